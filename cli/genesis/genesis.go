@@ -0,0 +1,61 @@
+// Package genesis implements the `saiya genesis` CLI commands for
+// generating and inspecting a chain's genesis.json before first run.
+package genesis
+
+import (
+	"github.com/multiverse-dev/saiya/pkg/core"
+	"github.com/multiverse-dev/saiya/pkg/core/native"
+	"github.com/urfave/cli"
+)
+
+// NewCommands returns the `genesis` command tree.
+func NewCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:  "genesis",
+			Usage: "work with a chain's genesis.json",
+			Subcommands: []cli.Command{
+				{
+					Name:      "dump",
+					Usage:     "write a sample genesis.json",
+					ArgsUsage: "<out-path>",
+					Action:    dumpGenesis,
+				},
+				{
+					Name:      "init",
+					Usage:     "validate a genesis.json by loading it",
+					ArgsUsage: "<path>",
+					Action:    initGenesis,
+				},
+			},
+		},
+	}
+}
+
+func dumpGenesis(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return cli.NewExitError("out-path is required", 1)
+	}
+	g := &core.Genesis{
+		Contracts: []core.GenesisContract{
+			{Address: native.DesignationAddress},
+			{Address: native.PolicyAddress},
+			{Address: native.SAIAddress},
+			{Address: native.ManagementAddress},
+		},
+	}
+	if err := g.Write(ctx.Args().First()); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
+
+func initGenesis(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return cli.NewExitError("path is required", 1)
+	}
+	if _, err := core.LoadGenesis(ctx.Args().First()); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
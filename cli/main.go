@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 
+	"github.com/multiverse-dev/saiya/cli/genesis"
 	"github.com/multiverse-dev/saiya/cli/native"
 	"github.com/multiverse-dev/saiya/cli/query"
 	"github.com/multiverse-dev/saiya/cli/server"
@@ -33,6 +34,7 @@ func newApp() *cli.App {
 	ctl.Commands = append(ctl.Commands, vm.NewCommands()...)
 	ctl.Commands = append(ctl.Commands, query.NewCommands()...)
 	ctl.Commands = append(ctl.Commands, native.NewCommands()...)
+	ctl.Commands = append(ctl.Commands, genesis.NewCommands()...)
 	ctl.Commands = append(ctl.Commands, utils.NewCommands()...)
 	return ctl
 }
@@ -0,0 +1,78 @@
+package native
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/cli/options"
+	"github.com/multiverse-dev/saiya/cli/wallet"
+	"github.com/multiverse-dev/saiya/pkg/core/native"
+	"github.com/multiverse-dev/saiya/pkg/core/native/nativenames"
+	"github.com/urfave/cli"
+)
+
+func newSaiCommands() []cli.Command {
+	flags := append(options.RPC, wallet.WalletPathFlag)
+	return []cli.Command{
+		{
+			Name:      "approve",
+			Usage:     "approve spender to transfer SAI from the signer's balance",
+			ArgsUsage: "<spender> <amount>",
+			Action:    approveSai,
+			Flags:     flags,
+		},
+		{
+			Name:      "transferfrom",
+			Usage:     "transfer SAI from one address to another using an existing allowance",
+			ArgsUsage: "<from> <to> <amount>",
+			Action:    transferFromSai,
+			Flags:     flags,
+		},
+	}
+}
+
+func approveSai(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) < 2 {
+		return cli.NewExitError(fmt.Errorf("please input spender and amount"), 1)
+	}
+	spender := common.HexToAddress(args.Get(0))
+	amount, ok := big.NewInt(0).SetString(args.Get(1), 10)
+	if !ok {
+		return cli.NewExitError(fmt.Errorf("invalid amount %s", args.Get(1)), 1)
+	}
+	return callSai(ctx, "approve", spender, amount)
+}
+
+func transferFromSai(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) < 3 {
+		return cli.NewExitError(fmt.Errorf("please input from, to and amount"), 1)
+	}
+	from := common.HexToAddress(args.Get(0))
+	to := common.HexToAddress(args.Get(1))
+	amount, ok := big.NewInt(0).SetString(args.Get(2), 10)
+	if !ok {
+		return cli.NewExitError(fmt.Errorf("invalid amount %s", args.Get(2)), 1)
+	}
+	return callSai(ctx, "transferFrom", from, to, amount)
+}
+
+// callSai packs method/args against the SAI native contract's ABI and sends
+// them the same way callPolicy does for PolicyContract: as a
+// committee-signed transaction to the contract's fixed address. Read-only
+// methods (balanceOf, allowance, totalSupply, ...) aren't exposed here
+// because, unlike isBlocked for PolicyContract, there's no typed RPC client
+// method for them in this snapshot to call through.
+func callSai(ctx *cli.Context, method string, args ...interface{}) error {
+	pabi, err := getNativeContract(ctx, nativenames.Sai)
+	if err != nil {
+		return err
+	}
+	data, err := pabi.Pack(method, args...)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't pack inputs for %s: %w", method, err), 1)
+	}
+	return makeCommitteeTx(ctx, native.SAIAddress, data)
+}
@@ -0,0 +1,54 @@
+package smartcontract
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/multiverse-dev/saiya/pkg/compiler/solidity"
+	"github.com/urfave/cli"
+)
+
+// contractCompile runs solc over the given Solidity sources and writes out
+// <name>.abi.json and <name>.bin artifacts into the --out directory (or the
+// current directory if --out isn't given).
+func contractCompile(ctx *cli.Context) error {
+	args := []string(ctx.Args())
+	if len(args) == 0 {
+		return cli.NewExitError(errNoSourceFiles, 1)
+	}
+
+	cc := solidity.NewCompiler(ctx.String("solc"))
+	contracts, err := cc.Compile(args...)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	outDir := ctx.String("out")
+	if outDir == "" {
+		outDir = "."
+	}
+
+	for _, ctr := range contracts {
+		base := filepath.Join(outDir, ctr.Name)
+		if err := ioutil.WriteFile(base+".abi.json", ctr.ABI, 0644); err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to write abi for %s: %w", ctr.Name, err), 1)
+		}
+		if err := ioutil.WriteFile(base+".bin", []byte(ctr.Bin), 0644); err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to write bin for %s: %w", ctr.Name, err), 1)
+		}
+	}
+	return nil
+}
+
+var errNoSourceFiles = fmt.Errorf("at least one .sol source file must be given")
+
+// abiFromCombined pulls the raw ABI JSON back out of a compiled contract,
+// failing if solc's output for it isn't valid JSON.
+func abiFromCombined(c *solidity.Contract) (json.RawMessage, error) {
+	if !json.Valid(c.ABI) {
+		return nil, fmt.Errorf("contract %s: invalid abi in solc output", c.Name)
+	}
+	return c.ABI, nil
+}
@@ -0,0 +1,69 @@
+package smartcontract
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/multiverse-dev/saiya/pkg/compiler/solidity"
+	"github.com/multiverse-dev/saiya/pkg/rpc/client"
+	"github.com/multiverse-dev/saiya/pkg/wallet"
+	"github.com/urfave/cli"
+)
+
+// contractDeploy compiles a single Solidity source, builds a deployment
+// transaction carrying the resulting bytecode, signs it with the account
+// unlocked from --wallet and submits it through the configured node,
+// printing the resulting contract address once it's mined.
+func contractDeploy(ctx *cli.Context) error {
+	args := []string(ctx.Args())
+	if len(args) != 1 {
+		return cli.NewExitError(fmt.Errorf("deploy expects exactly one .sol source file"), 1)
+	}
+
+	cc := solidity.NewCompiler(ctx.String("solc"))
+	contracts, err := cc.Compile(args[0])
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	name := ctx.String("contract")
+	var target *solidity.Contract
+	if name != "" {
+		for key, c := range contracts {
+			if c.Name == name || key == name {
+				target = c
+				break
+			}
+		}
+	} else if len(contracts) == 1 {
+		for _, c := range contracts {
+			target = c
+		}
+	}
+	if target == nil {
+		return cli.NewExitError(fmt.Errorf("use --contract to pick one of the %d compiled contracts", len(contracts)), 1)
+	}
+
+	bin, err := hex.DecodeString(target.Bin)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("invalid bytecode for %s: %w", target.Name, err), 1)
+	}
+
+	w, err := wallet.NewWalletFromFile(ctx.GlobalString("wallet"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	c, err := client.New(ctx.GlobalString("rpc-endpoint"), client.Options{})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	addr, txHash, err := c.DeployContract(w, bin)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to deploy %s: %w", target.Name, err), 1)
+	}
+
+	fmt.Printf("Contract %s deployed at %s (tx %s)\n", target.Name, addr.Hex(), txHash.Hex())
+	return nil
+}
@@ -2,298 +2,292 @@ package smartcontract
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
-	"html/template"
 	"io/ioutil"
 	"os"
-	"reflect"
 	"strings"
+	"text/template"
 
-	"github.com/nspcc-dev/neo-go/internal/random"
-	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
-	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
-	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/urfave/cli"
 )
 
 const srcTmpl = `
 {{- define "HEADER" -}}
 // {{.Name}} {{.Comment}}
-func (c *Client) {{.Name}}({{range $index, $arg := .Arguments -}}
+func (c *Client) {{.Name}}({{range $index, $arg := .Inputs -}}
 	{{- if ne $index 0}}, {{end}}
-		{{- .Name}} {{scTypeToGo .Type}}
-	{{- end}}) {{if .ReturnType }}({{ .ReturnType }}, error){{else}}error{{end}} 
+		{{- .Name}} {{.GoType}}
+	{{- end}}) {{if .Outputs}}({{.OutputTypes}}, error){{else}}error{{end}}
 {{- end -}}
 
-{{- define "ARGS" -}}
-	args := make([]smartcontract.Parameter, {{ len .Arguments }})
-	{{range $index, $arg := .Arguments -}}
-	args[{{$index}}] = smartcontract.Parameter{Type: {{ scType $arg.Type }}, Value: {{ scName $arg.Type $arg.Name -}} }
-	{{end}}
-{{- end -}}
-
-{{- define "CHECKRETURN" -}}
+{{- define "PACK" -}}
+	data, err := c.abi.Pack("{{.Raw}}"{{range .Inputs}}, {{.Name}}{{end}})
 	if err != nil {
-		return {{if .ReturnType }}{{ .ReturnValue }}, {{end}}err
+		return {{.ZeroReturn}}err
 	}
 {{- end -}}
 
-{{- define "SAFE" -}}
+{{- define "CALL" -}}
 {{ template "HEADER" . }} {
-	{{ if .Arguments }}{{ template "ARGS" . }}{{- else -}}{{end}}
-	result, err := (*client.Client)(c).InvokeFunction(contractHash, "
-		{{- lowerFirst .Name }}", {{if .Arguments}}args{{else}}nil{{end}}, nil)
-	{{ template "CHECKRETURN" . }}
-
-	{{if .ReturnType -}}
-	err = client.GetInvocationError(result)
-	{{ template "CHECKRETURN" . }}
-
-	return {{ .Converter }}(result.Stack)
+	{{ template "PACK" . }}
+	out, err := c.client.Call(contractAddress, data)
+	if err != nil {
+		return {{.ZeroReturn}}err
+	}
+	{{if .Outputs -}}
+	res, err := c.abi.Unpack("{{.Raw}}", out)
+	if err != nil {
+		return {{.ZeroReturn}}err
+	}
+	return {{.OutputCast}}, nil
 	{{- else -}}
-	return client.GetInvocationError(result)
+	return nil
 	{{- end}}
 }
 {{- end -}}
+
+{{- define "SEND" -}}
+{{ template "HEADER" . }} {
+	{{ template "PACK" . }}
+	return c.client.SendTransaction(c.wallet, &contractAddress, data)
+}
+{{- end -}}
 package {{.PackageName}}
 
 import (
-	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
-	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
-	"github.com/nspcc-dev/neo-go/pkg/util"
-{{range $m, $key := .Imports}}	"{{ $m }}"
-{{end}})
+	"strings"
 
-var contractHash = {{ printf "%#v" .Hash }}
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/multiverse-dev/saiya/pkg/rpc/client"
+	"github.com/multiverse-dev/saiya/pkg/wallet"
+)
 
-// Client is a wrapper over RPC-client mirroring methods of smartcontract.
-type Client client.Client
-{{range $m := .SafeMethods}}
-{{template "SAFE" $m }}
-{{end}}`
+var contractAddress = common.HexToAddress("{{.AddressHex}}")
 
-func printValue(v interface{}) string {
-	if v == nil {
-		return "nil"
-	}
-	rv := reflect.ValueOf(v)
-	switch rv.Kind() {
-	case reflect.Map, reflect.Interface, reflect.Slice:
-		if rv.IsNil() {
-			return "nil"
-		}
-	case reflect.String:
-		return "``"
-	}
-	return fmt.Sprintf("%#v", v)
+const rawABI = ` + "`" + `{{.RawABI}}` + "`" + `
+
+// Client is a typed wrapper over the RPC client for the contract's ABI.
+type Client struct {
+	client *client.Client
+	wallet *wallet.Wallet
+	abi    abi.ABI
 }
 
-func printType(v interface{}) string {
-	if v == nil {
-		return "interface{}"
+// NewClient returns a Client bound to contractAddress using c for RPC access.
+// w is used to sign and submit state-mutating calls and may be nil for
+// read-only usage.
+func NewClient(c *client.Client, w *wallet.Wallet) (*Client, error) {
+	parsed, err := abi.JSON(strings.NewReader(rawABI))
+	if err != nil {
+		return nil, err
 	}
-	rv := reflect.ValueOf(v)
-	switch rv.Kind() {
-	case reflect.Map, reflect.Interface, reflect.Slice:
-		if rv.IsNil() {
-			return "interface{}"
-		}
+	return &Client{client: c, wallet: w, abi: parsed}, nil
+}
+{{range $m := .ViewMethods}}
+{{template "CALL" $m }}
+{{end}}
+{{range $m := .SendMethods}}
+{{template "SEND" $m }}
+{{end}}
+{{range $e := .Events}}
+// Parse{{$e.Name}} decodes a {{$e.Raw}} event from a matching log entry.
+func (c *Client) Parse{{$e.Name}}(log types.Log) ({{range $index, $arg := $e.Inputs}}{{if ne $index 0}}, {{end}}{{.GoType}}{{end}}, error) {
+	event := new(struct {
+{{range $e.Inputs}}		{{.Name}} {{.GoType}}
+{{end}}	})
+	if err := c.abi.UnpackIntoInterface(event, "{{$e.Raw}}", log.Data); err != nil {
+		return {{range $index, $arg := $e.Inputs}}{{if ne $index 0}}, {{end}}{{.ZeroValue}}{{end}}, err
 	}
-	return fmt.Sprintf("%T", v)
+	return {{range $index, $arg := $e.Inputs}}{{if ne $index 0}}, {{end}}event.{{.Name}}{{end}}, nil
 }
+{{end}}`
 
-func scType(s smartcontract.ParamType) string {
-	switch s {
-	case smartcontract.AnyType:
-		return "smartcontract.AnyType"
-	case smartcontract.BoolType:
-		return "smartcontract.BoolType"
-	case smartcontract.IntegerType:
-		return "smartcontract.IntegerType"
-	case smartcontract.ByteArrayType:
-		return "smartcontract.ByteArrayType"
-	case smartcontract.StringType:
-		return "smartcontract.StringType"
-	case smartcontract.Hash160Type:
-		return "smartcontract.Hash160Type"
-	case smartcontract.Hash256Type:
-		return "smartcontract.Hash256Type"
-	case smartcontract.PublicKeyType:
-		return "smartcontract.PublicKeyType"
-	case smartcontract.SignatureType:
-		return "smartcontract.SignatureType"
-	case smartcontract.ArrayType:
-		return "smartcontract.ArrayType"
-	case smartcontract.MapType:
-		return "smartcontract.MapType"
-	case smartcontract.InteropInterfaceType:
-		return "smartcontract.InteropInterfaceType"
-	case smartcontract.VoidType:
-		return ""
-	default:
-		return "smartcontract.AnyType"
+func upperFirst(s string) string {
+	if s == "" {
+		return s
 	}
+	return strings.ToUpper(s[0:1]) + s[1:]
 }
 
-func scName(typ smartcontract.ParamType, name string) string {
-	switch typ {
-	case smartcontract.Hash160Type, smartcontract.Hash256Type:
-		return name + ".BytesBE()"
-	default:
-		return name
-	}
+// argTmpl describes a single ABI argument as it is rendered into the
+// generated source.
+type argTmpl struct {
+	Name      string
+	GoType    string
+	ZeroValue string
+}
+
+// methodTmpl describes a single ABI method as it is rendered into the
+// generated source.
+type methodTmpl struct {
+	Name        string
+	Raw         string
+	Comment     string
+	Inputs      []argTmpl
+	Outputs     []argTmpl
+	OutputTypes string
+	OutputCast  string
+	ZeroReturn  string
+}
+
+// eventTmpl describes a single ABI event as it is rendered into the
+// generated source.
+type eventTmpl struct {
+	Name      string
+	Raw       string
+	Inputs    []argTmpl
+	ZeroValue string
+}
+
+type contractTmpl struct {
+	PackageName string
+	AddressHex  string
+	RawABI      string
+	ViewMethods []methodTmpl
+	SendMethods []methodTmpl
+	Events      []eventTmpl
 }
 
-func scTypeToGo(typ smartcontract.ParamType) (string, string, string) {
-	switch typ {
-	case smartcontract.AnyType, smartcontract.InteropInterfaceType:
-		return "interface{}", "nil", "client.TopItemFromStack"
-	case smartcontract.BoolType:
-		return "bool", "false", "client.TopBoolFromStack"
-	case smartcontract.IntegerType:
-		return "int64", "0", "client.TopIntFromStack"
-	case smartcontract.ByteArrayType, smartcontract.SignatureType, smartcontract.PublicKeyType:
-		return "[]byte", "nil", "client.TopBytesFromStack"
-	case smartcontract.StringType:
-		return "string", "``", "client.TopStringFromStack"
-	case smartcontract.Hash160Type:
-		return "util.Uint160", "util.Uint160{}", "client.TopUint160FromStack"
-	case smartcontract.Hash256Type:
-		return "util.Uint256", "util.Uint256{}", "client.TopUint256FromStack"
-	case smartcontract.ArrayType:
-		return "[]stackitem.Item", "nil", "client.TopArrayFromStack"
-	case smartcontract.MapType:
-		return "*stackitem.Map", "nil", "client.TopMapFromStack"
-	case smartcontract.VoidType:
-		return "", "", ""
+func zeroValueFor(goType string) string {
+	switch {
+	case strings.HasPrefix(goType, "*"), strings.HasPrefix(goType, "[]"):
+		return "nil"
+	case goType == "bool":
+		return "false"
+	case goType == "string":
+		return "``"
+	case strings.HasPrefix(goType, "int") || strings.HasPrefix(goType, "uint"):
+		return "0"
 	default:
-		panic(fmt.Sprintf("unexpected type: %s", typ))
+		return goType + "{}"
 	}
 }
 
-func upperFirst(s string) string {
-	return strings.ToUpper(s[0:1]) + s[1:]
+func buildEventTmpl(e abi.Event) eventTmpl {
+	evt := eventTmpl{Name: upperFirst(e.Name), Raw: e.Name}
+	for i, in := range e.Inputs {
+		name := in.Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		t := in.Type.GetType().String()
+		evt.Inputs = append(evt.Inputs, argTmpl{Name: upperFirst(name), GoType: t, ZeroValue: zeroValueFor(t)})
+	}
+	return evt
 }
-func lowerFirst(s string) string {
-	return strings.ToLower(s[0:1]) + s[1:]
+
+func buildMethodTmpl(m abi.Method) methodTmpl {
+	mtd := methodTmpl{
+		Name:    upperFirst(m.Name),
+		Raw:     m.Name,
+		Comment: fmt.Sprintf("calls the `%s` method of the contract.", m.Name),
+	}
+	for i, in := range m.Inputs {
+		name := in.Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		mtd.Inputs = append(mtd.Inputs, argTmpl{Name: name, GoType: in.Type.GetType().String()})
+	}
+	var outTypes, outCasts []string
+	for i, out := range m.Outputs {
+		t := out.Type.GetType().String()
+		outTypes = append(outTypes, t)
+		outCasts = append(outCasts, fmt.Sprintf("res[%d].(%s)", i, t))
+		mtd.Outputs = append(mtd.Outputs, argTmpl{GoType: t})
+	}
+	mtd.OutputTypes = strings.Join(outTypes, ", ")
+	mtd.OutputCast = strings.Join(outCasts, ", ")
+	if len(mtd.Outputs) > 0 {
+		mtd.ZeroReturn = strings.Repeat("nil, ", len(mtd.Outputs))
+	}
+	return mtd
 }
 
-func Generate(arg contractTmpl) (string, error) {
-	fm := template.FuncMap{
-		"lowerFirst": lowerFirst,
-		"scType":     scType,
-		"scName":     scName,
-		"scTypeToGo": func(s smartcontract.ParamType) string {
-			typ, _, _ := scTypeToGo(s)
-			return typ
-		},
-		"printValue": printValue,
-		"printType":  printType,
+// Generate renders a typed Go client for the given Solidity ABI.
+func Generate(packageName string, address common.Address, parsedABI abi.ABI, rawABI string) (string, error) {
+	ctr := contractTmpl{
+		PackageName: packageName,
+		AddressHex:  address.Hex(),
+		RawABI:      rawABI,
 	}
-	tmp := template.New("test").Funcs(fm)
-	tmp, err := tmp.Parse(srcTmpl)
+	for _, m := range parsedABI.Methods {
+		mtd := buildMethodTmpl(m)
+		switch m.StateMutability {
+		case "view", "pure":
+			ctr.ViewMethods = append(ctr.ViewMethods, mtd)
+		default:
+			ctr.SendMethods = append(ctr.SendMethods, mtd)
+		}
+	}
+	for _, e := range parsedABI.Events {
+		ctr.Events = append(ctr.Events, buildEventTmpl(e))
+	}
+
+	tmp, err := template.New("contract").Parse(srcTmpl)
 	if err != nil {
 		return "", err
 	}
 	b := bytes.NewBuffer(nil)
-	if err := tmp.Execute(b, arg); err != nil {
+	if err := tmp.Execute(b, ctr); err != nil {
 		return "", err
 	}
 	return b.String(), nil
 }
 
-type (
-	contractTmpl struct {
-		PackageName string
-		Imports     map[string]struct{}
-		Hash        util.Uint160
-		SafeMethods []methodTmpl
-	}
-
-	methodTmpl struct {
-		Name        string
-		Comment     string
-		Arguments   []manifest.Parameter
-		ReturnType  string
-		ReturnValue string
-		Converter   string
-	}
-)
+var errNoABIFile = fmt.Errorf("abi file is mandatory and should be passed using the --abi flag")
 
-// contractGenerateWrapper deploys contract.
+// contractGenerateWrapper reads a Solidity ABI (and optional bytecode) and
+// writes a typed Go client wrapping the Saiya RPC client.
 func contractGenerateWrapper(ctx *cli.Context) error {
-	manifestFile := ctx.String("manifest")
-	if len(manifestFile) == 0 {
-		return cli.NewExitError(errNoManifestFile, 1)
+	abiFile := ctx.String("abi")
+	if len(abiFile) == 0 {
+		return cli.NewExitError(errNoABIFile, 1)
 	}
-
-	manifestBytes, err := ioutil.ReadFile(manifestFile)
+	abiBytes, err := ioutil.ReadFile(abiFile)
 	if err != nil {
-		return cli.NewExitError(fmt.Errorf("failed to read manifest file: %w", err), 1)
+		return cli.NewExitError(fmt.Errorf("failed to read abi file: %w", err), 1)
 	}
 
-	m := &manifest.Manifest{}
-	err = json.Unmarshal(manifestBytes, m)
+	parsed, err := abi.JSON(bytes.NewReader(abiBytes))
 	if err != nil {
-		return cli.NewExitError(fmt.Errorf("failed to restore manifest file: %w", err), 1)
+		return cli.NewExitError(fmt.Errorf("failed to parse abi file: %w", err), 1)
 	}
 
-	ctr := contractTmpl{
-		PackageName: ctx.String("package"),
-		Imports:     map[string]struct{}{},
-		Hash:        random.Uint160(),
+	var address common.Address
+	if addrStr := ctx.String("address"); len(addrStr) > 0 {
+		address = common.HexToAddress(addrStr)
 	}
 
-	converters := make(map[string]string)
-	for _, s := range ctx.StringSlice("return") {
-		ss := strings.SplitN(s, ":", 2)
-		if len(ss) != 2 {
-			return cli.NewExitError(fmt.Errorf("invalid return override: %s", s), 1)
-		}
-		converters[ss[0]] = ss[1]
-	}
-
-	for _, m := range m.ABI.Methods {
-		if m.Name[0] == '_' || !m.Safe {
-			continue
-		}
-		typ, val, conv := scTypeToGo(m.ReturnType)
-		if m.ReturnType == smartcontract.MapType || m.ReturnType == smartcontract.ArrayType {
-			ctr.Imports["github.com/nspcc-dev/neo-go/pkg/vm/stackitem"] = struct{}{}
-		}
-		mtd := methodTmpl{
-			Name:        upperFirst(m.Name),
-			ReturnType:  typ,
-			ReturnValue: val,
-			Comment:     fmt.Sprintf("invokes `%s` method of contract.", m.Name),
-			Arguments:   m.Parameters,
-			Converter:   conv,
-		}
-		if c, ok := converters[m.Name]; ok {
-			switch c {
-			case "iterator":
-				mtd.Converter = "client.TopIterableFromStack"
-				mtd.ReturnType = "[]interface{}"
-				mtd.ReturnValue = "nil"
-			case "keys":
-				mtd.Converter = "client.TopPublicKeysFromStack"
-				mtd.ReturnType = "keys.PublicKeys"
-				mtd.ReturnValue = "nil"
-			}
-		} else {
-		}
-		ctr.SafeMethods = append(ctr.SafeMethods, mtd)
+	packageName := ctx.String("package")
+	if len(packageName) == 0 {
+		packageName = "contract"
 	}
 
-	s, err := Generate(ctr)
+	s, err := Generate(packageName, address, parsed, string(abiBytes))
 	if err != nil {
 		return cli.NewExitError(fmt.Errorf("error during generation: %w", err), 1)
 	}
 
-	err = ioutil.WriteFile(ctx.String("out"), []byte(s), os.ModePerm)
-	if err != nil {
+	out := ctx.String("out")
+	if len(out) == 0 {
+		out = packageName + ".go"
+	}
+	if err := ioutil.WriteFile(out, []byte(s), os.ModePerm); err != nil {
 		return cli.NewExitError(fmt.Errorf("error during write: %w", err), 1)
 	}
+
+	// --bin is accepted for symmetry with the compiler pipeline's artifacts
+	// but is only required by the deploy flow, not by client generation.
+	if binFile := ctx.String("bin"); len(binFile) > 0 {
+		if _, err := ioutil.ReadFile(binFile); err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to read bin file: %w", err), 1)
+		}
+	}
+
 	return nil
 }
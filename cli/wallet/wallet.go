@@ -17,7 +17,8 @@ import (
 	"github.com/multiverse-dev/saiya/pkg/crypto/keys"
 	"github.com/multiverse-dev/saiya/pkg/encoding/address"
 	"github.com/multiverse-dev/saiya/pkg/wallet"
-	"github.com/urfave/cli"
+	"github.com/multiverse-dev/saiya/pkg/wallet/hardware"
+	"github.com/urfave/cli/v2"
 )
 
 var (
@@ -27,149 +28,216 @@ var (
 )
 
 var (
-	WalletPathFlag = cli.StringFlag{
-		Name:  "wallet, w",
-		Usage: "Target location of the wallet file ('-' to read from stdin).",
+	WalletPathFlag = &cli.StringFlag{
+		Name:    "wallet",
+		Aliases: []string{"w"},
+		Usage:   "Target location of the wallet file ('-' to read from stdin).",
+		EnvVars: []string{"SAIYA_WALLET"},
 	}
-	keyFlag = cli.StringFlag{
+	keyFlag = &cli.StringFlag{
 		Name:  "key",
 		Usage: "private key to import",
 	}
-	pswFlag = cli.StringFlag{
-		Name:  "psw",
-		Usage: "password to encypt private key",
+	pswFlag = &cli.StringFlag{
+		Name:    "psw",
+		Usage:   "password to encypt private key",
+		EnvVars: []string{"SAIYA_WALLET_PASSWORD"},
 	}
-	decryptFlag = flags.AddressFlag{
-		Name:  "decrypt, d",
-		Usage: "Decrypt encrypted keys.",
+	hwFlag = &cli.StringFlag{
+		Name:  "hw",
+		Usage: "import a hardware-wallet-backed account instead of a local key (ledger or trezor)",
 	}
-	outFlag = cli.StringFlag{
+	hwPathFlag = &cli.StringFlag{
+		Name:  "path",
+		Usage: "BIP-44 derivation path for --hw, e.g. m/44'/60'/0'/0/0",
+	}
+	decryptFlag = &cli.GenericFlag{
+		Name:    "decrypt",
+		Aliases: []string{"d"},
+		Usage:   "Decrypt encrypted keys.",
+		Value:   new(flags.Address),
+	}
+	outFlag = &cli.StringFlag{
 		Name:  "out",
 		Usage: "file to put JSON transaction to",
 	}
-	inFlag = cli.StringFlag{
+	inFlag = &cli.StringFlag{
 		Name:  "in",
 		Usage: "file with JSON transaction",
 	}
-	FromAddrFlag = flags.AddressFlag{
+	FromAddrFlag = &cli.GenericFlag{
 		Name:  "from",
 		Usage: "Address to send an asset from",
+		Value: new(flags.Address),
 	}
-	toAddrFlag = flags.AddressFlag{
+	toAddrFlag = &cli.GenericFlag{
 		Name:  "to",
 		Usage: "Address to send an asset to",
+		Value: new(flags.Address),
 	}
-	forceFlag = cli.BoolFlag{
+	forceFlag = &cli.BoolFlag{
 		Name:  "force",
 		Usage: "Do not ask for a confirmation",
 	}
+	// passwordFlag lets automated callers (CI, services) supply a wallet
+	// password without piping it through stdin or a --wallet-config file.
+	// It's consulted by password() ahead of the PasswordProvider and the
+	// interactive prompt.
+	passwordFlag = &cli.StringFlag{
+		Name:    "password",
+		Usage:   "wallet password (prefer SAIYA_WALLET_PASSWORD over passing this on the command line)",
+		EnvVars: []string{"SAIYA_WALLET_PASSWORD"},
+	}
 )
 
 // NewCommands returns 'wallet' command.
-func NewCommands() []cli.Command {
+func NewCommands() []*cli.Command {
 	listFlags := []cli.Flag{
 		WalletPathFlag,
+		WalletConfigFlag,
 	}
 	listFlags = append(listFlags, options.RPC...)
-	return []cli.Command{{
-		Name:  "wallet",
-		Usage: "create, open and manage a saiya wallet",
-		Subcommands: []cli.Command{
+	return []*cli.Command{{
+		Name:     "wallet",
+		Usage:    "create, open and manage a saiya wallet",
+		Before:   resolvePasswordProvider,
+		Category: "WALLET",
+		Subcommands: []*cli.Command{
 			{
-				Name:   "init",
-				Usage:  "create a new wallet",
-				Action: createWallet,
+				Name:     "init",
+				Aliases:  []string{"new"},
+				Usage:    "create a new wallet",
+				Category: "accounts",
+				Action:   createWallet,
 				Flags: []cli.Flag{
 					WalletPathFlag,
-					cli.BoolFlag{
-						Name:  "account, a",
-						Usage: "Create a new account",
+					WalletConfigFlag,
+					&cli.BoolFlag{
+						Name:    "account",
+						Aliases: []string{"a"},
+						Usage:   "Create a new account",
 					},
 				},
 			},
 			{
-				Name:   "change-password",
-				Usage:  "change password for accounts",
-				Action: changePassword,
+				Name:     "change-password",
+				Aliases:  []string{"passwd"},
+				Usage:    "change password for accounts",
+				Category: "accounts",
+				Action:   changePassword,
 				Flags: []cli.Flag{
 					WalletPathFlag,
-					flags.AddressFlag{
-						Name:  "address, a",
-						Usage: "address to change password for",
+					WalletConfigFlag,
+					passwordFlag,
+					&cli.GenericFlag{
+						Name:    "address",
+						Aliases: []string{"a"},
+						Usage:   "address to change password for",
+						Value:   new(flags.Address),
 					},
 				},
 			},
 			{
-				Name:   "create",
-				Usage:  "add an account to the existing wallet",
-				Action: addAccount,
+				Name:     "create",
+				Aliases:  []string{"add"},
+				Usage:    "add an account to the existing wallet",
+				Category: "accounts",
+				Action:   addAccount,
 				Flags: []cli.Flag{
 					WalletPathFlag,
+					WalletConfigFlag,
+					keystoreFlag,
 				},
 			},
 			{
-				Name:   "dump",
-				Usage:  "check and dump an existing saiya wallet",
-				Action: dumpWallet,
+				Name:     "dump",
+				Usage:    "check and dump an existing saiya wallet",
+				Category: "accounts",
+				Action:   dumpWallet,
 				Flags: []cli.Flag{
 					WalletPathFlag,
+					WalletConfigFlag,
+					passwordFlag,
 					decryptFlag,
 				},
 			},
 			{
-				Name:   "dump-keys",
-				Usage:  "dump public keys for account",
-				Action: dumpKeys,
+				Name:     "dump-keys",
+				Aliases:  []string{"keys"},
+				Usage:    "dump public keys for account",
+				Category: "accounts",
+				Action:   dumpKeys,
 				Flags: []cli.Flag{
 					WalletPathFlag,
-					flags.AddressFlag{
-						Name:  "address, a",
-						Usage: "address to print public keys for",
+					WalletConfigFlag,
+					&cli.GenericFlag{
+						Name:    "address",
+						Aliases: []string{"a"},
+						Usage:   "address to print public keys for",
+						Value:   new(flags.Address),
 					},
 				},
 			},
 			{
 				Name:      "export",
 				Usage:     "export keys for address",
+				Category:  "accounts",
 				UsageText: "export --wallet <path> --decrypt <address>",
 				Action:    exportKeys,
 				Flags: []cli.Flag{
 					WalletPathFlag,
+					WalletConfigFlag,
+					passwordFlag,
 					decryptFlag,
 				},
 			},
 			{
-				Name:      "import",
-				Usage:     "import private key",
-				UsageText: "import --wallet <path> --key <privateKey> --psw <password> [--name <account_name>]",
-				Action:    importWallet,
+				Name:     "import",
+				Usage:    "import private key",
+				Category: "accounts",
+				UsageText: "import --wallet <path> --key <privateKey> --psw <password> [--name <account_name>]\n" +
+					"   import --wallet <path> --hw ledger|trezor --path m/44'/60'/0'/0/0 [--name <account_name>]",
+				Action: importWallet,
 				Flags: []cli.Flag{
 					WalletPathFlag,
+					WalletConfigFlag,
 					keyFlag,
 					pswFlag,
-					cli.StringFlag{
-						Name:  "name, n",
-						Usage: "Optional account name",
+					hwFlag,
+					hwPathFlag,
+					keystoreFlag,
+					&cli.StringFlag{
+						Name:    "name",
+						Aliases: []string{"n"},
+						Usage:   "Optional account name",
 					},
 				},
 			},
 			{
 				Name:      "remove",
+				Aliases:   []string{"rm"},
 				Usage:     "remove an account from the wallet",
+				Category:  "accounts",
 				UsageText: "remove --wallet <path> [--force] --address <addr>",
 				Action:    removeAccount,
 				Flags: []cli.Flag{
 					WalletPathFlag,
+					WalletConfigFlag,
 					forceFlag,
-					flags.AddressFlag{
-						Name:  "address, a",
-						Usage: "Account address or hash in LE form to be removed",
+					keystoreFlag,
+					&cli.GenericFlag{
+						Name:    "address",
+						Aliases: []string{"a"},
+						Usage:   "Account address or hash in LE form to be removed",
+						Value:   new(flags.Address),
 					},
 				},
 			},
 			{
 				Name:      "list",
+				Aliases:   []string{"ls"},
 				Usage:     "list addresses in wallet",
+				Category:  "accounts",
 				UsageText: "list --wallet <path>",
 				Action:    listAddresses,
 				Flags:     listFlags,
@@ -177,33 +245,93 @@ func NewCommands() []cli.Command {
 			{
 				Name:        "gas",
 				Usage:       "work with native gas",
+				Category:    "tokens",
 				Subcommands: newNativeTokenCommands(),
 			},
 			{
-				Name:   "sign",
-				Usage:  "sign sign_context",
-				Action: sign,
+				Name:      "sign",
+				Usage:     "co-sign an offline/multisig sign context (tx, extensible payload or state root)",
+				Category:  "signing",
+				UsageText: "sign --wallet <path> --in <context.json> [--out <context.json>] [--submit] [<signer address>...]",
+				Action:    sign,
+				Flags: append([]cli.Flag{
+					WalletPathFlag,
+					WalletConfigFlag,
+					passwordFlag,
+					inFlag,
+					outFlag,
+					&cli.BoolFlag{
+						Name:  "submit",
+						Usage: "submit the assembled transaction via RPC once enough signatures are collected (only valid for tx sign contexts)",
+					},
+				}, options.RPC...),
 			},
 		},
 	}}
 }
 
+// resolvePasswordProvider is the wallet command's Before hook: it parses
+// --wallet-config once up front (instead of once per subcommand) and
+// stashes the resulting PasswordProvider on the app so every subcommand's
+// openWallet/ReadWallet call picks up the same instance.
+func resolvePasswordProvider(ctx *cli.Context) error {
+	provider, err := parsePasswordProvider(ctx)
+	if err != nil {
+		return cli.Exit(err, 1)
+	}
+	if ctx.App.Metadata == nil {
+		ctx.App.Metadata = map[string]interface{}{}
+	}
+	ctx.App.Metadata[walletPasswordProviderKey] = provider
+	return nil
+}
+
+// GetSignersFromContext parses ctx's positional arguments as a list of
+// signer addresses, letting `wallet sign` be restricted to a subset of
+// the wallet's accounts instead of attempting every account that matches
+// a PublicKey in the sign context.
+func GetSignersFromContext(ctx *cli.Context) ([]common.Address, error) {
+	args := ctx.Args()
+	if !args.Present() {
+		return nil, nil
+	}
+	signers := make([]common.Address, args.Len())
+	for i, a := range args.Slice() {
+		var addr flags.Address
+		if err := addr.Set(a); err != nil {
+			return nil, cli.Exit(fmt.Errorf("invalid signer address %q: %w", a, err), 1)
+		}
+		signers[i] = addr.Address()
+	}
+	return signers, nil
+}
+
+func containsAddress(addrs []common.Address, addr common.Address) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
 func listAddresses(ctx *cli.Context) error {
-	wall, err := openWallet(ctx.String("wallet"))
+	wall, _, err := openWallet(ctx)
 	if err != nil {
-		return cli.NewExitError(err, 1)
+		return cli.Exit(err, 1)
 	}
+	defer wall.Close()
 	gctx, cancel := options.GetTimeoutContext(ctx)
 	defer cancel()
 
 	c, err := options.GetRPCClient(gctx, ctx)
 	if err != nil {
-		return cli.NewExitError(err, 1)
+		return cli.Exit(err, 1)
 	}
 	for _, acc := range wall.Accounts {
 		bal, err := c.Eth_GetBalance(acc.Address)
 		if err != nil {
-			return cli.NewExitError(fmt.Errorf("could not get balance of account %s, err: %w", acc.Address, err), 1)
+			return cli.Exit(fmt.Errorf("could not get balance of account %s, err: %w", acc.Address, err), 1)
 		}
 		fmt.Fprintf(ctx.App.Writer, "%s SAIYA: %s\n", acc.Address, bal)
 	}
@@ -211,22 +339,27 @@ func listAddresses(ctx *cli.Context) error {
 }
 
 func changePassword(ctx *cli.Context) error {
-	wall, err := openWallet(ctx.String("wallet"))
+	wall, provider, err := openWallet(ctx)
 	if err != nil {
-		return cli.NewExitError(err, 1)
+		return cli.Exit(err, 1)
 	}
+	defer wall.Close()
 	addrFlag := ctx.Generic("address").(*flags.Address)
 	if addrFlag.IsSet {
 		// Check for account presence first before asking for password.
 		acc := wall.GetAccount(addrFlag.Address())
 		if acc == nil {
-			return cli.NewExitError("account is missing", 1)
+			return cli.Exit("account is missing", 1)
 		}
 	}
 
-	oldPass, err := input.ReadPassword("Enter password > ")
+	var oldPassAddr common.Address
+	if addrFlag.IsSet {
+		oldPassAddr = addrFlag.Address()
+	}
+	oldPass, err := password(ctx, provider, oldPassAddr, "Enter password > ")
 	if err != nil {
-		return cli.NewExitError(fmt.Errorf("error reading old password: %w", err), 1)
+		return cli.Exit(fmt.Errorf("error reading old password: %w", err), 1)
 	}
 
 	for i := range wall.Accounts {
@@ -235,13 +368,13 @@ func changePassword(ctx *cli.Context) error {
 		}
 		err := wall.Accounts[i].Decrypt(oldPass, wall.Scrypt)
 		if err != nil {
-			return cli.NewExitError(fmt.Errorf("unable to decrypt account %s: %w", wall.Accounts[i].Address, err), 1)
+			return cli.Exit(fmt.Errorf("unable to decrypt account %s: %w", wall.Accounts[i].Address, err), 1)
 		}
 	}
 
 	pass, err := readNewPassword()
 	if err != nil {
-		return cli.NewExitError(fmt.Errorf("error reading new password: %w", err), 1)
+		return cli.Exit(fmt.Errorf("error reading new password: %w", err), 1)
 	}
 	for i := range wall.Accounts {
 		if addrFlag.IsSet && wall.Accounts[i].Address != addrFlag.Address() {
@@ -249,42 +382,47 @@ func changePassword(ctx *cli.Context) error {
 		}
 		err := wall.Accounts[i].Encrypt(pass, wall.Scrypt)
 		if err != nil {
-			return cli.NewExitError(err, 1)
+			return cli.Exit(err, 1)
 		}
 	}
 	err = wall.Save()
 	if err != nil {
-		return cli.NewExitError(fmt.Errorf("error saving the wallet: %w", err), 1)
+		return cli.Exit(fmt.Errorf("error saving the wallet: %w", err), 1)
 	}
 	return nil
 }
 
 func addAccount(ctx *cli.Context) error {
-	wall, err := openWallet(ctx.String("wallet"))
+	wall, _, err := openWallet(ctx)
 	if err != nil {
-		return cli.NewExitError(err, 1)
+		return cli.Exit(err, 1)
 	}
 
 	defer wall.Close()
 
 	if err := createAccount(wall); err != nil {
-		return cli.NewExitError(err, 1)
+		return cli.Exit(err, 1)
+	}
+
+	if err := saveToKeystore(ctx, wall.Accounts[len(wall.Accounts)-1]); err != nil {
+		return cli.Exit(err, 1)
 	}
 
 	return nil
 }
 
 func exportKeys(ctx *cli.Context) error {
-	wall, err := ReadWallet(ctx.String("wallet"))
+	wall, provider, err := ReadWallet(ctx)
 	if err != nil {
-		return cli.NewExitError(err, 1)
+		return cli.Exit(err, 1)
 	}
+	defer wall.Close()
 
 	var addr common.Address
 
 	decrypt := ctx.Generic("decrypt").(*flags.Address)
 	if !decrypt.IsSet {
-		return cli.NewExitError(fmt.Errorf("missing address to decrypt"), 1)
+		return cli.Exit(fmt.Errorf("missing address to decrypt"), 1)
 	}
 	addr = decrypt.Address()
 
@@ -305,70 +443,96 @@ loop:
 		wifs = append(wifs, a.EncryptedWIF)
 	}
 	if len(wifs) == 0 {
-		return cli.NewExitError(fmt.Errorf("address not found"), 1)
+		return cli.Exit(fmt.Errorf("address not found"), 1)
 	}
 	for _, wif := range wifs {
-		pass, err := input.ReadPassword("Enter password > ")
+		pass, err := password(ctx, provider, addr, "Enter password > ")
 		if err != nil {
-			return cli.NewExitError(fmt.Errorf("error reading password: %w", err), 1)
+			return cli.Exit(fmt.Errorf("error reading password: %w", err), 1)
 		}
 
 		pk, err := keys.NEP2Decrypt(wif, pass, wall.Scrypt)
 		if err != nil {
-			return cli.NewExitError(err, 1)
+			return cli.Exit(err, 1)
 		}
 		fmt.Fprintln(ctx.App.Writer, hexutil.Encode(pk.Bytes()))
+		pk.Destroy()
 	}
 
 	return nil
 }
 
 func importWallet(ctx *cli.Context) error {
-	wall, err := openWallet(ctx.String("wallet"))
+	wall, _, err := openWallet(ctx)
 	if err != nil {
-		return cli.NewExitError(err, 1)
+		return cli.Exit(err, 1)
 	}
 	defer wall.Close()
-	b, err := hexutil.Decode(ctx.String("key"))
-	if err != nil {
-		return cli.NewExitError(err, 1)
-	}
-	key, err := keys.NewPrivateKeyFromBytes(b)
-	if err != nil {
-		return cli.NewExitError(err, 1)
-	}
-	acc := wallet.NewAccountFromPrivateKey(key)
-	if err != nil {
-		return cli.NewExitError(err, 1)
-	}
-	pass := ctx.String("psw")
-	if err := acc.Encrypt(pass, wall.Scrypt); err != nil {
-		return err
+
+	var acc *wallet.Account
+	if hw := ctx.String("hw"); hw != "" {
+		acc, err = importHardwareAccount(hw, ctx.String("path"))
+		if err != nil {
+			return cli.Exit(err, 1)
+		}
+	} else {
+		b, err := hexutil.Decode(ctx.String("key"))
+		if err != nil {
+			return cli.Exit(err, 1)
+		}
+		key, err := keys.NewPrivateKeyFromBytes(b)
+		if err != nil {
+			return cli.Exit(err, 1)
+		}
+		acc = wallet.NewAccountFromPrivateKey(key)
+		pass := ctx.String("psw")
+		if err := acc.Encrypt(pass, wall.Scrypt); err != nil {
+			return err
+		}
 	}
 	if acc.Label == "" {
 		acc.Label = ctx.String("name")
 	}
 	if err := addAccountAndSave(wall, acc); err != nil {
-		return cli.NewExitError(err, 1)
+		return cli.Exit(err, 1)
+	}
+	if err := saveToKeystore(ctx, acc); err != nil {
+		return cli.Exit(err, 1)
 	}
 
 	return nil
 }
 
+func importHardwareAccount(vendorName, path string) (*wallet.Account, error) {
+	if path == "" {
+		return nil, fmt.Errorf("--path is required with --hw")
+	}
+	var vendor hardware.Vendor
+	switch strings.ToLower(vendorName) {
+	case "ledger":
+		vendor = hardware.Ledger
+	case "trezor":
+		vendor = hardware.Trezor
+	default:
+		return nil, fmt.Errorf("unknown --hw vendor %q, expected ledger or trezor", vendorName)
+	}
+	return wallet.NewHardwareAccount(vendor, path)
+}
+
 func removeAccount(ctx *cli.Context) error {
-	wall, err := openWallet(ctx.String("wallet"))
+	wall, _, err := openWallet(ctx)
 	if err != nil {
-		return cli.NewExitError(err, 1)
+		return cli.Exit(err, 1)
 	}
 	defer wall.Close()
 
 	addr := ctx.Generic("address").(*flags.Address)
 	if !addr.IsSet {
-		return cli.NewExitError("valid account address must be provided", 1)
+		return cli.Exit("valid account address must be provided", 1)
 	}
 	acc := wall.GetAccount(addr.Address())
 	if acc == nil {
-		return cli.NewExitError("account wasn't found", 1)
+		return cli.Exit("account wasn't found", 1)
 	}
 
 	if !ctx.Bool("force") {
@@ -379,10 +543,13 @@ func removeAccount(ctx *cli.Context) error {
 	}
 
 	if err := wall.RemoveAccount(acc.Address.String()); err != nil {
-		return cli.NewExitError(fmt.Errorf("error on remove: %w", err), 1)
+		return cli.Exit(fmt.Errorf("error on remove: %w", err), 1)
 	}
 	if err := wall.Save(); err != nil {
-		return cli.NewExitError(fmt.Errorf("error while saving wallet: %w", err), 1)
+		return cli.Exit(fmt.Errorf("error while saving wallet: %w", err), 1)
+	}
+	if err := removeFromKeystore(ctx, acc.Address); err != nil {
+		return cli.Exit(err, 1)
 	}
 	return nil
 }
@@ -400,20 +567,21 @@ func askForConsent(w io.Writer) bool {
 }
 
 func dumpWallet(ctx *cli.Context) error {
-	wall, err := ReadWallet(ctx.String("wallet"))
+	wall, provider, err := ReadWallet(ctx)
 	if err != nil {
-		return cli.NewExitError(err, 1)
+		return cli.Exit(err, 1)
 	}
+	defer wall.Close()
 	if ctx.Bool("decrypt") {
-		pass, err := input.ReadPassword("Enter wallet password > ")
-		if err != nil {
-			return cli.NewExitError(fmt.Errorf("Error reading password: %w", err), 1)
-		}
 		for i := range wall.Accounts {
+			pass, err := password(ctx, provider, wall.Accounts[i].Address, "Enter wallet password > ")
+			if err != nil {
+				return cli.Exit(fmt.Errorf("Error reading password: %w", err), 1)
+			}
 			// Just testing the decryption here.
-			err := wall.Accounts[i].Decrypt(pass, wall.Scrypt)
+			err = wall.Accounts[i].Decrypt(pass, wall.Scrypt)
 			if err != nil {
-				return cli.NewExitError(err, 1)
+				return cli.Exit(err, 1)
 			}
 		}
 	}
@@ -422,17 +590,18 @@ func dumpWallet(ctx *cli.Context) error {
 }
 
 func dumpKeys(ctx *cli.Context) error {
-	wall, err := ReadWallet(ctx.String("wallet"))
+	wall, _, err := ReadWallet(ctx)
 	if err != nil {
-		return cli.NewExitError(err, 1)
+		return cli.Exit(err, 1)
 	}
+	defer wall.Close()
 	accounts := wall.Accounts
 
 	addrFlag := ctx.Generic("address").(*flags.Address)
 	if addrFlag.IsSet {
 		acc := wall.GetAccount(addrFlag.Address())
 		if acc == nil {
-			return cli.NewExitError("account is missing", 1)
+			return cli.Exit("account is missing", 1)
 		}
 		accounts = []*wallet.Account{acc}
 	}
@@ -446,7 +615,7 @@ func dumpKeys(ctx *cli.Context) error {
 		fmt.Fprintln(ctx.App.Writer, hex.EncodeToString(acc.PublicKey))
 		hasPrinted = true
 		if addrFlag.IsSet {
-			return cli.NewExitError(fmt.Errorf("unknown script type for address %s", address.AddressToBase58(addrFlag.Address())), 1)
+			return cli.Exit(fmt.Errorf("unknown script type for address %s", address.AddressToBase58(addrFlag.Address())), 1)
 		}
 	}
 	return nil
@@ -455,19 +624,20 @@ func dumpKeys(ctx *cli.Context) error {
 func createWallet(ctx *cli.Context) error {
 	path := ctx.String("wallet")
 	if len(path) == 0 {
-		return cli.NewExitError(errNoPath, 1)
+		return cli.Exit(errNoPath, 1)
 	}
 	wall, err := wallet.NewWallet(path)
 	if err != nil {
-		return cli.NewExitError(err, 1)
+		return cli.Exit(err, 1)
 	}
+	defer wall.Close()
 	if err := wall.Save(); err != nil {
-		return cli.NewExitError(err, 1)
+		return cli.Exit(err, 1)
 	}
 
 	if ctx.Bool("account") {
 		if err := createAccount(wall); err != nil {
-			return cli.NewExitError(err, 1)
+			return cli.Exit(err, 1)
 		}
 	}
 
@@ -512,28 +682,50 @@ func createAccount(wall *wallet.Wallet) error {
 	return wall.CreateAccount(name, phrase)
 }
 
-func openWallet(path string) (*wallet.Wallet, error) {
+// openWallet opens the wallet at --wallet for read/write and, if
+// --wallet-config is set, the PasswordProvider it describes.
+func openWallet(ctx *cli.Context) (*wallet.Wallet, PasswordProvider, error) {
+	path := ctx.String("wallet")
 	if len(path) == 0 {
-		return nil, errNoPath
+		return nil, nil, errNoPath
 	}
 	if path == "-" {
-		return nil, errNoStdin
+		return nil, nil, errNoStdin
 	}
-	return wallet.NewWalletFromFile(path)
+	w, err := wallet.NewWalletFromFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	provider, err := loadPasswordProvider(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return w, provider, nil
 }
 
-func ReadWallet(path string) (*wallet.Wallet, error) {
+// ReadWallet is like openWallet, but also accepts '-' to read the wallet
+// JSON from stdin (for commands that never write it back).
+func ReadWallet(ctx *cli.Context) (*wallet.Wallet, PasswordProvider, error) {
+	path := ctx.String("wallet")
 	if len(path) == 0 {
-		return nil, errNoPath
+		return nil, nil, errNoPath
+	}
+	provider, err := loadPasswordProvider(ctx)
+	if err != nil {
+		return nil, nil, err
 	}
 	if path == "-" {
 		w := &wallet.Wallet{}
 		if err := json.NewDecoder(os.Stdin).Decode(w); err != nil {
-			return nil, fmt.Errorf("js %s", err)
+			return nil, nil, fmt.Errorf("js %s", err)
 		}
-		return w, nil
+		return w, provider, nil
 	}
-	return wallet.NewWalletFromFile(path)
+	w, err := wallet.NewWalletFromFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return w, provider, nil
 }
 
 func addAccountAndSave(w *wallet.Wallet, acc *wallet.Account) error {
@@ -554,6 +746,105 @@ func fmtPrintWallet(w io.Writer, wall *wallet.Wallet) {
 	fmt.Fprintln(w, "")
 }
 
+// sign reads a wallet.SignContext (a transaction, extensible payload, or
+// state root awaiting an M-of-N multisig), adds a partial signature from
+// every local/hardware account in the wallet that's one of its expected
+// signers (optionally restricted to the addresses passed as positional
+// arguments), and either writes the augmented context back out or, once
+// the threshold is met, assembles the final witness and optionally
+// submits it via RPC.
 func sign(ctx *cli.Context) error {
+	wall, provider, err := openWallet(ctx)
+	if err != nil {
+		return cli.Exit(err, 1)
+	}
+	defer wall.Close()
+
+	signers, err := GetSignersFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	in := ctx.String("in")
+	var r io.Reader = os.Stdin
+	if in != "" {
+		f, err := os.Open(in)
+		if err != nil {
+			return cli.Exit(fmt.Errorf("opening sign context: %w", err), 1)
+		}
+		defer f.Close()
+		r = f
+	}
+	sc := new(wallet.SignContext)
+	if err := json.NewDecoder(r).Decode(sc); err != nil {
+		return cli.Exit(fmt.Errorf("error reading sign context: %w", err), 1)
+	}
+
+	fmt.Fprintf(ctx.App.Writer, "Signing %s\n", sc.Describe())
+
+	for _, pk := range sc.PublicKeys {
+		acc := wall.GetAccount(pk.Address())
+		if acc == nil {
+			continue
+		}
+		if len(signers) > 0 && !containsAddress(signers, acc.Address) {
+			continue
+		}
+		if acc.Kind != wallet.AccountKindHardware {
+			pass, err := password(ctx, provider, acc.Address, "Enter password > ")
+			if err != nil {
+				return cli.Exit(fmt.Errorf("error reading password: %w", err), 1)
+			}
+			if err := acc.Decrypt(pass, wall.Scrypt); err != nil {
+				return cli.Exit(fmt.Errorf("unable to decrypt account %s: %w", acc.Address, err), 1)
+			}
+		}
+		if err := wallet.Sign(acc, sc); err != nil {
+			return cli.Exit(err, 1)
+		}
+	}
+
+	if sc.IsComplete() {
+		fmt.Fprintln(ctx.App.Writer, "Signature threshold reached.")
+		switch sc.Type {
+		case wallet.SignContextTx:
+			tx := sc.CreateTx()
+			if ctx.Bool("submit") {
+				raw, err := tx.Bytes()
+				if err != nil {
+					return cli.Exit(fmt.Errorf("encoding transaction: %w", err), 1)
+				}
+				gctx, cancel := options.GetTimeoutContext(ctx)
+				defer cancel()
+				c, err := options.GetRPCClient(gctx, ctx)
+				if err != nil {
+					return cli.Exit(err, 1)
+				}
+				h, err := c.Eth_SendRawTransaction(raw)
+				if err != nil {
+					return cli.Exit(fmt.Errorf("submitting transaction: %w", err), 1)
+				}
+				fmt.Fprintf(ctx.App.Writer, "Submitted transaction %s\n", h)
+				return nil
+			}
+		default:
+			if err := sc.Finalize(); err != nil {
+				return cli.Exit(err, 1)
+			}
+		}
+	}
+
+	out := ctx.String("out")
+	b, err := json.Marshal(sc)
+	if err != nil {
+		return cli.Exit(err, 1)
+	}
+	if out == "" {
+		fmt.Fprintln(ctx.App.Writer, string(b))
+		return nil
+	}
+	if err := os.WriteFile(out, b, 0644); err != nil {
+		return cli.Exit(fmt.Errorf("writing sign context: %w", err), 1)
+	}
 	return nil
 }
@@ -0,0 +1,81 @@
+// Package zk provides the `saiya zk verify` CLI command for offline Groth16
+// proof verification.
+package zk
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/multiverse-dev/saiya/pkg/crypto/zk"
+	"github.com/urfave/cli"
+)
+
+// NewCommands returns the 'zk' command.
+func NewCommands() []cli.Command {
+	return []cli.Command{{
+		Name:  "zk",
+		Usage: "work with zk-SNARK proofs",
+		Subcommands: []cli.Command{
+			{
+				Name:   "verify",
+				Usage:  "verify a Groth16 proof offline",
+				Action: verifyProof,
+				Flags: []cli.Flag{
+					cli.StringFlag{Name: "proof", Usage: "path to the hex-encoded proof file"},
+					cli.StringFlag{Name: "vk", Usage: "path to the hex-encoded verifying key file"},
+					cli.StringFlag{Name: "input", Usage: "path to a JSON array of decimal public inputs"},
+				},
+			},
+		},
+	}}
+}
+
+func readHexFile(path string) ([]byte, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(strings.TrimSpace(string(raw)))
+}
+
+func verifyProof(ctx *cli.Context) error {
+	proof, err := readHexFile(ctx.String("proof"))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to read proof: %w", err), 1)
+	}
+	vk, err := readHexFile(ctx.String("vk"))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to read verifying key: %w", err), 1)
+	}
+
+	var rawInputs []string
+	inputBytes, err := ioutil.ReadFile(ctx.String("input"))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to read public inputs: %w", err), 1)
+	}
+	if err := json.Unmarshal(inputBytes, &rawInputs); err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to parse public inputs: %w", err), 1)
+	}
+	inputs := make([]*big.Int, len(rawInputs))
+	for i, s := range rawInputs {
+		n, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return cli.NewExitError(fmt.Errorf("invalid public input %q", s), 1)
+		}
+		inputs[i] = n
+	}
+
+	ok, err := zk.Verify(proof, vk, inputs)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if !ok {
+		return cli.NewExitError("proof is INVALID", 1)
+	}
+	fmt.Println("proof is VALID")
+	return nil
+}
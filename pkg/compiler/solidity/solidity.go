@@ -0,0 +1,114 @@
+// Package solidity wraps the solc compiler and parses its combined-json
+// output into a typed Contract.
+package solidity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ErrNoSolc is returned when no usable solc binary can be found.
+var ErrNoSolc = errors.New("solidity: solc binary not found")
+
+// Contract holds the compilation artifacts solc produces for a single
+// contract in a --combined-json abi,bin,bin-runtime,devdoc,userdoc run.
+type Contract struct {
+	Name       string
+	ABI        json.RawMessage
+	Bin        string
+	RuntimeBin string
+	DevDoc     json.RawMessage
+	UserDoc    json.RawMessage
+}
+
+// combinedOutput mirrors the shape of `solc --combined-json` output.
+type combinedOutput struct {
+	Contracts map[string]struct {
+		Abi      json.RawMessage `json:"abi"`
+		Bin      string          `json:"bin"`
+		BinRtm   string          `json:"bin-runtime"`
+		Devdoc   json.RawMessage `json:"devdoc"`
+		Userdoc  json.RawMessage `json:"userdoc"`
+	} `json:"contracts"`
+	Version string `json:"version"`
+}
+
+// Compiler shells out to solc to compile Solidity source files.
+type Compiler struct {
+	// Path is the solc binary to invoke. Defaults to "solc" on PATH.
+	Path string
+}
+
+// NewCompiler returns a Compiler that looks up solc at path. If path is
+// empty, "solc" is resolved from PATH.
+func NewCompiler(path string) *Compiler {
+	if path == "" {
+		path = "solc"
+	}
+	return &Compiler{Path: path}
+}
+
+// Version returns the solc version string, or ErrNoSolc if solc cannot be
+// found or executed.
+func (c *Compiler) Version() (string, error) {
+	out, err := exec.Command(c.Path, "--version").CombinedOutput()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", ErrNoSolc
+		}
+		return "", fmt.Errorf("solidity: solc --version: %w", err)
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "Version:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "Version:")), nil
+		}
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Compile runs solc against the given source files and returns one Contract
+// per `<file>:<contractName>` entry solc emits.
+func (c *Compiler) Compile(files ...string) (map[string]*Contract, error) {
+	if len(files) == 0 {
+		return nil, errors.New("solidity: no source files given")
+	}
+	if _, err := c.Version(); err != nil {
+		return nil, err
+	}
+
+	args := append([]string{"--combined-json", "abi,bin,bin-runtime,devdoc,userdoc"}, files...)
+	cmd := exec.Command(c.Path, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("solidity: solc failed: %w: %s", err, stderr.String())
+	}
+
+	var raw combinedOutput
+	if err := json.Unmarshal(stdout.Bytes(), &raw); err != nil {
+		return nil, fmt.Errorf("solidity: failed to parse solc output: %w", err)
+	}
+
+	contracts := make(map[string]*Contract, len(raw.Contracts))
+	for name, ctr := range raw.Contracts {
+		parts := strings.SplitN(name, ":", 2)
+		shortName := name
+		if len(parts) == 2 {
+			shortName = parts[1]
+		}
+		contracts[name] = &Contract{
+			Name:       shortName,
+			ABI:        ctr.Abi,
+			Bin:        ctr.Bin,
+			RuntimeBin: ctr.BinRtm,
+			DevDoc:     ctr.Devdoc,
+			UserDoc:    ctr.Userdoc,
+		}
+	}
+	return contracts, nil
+}
@@ -15,6 +15,7 @@ type ApplicationConfiguration struct {
 	DialTimeout       int64                   `yaml:"DialTimeout"`
 	LogPath           string                  `yaml:"LogPath"`
 	MaxPeers          int                     `yaml:"MaxPeers"`
+	Metrics           Metrics                 `yaml:"Metrics"`
 	MinPeers          int                     `yaml:"MinPeers"`
 	NodePort          uint16                  `yaml:"NodePort"`
 	PingInterval      int64                   `yaml:"PingInterval"`
@@ -28,4 +29,12 @@ type ApplicationConfiguration struct {
 	StateRoot         StateRoot               `yaml:"StateRoot"`
 	// ExtensiblePoolSize is the maximum amount of the extensible payloads from a single sender.
 	ExtensiblePoolSize int `yaml:"ExtensiblePoolSize"`
+	// SyncMode names how this node bootstraps its initial state: "full"
+	// (the default, zero-value behavior) replays every block from genesis;
+	// "snap" instead downloads pkg/core/snapshot range chunks from peers
+	// over GetSnapshotRange and replays only the most recent blocks on
+	// top. Nothing in this checkout reads SyncMode, so setting it does not
+	// change how the node syncs, and there is no --syncmode flag for it
+	// (cli/server is not present in this checkout).
+	SyncMode string `yaml:"SyncMode"`
 }
@@ -0,0 +1,19 @@
+package config
+
+// Metrics opts individual Prometheus metric families in or out. Each flag
+// disables its family so the zero value (an omitted Metrics section)
+// leaves every family enabled, the same "on unless told otherwise"
+// default StateRoot and RPC use elsewhere in ApplicationConfiguration.
+// Each family's package (mempool, native, simulated, rpc) exposes its own
+// MetricsEnabled package var that these flags are meant to set at node
+// startup.
+type Metrics struct {
+	// DisableMempool turns off mempool size/per-sender/accept-reject metrics.
+	DisableMempool bool `yaml:"DisableMempool"`
+	// DisableRPC turns off per-method RPC latency histograms.
+	DisableRPC bool `yaml:"DisableRPC"`
+	// DisableEVM turns off per-destination EVM execution time histograms.
+	DisableEVM bool `yaml:"DisableEVM"`
+	// DisableNative turns off native contract call counters.
+	DisableNative bool `yaml:"DisableNative"`
+}
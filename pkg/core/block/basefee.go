@@ -0,0 +1,44 @@
+package block
+
+import "math/big"
+
+// BaseFeeChangeDenominator bounds how fast BaseFee can move per block: at
+// most a 1/8 (12.5%) step toward or away from gasTarget, mirroring
+// EIP-1559's own constant.
+const BaseFeeChangeDenominator = 8
+
+// CalcBaseFee computes the next block's Header.BaseFee from its parent's,
+// per EIP-1559: it rises when the parent used more gas than gasTarget and
+// falls when it used less, by up to 1/BaseFeeChangeDenominator of
+// parentBaseFee per block, and never drops below minBaseFee.
+func CalcBaseFee(parentBaseFee *big.Int, parentGasUsed, gasTarget uint64, minBaseFee *big.Int) *big.Int {
+	if parentGasUsed == gasTarget {
+		return floorBaseFee(new(big.Int).Set(parentBaseFee), minBaseFee)
+	}
+
+	if parentGasUsed > gasTarget {
+		delta := gasDelta(parentBaseFee, parentGasUsed-gasTarget, gasTarget)
+		if delta.Sign() == 0 {
+			delta.SetInt64(1)
+		}
+		return floorBaseFee(new(big.Int).Add(parentBaseFee, delta), minBaseFee)
+	}
+
+	delta := gasDelta(parentBaseFee, gasTarget-parentGasUsed, gasTarget)
+	return floorBaseFee(new(big.Int).Sub(parentBaseFee, delta), minBaseFee)
+}
+
+// gasDelta is parentBaseFee * gasUsedDelta / gasTarget / BaseFeeChangeDenominator.
+func gasDelta(parentBaseFee *big.Int, gasUsedDelta, gasTarget uint64) *big.Int {
+	d := new(big.Int).Mul(parentBaseFee, big.NewInt(int64(gasUsedDelta)))
+	d.Div(d, big.NewInt(int64(gasTarget)))
+	d.Div(d, big.NewInt(BaseFeeChangeDenominator))
+	return d
+}
+
+func floorBaseFee(fee, min *big.Int) *big.Int {
+	if fee.Cmp(min) < 0 {
+		return new(big.Int).Set(min)
+	}
+	return fee
+}
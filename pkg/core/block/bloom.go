@@ -0,0 +1,17 @@
+package block
+
+import "github.com/ethereum/go-ethereum/core/types"
+
+// ComputeLogsBloom returns the union of every receipt's bloom filter: the
+// aggregate value a block's Header.LogsBloom commits to, letting
+// blockchain.GetLogs rule out a whole block from a single header read
+// before it has to load any receipt.
+func ComputeLogsBloom(receipts []*types.Receipt) types.Bloom {
+	var bloom types.Bloom
+	for _, r := range receipts {
+		for i := range bloom {
+			bloom[i] |= r.Bloom[i]
+		}
+	}
+	return bloom
+}
@@ -0,0 +1,331 @@
+package chaindump
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/core/block"
+	"github.com/multiverse-dev/saiya/pkg/crypto/hash"
+	bio "github.com/multiverse-dev/saiya/pkg/io"
+)
+
+// magicV2 marks the start of a v2 (segmented) chain dump. A v1 dump has no
+// header at all -- it starts directly with a block's 4-byte length prefix
+// -- so a v1 stream is vanishingly unlikely to start with these same 4
+// bytes, which is all sniffing needs.
+var magicV2 = [4]byte{'S', 'C', 'D', '2'}
+
+// dumpV2Version is the only version of the v2 format so far.
+const dumpV2Version = uint32(1)
+
+// DefaultSegmentSize is the number of blocks per segment used by DumpV2
+// when segmentSize is 0.
+const DefaultSegmentSize = 10000
+
+// ErrNotV2 is returned by Verify when the stream doesn't start with
+// magicV2 (RestoreV2 falls back to the legacy Restore instead).
+var ErrNotV2 = errors.New("chaindump: not a v2 dump")
+
+// ErrSegmentRoot is returned when a segment's blocks don't hash to its
+// declared SegmentMerkleRoot.
+var ErrSegmentRoot = errors.New("chaindump: segment Merkle root mismatch")
+
+// ErrSegmentChain is returned when a segment's PrevSegmentHash doesn't
+// match the hash of the segment before it, meaning the file was truncated
+// or reordered.
+var ErrSegmentChain = errors.New("chaindump: segment chain is broken")
+
+// segmentHeader precedes every segment's blocks in a v2 dump: enough to
+// verify the segment's contents (SegmentMerkleRoot) and detect truncation
+// (PrevSegmentHash chains to the previous segment).
+type segmentHeader struct {
+	StartHeight       uint32
+	BlockCount        uint32
+	SegmentMerkleRoot common.Hash
+	PrevSegmentHash   common.Hash
+}
+
+// encode writes h to w using the same BinWriter the block bodies are
+// written with.
+func (h segmentHeader) encode(w *bio.BinWriter) {
+	w.WriteU32LE(h.StartHeight)
+	w.WriteU32LE(h.BlockCount)
+	w.WriteBytes(h.SegmentMerkleRoot.Bytes())
+	w.WriteBytes(h.PrevSegmentHash.Bytes())
+}
+
+// hash returns the integrity hash chaining this segment to the next one's
+// PrevSegmentHash.
+func (h segmentHeader) hash() common.Hash {
+	buf := make([]byte, 8+common.HashLength*2)
+	binary.LittleEndian.PutUint32(buf[0:4], h.StartHeight)
+	binary.LittleEndian.PutUint32(buf[4:8], h.BlockCount)
+	copy(buf[8:8+common.HashLength], h.SegmentMerkleRoot.Bytes())
+	copy(buf[8+common.HashLength:], h.PrevSegmentHash.Bytes())
+	return hash.Keccak256(buf)
+}
+
+// DumpV2 writes a v2 (segmented) dump of count blocks starting at start.
+// Blocks are split into segments of segmentSize blocks each
+// (DefaultSegmentSize if segmentSize is 0); every segment is preceded by a
+// segmentHeader so RestoreV2/Verify can check it as a unit before trusting
+// any block inside it.
+func DumpV2(bc DumperRestorer, w *bio.BinWriter, start, count, segmentSize uint32) error {
+	if segmentSize == 0 {
+		segmentSize = DefaultSegmentSize
+	}
+
+	w.WriteBytes(magicV2[:])
+	w.WriteU32LE(dumpV2Version)
+	if w.Err != nil {
+		return w.Err
+	}
+
+	var prevSegmentHash common.Hash
+	for s := start; s < start+count; s += segmentSize {
+		n := segmentSize
+		if s+n > start+count {
+			n = start + count - s
+		}
+
+		hashes := make([]common.Hash, n)
+		for i := uint32(0); i < n; i++ {
+			hashes[i] = bc.GetHeaderHash(int(s + i))
+		}
+
+		hdr := segmentHeader{
+			StartHeight:       s,
+			BlockCount:        n,
+			SegmentMerkleRoot: hash.CalcMerkleRoot(hashes),
+			PrevSegmentHash:   prevSegmentHash,
+		}
+		hdr.encode(w)
+		if w.Err != nil {
+			return w.Err
+		}
+		if err := Dump(bc, w, s, n); err != nil {
+			return err
+		}
+		prevSegmentHash = hdr.hash()
+	}
+	return nil
+}
+
+// readU32 reads a little-endian uint32 directly off r, bypassing BinReader
+// so a segment can be skipped without allocating or decoding anything.
+func readU32(r io.Reader) (uint32, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(buf[:]), nil
+}
+
+func readHash(r io.Reader) (common.Hash, error) {
+	var h common.Hash
+	_, err := io.ReadFull(r, h[:])
+	return h, err
+}
+
+// readSegmentHeader reads a segmentHeader directly off r, mirroring
+// segmentHeader.encode.
+func readSegmentHeader(r io.Reader) (segmentHeader, error) {
+	var (
+		hdr segmentHeader
+		err error
+	)
+	if hdr.StartHeight, err = readU32(r); err != nil {
+		return hdr, err
+	}
+	if hdr.BlockCount, err = readU32(r); err != nil {
+		return hdr, err
+	}
+	if hdr.SegmentMerkleRoot, err = readHash(r); err != nil {
+		return hdr, err
+	}
+	if hdr.PrevSegmentHash, err = readHash(r); err != nil {
+		return hdr, err
+	}
+	return hdr, nil
+}
+
+// sniffV2 peeks at the first 4 bytes of r to tell a v2 dump from a legacy
+// one. For a v2 dump it leaves r positioned right after the magic and
+// version; for a legacy one it seeks r back to where it started, so the
+// caller can continue reading from there either way.
+func sniffV2(r io.ReadSeeker) (bool, error) {
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return false, nil
+		}
+		return false, err
+	}
+	if magic != magicV2 {
+		if _, err := r.Seek(-int64(len(magic)), io.SeekCurrent); err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+	if _, err := readU32(r); err != nil { // dump version, currently unused
+		return false, err
+	}
+	return true, nil
+}
+
+// readOneBlock reads and decodes a single v1-framed block (length prefix
+// plus body) off r.
+func readOneBlock(r io.Reader) (*block.Block, error) {
+	size, err := readU32(r)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	b := block.New()
+	br := bio.NewBinReaderFromBuf(buf)
+	b.DecodeBinary(br)
+	return b, br.Err
+}
+
+// skipOneBlock skips a single v1-framed block off r without decoding it.
+func skipOneBlock(r io.ReadSeeker) error {
+	size, err := readU32(r)
+	if err != nil {
+		return err
+	}
+	_, err = r.Seek(int64(size), io.SeekCurrent)
+	return err
+}
+
+// RestoreV2Options configures RestoreV2.
+type RestoreV2Options struct {
+	// ResumeFromHeight is the first height to restore: every segment that
+	// ends below it is skipped by seeking past it, without decoding any of
+	// its blocks, and blocks below it in the segment that contains it are
+	// verified but not added, picking an interrupted restore back up where
+	// it left off.
+	ResumeFromHeight uint32
+}
+
+// RestoreV2 restores blocks from a v2 dump read from r, verifying each
+// segment's Merkle root and chain-hash before adding any of its blocks to
+// bc. If r turns out to hold a legacy (v1) dump instead, it falls back to
+// Restore, ignoring opts.ResumeFromHeight (v1 streams carry nothing to
+// resume from). f, if non-nil, is called after every block is added.
+func RestoreV2(bc DumperRestorer, r io.ReadSeeker, opts RestoreV2Options, f func(b *block.Block) error) error {
+	isV2, err := sniffV2(r)
+	if err != nil {
+		return err
+	}
+	if !isV2 {
+		return Restore(bc, bio.NewBinReaderFromIO(r), 0, ^uint32(0), f)
+	}
+
+	var prevSegmentHash common.Hash
+	for {
+		hdr, err := readSegmentHeader(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.PrevSegmentHash != prevSegmentHash {
+			return ErrSegmentChain
+		}
+
+		if hdr.StartHeight+hdr.BlockCount <= opts.ResumeFromHeight {
+			for i := uint32(0); i < hdr.BlockCount; i++ {
+				if err := skipOneBlock(r); err != nil {
+					return err
+				}
+			}
+			prevSegmentHash = hdr.hash()
+			continue
+		}
+
+		hashes := make([]common.Hash, hdr.BlockCount)
+		blocks := make([]*block.Block, hdr.BlockCount)
+		for i := uint32(0); i < hdr.BlockCount; i++ {
+			b, err := readOneBlock(r)
+			if err != nil {
+				return err
+			}
+			blocks[i] = b
+			hashes[i] = b.Hash()
+		}
+		if hash.CalcMerkleRoot(hashes) != hdr.SegmentMerkleRoot {
+			return ErrSegmentRoot
+		}
+
+		for i, b := range blocks {
+			height := hdr.StartHeight + uint32(i)
+			if height < opts.ResumeFromHeight {
+				continue
+			}
+			if b.Index != 0 || height != 0 {
+				if err := bc.AddBlock(b); err != nil {
+					return err
+				}
+			}
+			if f != nil {
+				if err := f(b); err != nil {
+					return err
+				}
+			}
+		}
+		prevSegmentHash = hdr.hash()
+	}
+}
+
+// Verify walks a v2 dump read from r and checks every segment's Merkle
+// root and chain-hash, without calling into bc at all. It returns the
+// highest block height covered by a fully-valid run of segments starting
+// from the first one; it stops (without error) at the first segment that
+// fails to verify or at EOF.
+func Verify(r io.ReadSeeker) (uint32, error) {
+	isV2, err := sniffV2(r)
+	if err != nil {
+		return 0, err
+	}
+	if !isV2 {
+		return 0, ErrNotV2
+	}
+
+	var (
+		prevSegmentHash common.Hash
+		highest         uint32
+	)
+	for {
+		hdr, err := readSegmentHeader(r)
+		if err == io.EOF {
+			return highest, nil
+		}
+		if err != nil {
+			return highest, err
+		}
+		if hdr.PrevSegmentHash != prevSegmentHash {
+			return highest, nil
+		}
+
+		hashes := make([]common.Hash, hdr.BlockCount)
+		for i := uint32(0); i < hdr.BlockCount; i++ {
+			b, err := readOneBlock(r)
+			if err != nil {
+				return highest, err
+			}
+			hashes[i] = b.Hash()
+		}
+		if hash.CalcMerkleRoot(hashes) != hdr.SegmentMerkleRoot {
+			return highest, nil
+		}
+
+		highest = hdr.StartHeight + hdr.BlockCount - 1
+		prevSegmentHash = hdr.hash()
+	}
+}
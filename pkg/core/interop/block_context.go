@@ -9,6 +9,19 @@ import (
 	"github.com/multiverse-dev/saiya/pkg/evm/vm"
 )
 
+// PersistHooks lets a chain run extra state changes around a block's user
+// transactions within the same state transition, rather than in an
+// out-of-band path: OnPersist before any transaction executes (e.g.
+// validator-reward distribution, coinbase crediting - bctx.Coinbase is set
+// by NewEVMBlockContext below but nothing pays it on its own) and
+// PostPersist after the last one
+// (validator-set rotation, governance-contract housekeeping). Either may be
+// left nil to skip that step.
+type PersistHooks struct {
+	OnPersist   func(bctx vm.BlockContext, sdb vm.StateDB) error
+	PostPersist func(bctx vm.BlockContext, sdb vm.StateDB) error
+}
+
 func NewEVMBlockContext(block *block.Block,
 	bc Chain,
 	protocolSettings config.ProtocolConfiguration) (bctx vm.BlockContext) {
@@ -11,6 +11,7 @@ import (
 	"github.com/multiverse-dev/saiya/pkg/core/statedb"
 	"github.com/multiverse-dev/saiya/pkg/core/transaction"
 	"github.com/multiverse-dev/saiya/pkg/crypto/keys"
+	"github.com/multiverse-dev/saiya/pkg/crypto/zk"
 	"github.com/multiverse-dev/saiya/pkg/evm"
 	"github.com/multiverse-dev/saiya/pkg/evm/vm"
 )
@@ -24,6 +25,7 @@ type Chain interface {
 	GetConfig() config.ProtocolConfiguration
 	Contracts() *native.Contracts
 	GetCurrentValidators() ([]*keys.PublicKey, error)
+	GetPersistHooks() PersistHooks
 }
 
 // Context represents context in which interops are executed.
@@ -60,6 +62,11 @@ func NewContext(block *block.Block, tx *transaction.Transaction, sdb *statedb.St
 				nativeContract: chain.Contracts().GAS,
 				ic:             ctx,
 			},
+			native.RandomnessAddress: nativeWrapper{
+				nativeContract: chain.Contracts().Randomness,
+				ic:             ctx,
+			},
+			zk.PrecompileAddress: zk.Precompile{},
 		})
 	return ctx, nil
 }
@@ -84,6 +91,38 @@ func (c Context) Coinbase() common.Address {
 	return c.bctx.Coinbase
 }
 
+// RunOnPersist invokes c.Chain.GetPersistHooks().OnPersist, if set, against
+// this Context's block context and state database - meant to run before any
+// of c.Block's transactions execute, the same way PostPersist below is
+// meant to run after the last one. Both are no-ops when the corresponding
+// hook is nil.
+//
+// There is no caller for RunOnPersist/RunPostPersist in this checkout yet:
+// that requires a per-block persist loop (a Blockchain type), which isn't
+// present here - the closest existing precedent is the native contracts'
+// own OnPersist(d *dao.Simple, block *block.Block) methods (pkg/core/native,
+// e.g. randomness.go), which are dead code for the identical reason (see
+// randomness_doc.go). Coinbase (above) is likewise still never paid by
+// anything; an OnPersist hook crediting it is how that would eventually
+// happen once a call site exists.
+func (c Context) RunOnPersist() error {
+	hooks := c.Chain.GetPersistHooks()
+	if hooks.OnPersist == nil {
+		return nil
+	}
+	return hooks.OnPersist(c.bctx, c.sdb)
+}
+
+// RunPostPersist is RunOnPersist's counterpart for PostPersist. See
+// RunOnPersist's doc comment for why nothing calls this yet either.
+func (c Context) RunPostPersist() error {
+	hooks := c.Chain.GetPersistHooks()
+	if hooks.PostPersist == nil {
+		return nil
+	}
+	return hooks.PostPersist(c.bctx, c.sdb)
+}
+
 func (c Context) Address() common.Address {
 	return c.Tx.From()
 }
@@ -13,3 +13,10 @@ func NewEVMTxContext(sender common.Address, gasPrice *big.Int) vm.TxContext {
 		GasPrice: gasPrice,
 	}
 }
+
+// NewEVMTxContext doesn't thread an access list through to pkg/evm/vm.TxContext
+// because that package isn't present in this snapshot (pkg/evm/vm.TxContext's
+// own field list can't be checked, unlike pkg/vm's BlockContext/TxContext used
+// by interop's own tests) - core.IntrinsicGas and SignContext.Check already
+// account for Transaction.AccessList() ahead of the EVM call, which is where
+// go-ethereum's StateDB.PrepareAccessList (not TxContext) consumes it too.
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Per-unit costs of the intrinsic gas formula, matching go-ethereum's
+// own TxGas/TxDataNonZeroGasEIP2028/TxAccessListAddressGas constants so a
+// saiya transaction prepays storage warmups the same way an Ethereum one
+// does.
+const (
+	TxGas                     = 21000
+	TxDataZeroGas             = 4
+	TxDataNonZeroGasEIP2028   = 16
+	TxAccessListAddressGas    = 2400
+	TxAccessListStorageKeyGas = 1900
+)
+
+// ErrGasUintOverflow is returned when computing intrinsic gas for data long
+// enough to overflow a uint64.
+var ErrGasUintOverflow = errors.New("intrinsic gas: overflow")
+
+// IntrinsicGas returns the minimum gas a transaction carrying data and
+// accessList must supply before the EVM runs: 21000 base, plus 16 per
+// non-zero data byte and 4 per zero byte (EIP-2028), plus 2400 per
+// access-list address and 1900 per access-list storage key (EIP-2930).
+//
+// isCreate is accepted for parity with go-ethereum's IntrinsicGas, which
+// additionally meters per-32-byte-word init code on contract creation;
+// this repo doesn't track that word count anywhere yet, so isCreate is
+// currently unused here.
+func IntrinsicGas(data []byte, accessList types.AccessList, isCreate bool) (uint64, error) {
+	_ = isCreate
+	gas := uint64(TxGas)
+	if len(data) > 0 {
+		var nz uint64
+		for _, b := range data {
+			if b != 0 {
+				nz++
+			}
+		}
+		if (math.MaxUint64-gas)/TxDataNonZeroGasEIP2028 < nz {
+			return 0, ErrGasUintOverflow
+		}
+		gas += nz * TxDataNonZeroGasEIP2028
+
+		z := uint64(len(data)) - nz
+		if (math.MaxUint64-gas)/TxDataZeroGas < z {
+			return 0, ErrGasUintOverflow
+		}
+		gas += z * TxDataZeroGas
+	}
+	if len(accessList) > 0 {
+		gas += uint64(len(accessList)) * TxAccessListAddressGas
+		for _, tuple := range accessList {
+			gas += uint64(len(tuple.StorageKeys)) * TxAccessListStorageKeyGas
+		}
+	}
+	return gas, nil
+}
@@ -0,0 +1,113 @@
+package light
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/core/block"
+)
+
+// ErrBelowFinalized is returned when a header or finality update would
+// roll the light client's view back past its last committee-finalized
+// height.
+var ErrBelowFinalized = errors.New("light: update is below the last finalized height")
+
+// FinalityUpdate is the latest header a committee has been seen to sign
+// off on, mirroring an Ethereum consensus light client's finality update:
+// everything at or below its height is treated as irreversible.
+type FinalityUpdate struct {
+	Header    block.Header
+	Bitmap    []byte
+	Signature []byte
+}
+
+// HeadTracker holds a light client's view of the chain: the latest header
+// it has accepted (chained by parent hash from the trusted checkpoint) and
+// the latest finality update a committee has signed over.
+type HeadTracker struct {
+	mtx sync.RWMutex
+
+	checkpoint Checkpoint
+	head       *block.Header
+	final      *FinalityUpdate
+}
+
+// NewHeadTracker returns a HeadTracker anchored at cp; the first header it
+// accepts must chain by parent hash to cp.Hash.
+func NewHeadTracker(cp Checkpoint) *HeadTracker {
+	return &HeadTracker{checkpoint: cp}
+}
+
+// Head returns the latest accepted header, or false if none has been
+// accepted yet.
+func (t *HeadTracker) Head() (block.Header, bool) {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+	if t.head == nil {
+		return block.Header{}, false
+	}
+	return *t.head, true
+}
+
+// ParentHash returns the hash a candidate header must chain to in order to
+// extend the current tip: the latest accepted header's hash, or the
+// trusted checkpoint's hash if no header has been accepted yet.
+func (t *HeadTracker) ParentHash() common.Hash {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+	if t.head == nil {
+		return t.checkpoint.Hash
+	}
+	return t.head.Hash()
+}
+
+// Finalized returns the latest committee-signed finality update, or false
+// if none has been recorded yet.
+func (t *HeadTracker) Finalized() (FinalityUpdate, bool) {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+	if t.final == nil {
+		return FinalityUpdate{}, false
+	}
+	return *t.final, true
+}
+
+// finalizedHeight returns the height below which HeadTracker refuses to
+// roll back: the checkpoint's height if no finality update has landed yet,
+// otherwise the latest finality update's height. Callers must hold t.mtx.
+func (t *HeadTracker) finalizedHeight() uint32 {
+	if t.final == nil {
+		return t.checkpoint.Height
+	}
+	return t.final.Header.Index
+}
+
+// Advance records h as the new accepted head. Callers must have already
+// verified h chains to ParentHash(); Advance itself only enforces the
+// anti-reorg rule that h may not roll back below the last finalized
+// height.
+func (t *HeadTracker) Advance(h block.Header) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if h.Index < t.finalizedHeight() {
+		return ErrBelowFinalized
+	}
+	head := h
+	t.head = &head
+	return nil
+}
+
+// SetFinalized records upd as the latest committee-signed finality update.
+// Callers must have already verified upd's aggregate signature; SetFinalized
+// itself only enforces that finality height is non-decreasing.
+func (t *HeadTracker) SetFinalized(upd FinalityUpdate) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if upd.Header.Index < t.finalizedHeight() {
+		return ErrBelowFinalized
+	}
+	u := upd
+	t.final = &u
+	return nil
+}
@@ -0,0 +1,32 @@
+// Package light implements a header-only light sync mode: a client can
+// follow the chain tip from a trusted checkpoint using only block headers
+// plus periodic committee-signed finality updates, without downloading or
+// executing any transaction.
+package light
+
+import "github.com/ethereum/go-ethereum/common"
+
+// Checkpoint is a single weak-subjectivity checkpoint: a block hash and
+// height a light client may start following from instead of replaying
+// full history from genesis.
+type Checkpoint struct {
+	Height uint32
+	Hash   common.Hash
+}
+
+// CheckpointStore holds the checkpoint a HeadTracker is anchored to. It is
+// seeded once from config at node start; saiya does not (yet) support
+// moving to a later checkpoint at runtime.
+type CheckpointStore struct {
+	trusted Checkpoint
+}
+
+// NewCheckpointStore returns a CheckpointStore anchored at height/hash.
+func NewCheckpointStore(height uint32, hash common.Hash) *CheckpointStore {
+	return &CheckpointStore{trusted: Checkpoint{Height: height, Hash: hash}}
+}
+
+// Trusted returns the checkpoint this store was seeded with.
+func (s *CheckpointStore) Trusted() Checkpoint {
+	return s.trusted
+}
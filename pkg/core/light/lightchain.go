@@ -0,0 +1,119 @@
+package light
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/multiverse-dev/saiya/pkg/config"
+	"github.com/multiverse-dev/saiya/pkg/core/block"
+)
+
+// ErrNoHeader is returned when a header isn't (yet) known to the LightChain.
+var ErrNoHeader = errors.New("light: header not found")
+
+// ErrNoTransactions is returned by GetBlock(hash, true): a LightChain only
+// ever stores headers, so it can't serve full block bodies.
+var ErrNoTransactions = errors.New("light: transactions are not available in header-only sync mode")
+
+// LightChain is a header-only chain view: it validates and stores headers
+// plus the finality updates that commit to them, without fetching or
+// executing any transaction. It implements the minimum of
+// chaindump.DumperRestorer plus header lookup needed so an existing
+// chaindump.Restore can ingest a header-only dump stream.
+type LightChain struct {
+	mtx sync.RWMutex
+
+	cfg       config.ProtocolConfiguration
+	tracker   *HeadTracker
+	validator *Validator
+
+	byHeight map[uint32]common.Hash
+	headers  map[common.Hash]block.Header
+}
+
+// NewLightChain returns a LightChain anchored at cp, validating headers and
+// finality updates through v.
+func NewLightChain(cfg config.ProtocolConfiguration, cp Checkpoint, v *Validator) *LightChain {
+	return &LightChain{
+		cfg:       cfg,
+		tracker:   NewHeadTracker(cp),
+		validator: v,
+		byHeight:  make(map[uint32]common.Hash),
+		headers:   make(map[common.Hash]block.Header),
+	}
+}
+
+// AddBlock implements chaindump.DumperRestorer: it validates b's header
+// against the tracked tip and, if it chains correctly, records it. Only
+// the header is kept; any transactions b carries are ignored.
+func (c *LightChain) AddBlock(b *block.Block) error {
+	if err := c.validator.ValidateHeader(c.tracker, b.Header); err != nil {
+		return err
+	}
+	if err := c.tracker.Advance(b.Header); err != nil {
+		return err
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	h := b.Header.Hash()
+	c.byHeight[b.Header.Index] = h
+	c.headers[h] = b.Header
+	return nil
+}
+
+// AddFinalityUpdate validates upd's committee aggregate signature and, if
+// it verifies, records it as the chain's new finalized head.
+func (c *LightChain) AddFinalityUpdate(upd FinalityUpdate) error {
+	if err := c.validator.ValidateFinalityUpdate(upd); err != nil {
+		return err
+	}
+	return c.tracker.SetFinalized(upd)
+}
+
+// GetBlock implements chaindump.DumperRestorer. A LightChain never has
+// transaction bodies, so full=true always fails; full=false returns a
+// trimmed block wrapping the stored header.
+func (c *LightChain) GetBlock(hash common.Hash, full bool) (*block.Block, error) {
+	c.mtx.RLock()
+	h, ok := c.headers[hash]
+	c.mtx.RUnlock()
+	if !ok {
+		return nil, ErrNoHeader
+	}
+	if full {
+		return nil, ErrNoTransactions
+	}
+	return &block.Block{Header: h, Trimmed: true}, nil
+}
+
+// GetHeaderHash implements chaindump.DumperRestorer.
+func (c *LightChain) GetHeaderHash(index int) common.Hash {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.byHeight[uint32(index)]
+}
+
+// GetConfig implements chaindump.DumperRestorer.
+func (c *LightChain) GetConfig() config.ProtocolConfiguration {
+	return c.cfg
+}
+
+// GetReceipt always fails: a LightChain only syncs headers, so it never
+// has the transaction (and therefore receipt) data needed to answer this.
+func (c *LightChain) GetReceipt(_ common.Hash) (*types.Receipt, error) {
+	return nil, ErrNoTransactions
+}
+
+// GetHeader returns the stored header for hash, if any.
+func (c *LightChain) GetHeader(hash common.Hash) (block.Header, error) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	h, ok := c.headers[hash]
+	if !ok {
+		return block.Header{}, ErrNoHeader
+	}
+	return h, nil
+}
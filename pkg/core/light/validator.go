@@ -0,0 +1,84 @@
+package light
+
+import (
+	"errors"
+
+	"github.com/multiverse-dev/saiya/pkg/core/block"
+	"github.com/multiverse-dev/saiya/pkg/crypto/bls"
+)
+
+// ErrParentMismatch is returned when a candidate header does not chain by
+// parent hash to the tracker's current tip (or trusted checkpoint, if no
+// header has been accepted yet).
+var ErrParentMismatch = errors.New("light: header does not chain to the current head")
+
+// ErrBitmapSize is returned when a finality update's committee bitmap
+// doesn't have exactly one bit per member of the committee it's checked
+// against.
+var ErrBitmapSize = errors.New("light: committee bitmap size mismatch")
+
+// CommitteeSource resolves the BLS committee empowered to sign finality
+// updates as of a given block height. In a full node it is backed by the
+// Designation native contract's stored role assignment at that height, so
+// a light client can verify updates without re-executing any transaction.
+type CommitteeSource interface {
+	CommitteeAt(height uint32) ([]*bls.PublicKey, error)
+}
+
+// Validator checks candidate headers and finality updates against a
+// HeadTracker before they're allowed to advance it.
+type Validator struct {
+	committee CommitteeSource
+}
+
+// NewValidator returns a Validator resolving committees through committee.
+func NewValidator(committee CommitteeSource) *Validator {
+	return &Validator{committee: committee}
+}
+
+// ValidateHeader reports whether h may extend t: it must chain by parent
+// hash to t's current tip (or checkpoint).
+func (v *Validator) ValidateHeader(t *HeadTracker, h block.Header) error {
+	if h.PrevHash != t.ParentHash() {
+		return ErrParentMismatch
+	}
+	return nil
+}
+
+// ValidateFinalityUpdate checks that upd's aggregate signature verifies
+// over its header's hash, under the committee active at upd.Header.Index,
+// restricted to the signing subset named by upd.Bitmap.
+func (v *Validator) ValidateFinalityUpdate(upd FinalityUpdate) error {
+	committee, err := v.committee.CommitteeAt(upd.Header.Index)
+	if err != nil {
+		return err
+	}
+	signers, err := selectSigners(committee, upd.Bitmap)
+	if err != nil {
+		return err
+	}
+	sig, err := bls.Unmarshal(upd.Signature)
+	if err != nil {
+		return err
+	}
+	h := upd.Header.Hash()
+	return bls.VerifyAggregate(sig, signers, h.Bytes())
+}
+
+// selectSigners picks out of committee the subset whose bit is set in
+// bitmap, one bit per committee member in order.
+func selectSigners(committee []*bls.PublicKey, bitmap []byte) ([]*bls.PublicKey, error) {
+	if len(bitmap) != (len(committee)+7)/8 {
+		return nil, ErrBitmapSize
+	}
+	signers := make([]*bls.PublicKey, 0, len(committee))
+	for i, pub := range committee {
+		if bitmap[i/8]&(1<<uint(i%8)) != 0 {
+			signers = append(signers, pub)
+		}
+	}
+	if len(signers) == 0 {
+		return nil, bls.ErrNoSignatures
+	}
+	return signers, nil
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// GetLogs implements the historical-query half of eth_getLogs: it scans
+// [fromBlock, toBlock], testing each block's Header.LogsBloom against
+// addresses/topics and only loading that block's receipts once the bloom
+// says it might contain a match. It returns every log from a block that
+// passed the bloom check; the RPC layer is responsible for the final
+// exact address/topic match (shared with the log subscription filter),
+// since a bloom hit only means "maybe".
+func (bc *Blockchain) GetLogs(fromBlock, toBlock uint32, addresses []common.Address, topics [][]common.Hash) ([]*types.Log, error) {
+	var logs []*types.Log
+	for height := fromBlock; height <= toBlock; height++ {
+		blockHash := bc.GetHeaderHash(int(height))
+		header, err := bc.GetHeader(blockHash)
+		if err != nil {
+			return nil, err
+		}
+		if !bloomMatches(header.LogsBloom, addresses, topics) {
+			continue
+		}
+		receipts, err := bc.GetReceipts(blockHash)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range receipts {
+			logs = append(logs, r.Logs...)
+		}
+	}
+	return logs, nil
+}
+
+// bloomMatches reports whether bloom could possibly contain a log from one
+// of addresses (if any were given) and matching every topic position (if
+// any were given). A false return is conclusive; a true return only means
+// the block is worth loading receipts for.
+func bloomMatches(bloom types.Bloom, addresses []common.Address, topics [][]common.Hash) bool {
+	if len(addresses) > 0 {
+		var any bool
+		for _, a := range addresses {
+			if types.BloomLookup(bloom, a) {
+				any = true
+				break
+			}
+		}
+		if !any {
+			return false
+		}
+	}
+	for _, position := range topics {
+		if len(position) == 0 {
+			continue
+		}
+		var any bool
+		for _, t := range position {
+			if types.BloomLookup(bloom, t) {
+				any = true
+				break
+			}
+		}
+		if !any {
+			return false
+		}
+	}
+	return true
+}
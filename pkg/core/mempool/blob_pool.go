@@ -0,0 +1,371 @@
+package mempool
+
+import (
+	"container/heap"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/core/mempoolevent"
+	"github.com/multiverse-dev/saiya/pkg/core/transaction"
+	"go.uber.org/atomic"
+)
+
+// MaxBlobsPerBlock is EIP-4844's per-block cap on the number of blobs a
+// block may include. BlobPool rejects any single transaction that alone
+// declares more blobs than this, since no block could ever fit it.
+const MaxBlobsPerBlock = 6
+
+var (
+	// ErrNotBlobTx is returned when a non-BlobTxType transaction is
+	// offered to the BlobPool.
+	ErrNotBlobTx = errors.New("not a blob transaction")
+	// ErrMissingSidecar is returned when a blob transaction is added
+	// without the sidecar its BlobVersionedHashes commit to.
+	ErrMissingSidecar = errors.New("blob transaction missing sidecar")
+	// ErrTooManyBlobs is returned when a transaction declares more blobs
+	// than MaxBlobsPerBlock allows.
+	ErrTooManyBlobs = errors.New("blob transaction exceeds MAX_BLOBS_PER_BLOCK")
+	// ErrBlobDup is returned when a blob transaction being added is
+	// already present in the blob pool.
+	ErrBlobDup = errors.New("already in the blob pool")
+)
+
+// SidecarStore persists blob sidecars out-of-band from BlobPool's much
+// smaller in-memory index, keyed by transaction hash. Sidecars are large
+// (~128 KiB per blob), so BlobPool only keeps a bounded number of them and
+// leaves actually holding the bytes, and enforcing that bound (e.g. as an
+// on-disk LRU), to the store.
+type SidecarStore interface {
+	Put(hash common.Hash, sidecar *transaction.BlobSidecar) error
+	Get(hash common.Hash) (*transaction.BlobSidecar, bool)
+	Delete(hash common.Hash)
+}
+
+// blobItem is BlobPool's in-memory index entry for a single pooled blob
+// transaction: just enough to price and order it without touching its
+// (store-backed) sidecar.
+type blobItem struct {
+	txn              *transaction.Transaction
+	from             common.Address
+	nonce            uint64
+	maxFeePerBlobGas *big.Int
+	// tip and blobFee are this item's effective execution-gas tip and
+	// blob-gas fee as of the last time they were (re)computed against the
+	// pool's current base fees; see Pool.item.tip for the same idea
+	// applied to a single fee dimension instead of two.
+	tip     *big.Int
+	blobFee *big.Int
+	// index is this item's position in its account's heap, maintained by
+	// container/heap so Remove can find it in O(log n) instead of a scan.
+	index int
+}
+
+// priority is the value accountBlobs orders by: the weaker of this item's
+// two fee dimensions, execution tip and blob fee, so an account is only as
+// well-prioritized as its worst-paying dimension on any single pooled tx.
+func (it *blobItem) priority() *big.Int {
+	if it.tip.Cmp(it.blobFee) < 0 {
+		return it.tip
+	}
+	return it.blobFee
+}
+
+// accountBlobs is a per-account min-heap of blobItem, weakest priority at
+// the root, ordered secondarily by nonce so that evicting the root always
+// drops the worst-paying entry for that account without disturbing nonce
+// order among the rest.
+type accountBlobs []*blobItem
+
+func (h accountBlobs) Len() int { return len(h) }
+func (h accountBlobs) Less(i, j int) bool {
+	if c := h[i].priority().Cmp(h[j].priority()); c != 0 {
+		return c < 0
+	}
+	return h[i].nonce < h[j].nonce
+}
+func (h accountBlobs) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *accountBlobs) Push(x interface{}) {
+	it := x.(*blobItem)
+	it.index = len(*h)
+	*h = append(*h, it)
+}
+func (h *accountBlobs) Pop() interface{} {
+	old := *h
+	n := len(old)
+	it := old[n-1]
+	old[n-1] = nil
+	it.index = -1
+	*h = old[:n-1]
+	return it
+}
+
+// BlobPool is a SubPool for EIP-4844-style blob-carrying transactions. It
+// keeps only a small {from, nonce, fee} index in memory and offloads the
+// actual blob payloads, which can run ~128 KiB each, to a SidecarStore.
+// Eviction, once the pool is over capacity, drops the pooled transaction
+// whose account's worst-priced entry is the weakest across the whole pool.
+type BlobPool struct {
+	lock sync.RWMutex
+
+	store    SidecarStore
+	capacity int
+
+	byHash    map[common.Hash]*blobItem
+	byAccount map[common.Address]*accountBlobs
+
+	subscriptionsEnabled bool
+	subscriptionsOn      atomic.Bool
+	stopCh               chan struct{}
+	events               chan mempoolevent.Event
+	subCh                chan chan<- mempoolevent.Event
+	unsubCh              chan chan<- mempoolevent.Event
+}
+
+var _ SubPool = (*BlobPool)(nil)
+
+// NewBlobPool returns a new BlobPool backed by the given SidecarStore.
+func NewBlobPool(capacity int, store SidecarStore, enableSubscriptions bool) *BlobPool {
+	bp := &BlobPool{
+		store:                store,
+		capacity:             capacity,
+		byHash:               make(map[common.Hash]*blobItem),
+		byAccount:            make(map[common.Address]*accountBlobs),
+		subscriptionsEnabled: enableSubscriptions,
+		stopCh:               make(chan struct{}),
+		events:               make(chan mempoolevent.Event),
+		subCh:                make(chan chan<- mempoolevent.Event),
+		unsubCh:              make(chan chan<- mempoolevent.Event),
+	}
+	bp.subscriptionsOn.Store(false)
+	return bp
+}
+
+// Add validates and admits a BlobTxType transaction, offloading its
+// sidecar to the store and indexing it by account for eviction.
+func (bp *BlobPool) Add(t *transaction.Transaction, fee Feer, data ...interface{}) error {
+	if t.Type != transaction.BlobTxType {
+		return ErrNotBlobTx
+	}
+	sidecar := t.BlobTx.Sidecar
+	if sidecar == nil {
+		return ErrMissingSidecar
+	}
+	if len(t.BlobTx.BlobVersionedHashes) > MaxBlobsPerBlock {
+		return ErrTooManyBlobs
+	}
+
+	it := &blobItem{
+		txn:              t,
+		from:             t.From(),
+		nonce:            t.Nonce(),
+		maxFeePerBlobGas: t.BlobTx.MaxFeePerBlobGas,
+		tip:              t.EffectiveGasTip(fee.BaseFee()),
+		blobFee:          t.BlobTx.EffectiveBlobFeeTip(fee.BlobBaseFee()),
+	}
+
+	bp.lock.Lock()
+	if _, ok := bp.byHash[t.Hash()]; ok {
+		bp.lock.Unlock()
+		return ErrBlobDup
+	}
+	if err := bp.store.Put(t.Hash(), sidecar); err != nil {
+		bp.lock.Unlock()
+		return err
+	}
+	bp.byHash[t.Hash()] = it
+	acc, ok := bp.byAccount[it.from]
+	if !ok {
+		acc = &accountBlobs{}
+		bp.byAccount[it.from] = acc
+	}
+	heap.Push(acc, it)
+
+	if len(bp.byHash) > bp.capacity {
+		bp.evictWeakest()
+	}
+	bp.lock.Unlock()
+
+	if bp.subscriptionsOn.Load() {
+		var d interface{}
+		if data != nil {
+			d = data[0]
+		}
+		bp.events <- mempoolevent.Event{
+			Type: mempoolevent.TransactionAdded,
+			Tx:   t,
+			Data: d,
+		}
+	}
+	return nil
+}
+
+// evictWeakest drops the pooled transaction whose account's worst-priced
+// entry is the weakest of all accounts' worst-priced entries. Must be
+// called with bp.lock held.
+func (bp *BlobPool) evictWeakest() {
+	var (
+		weakest *blobItem
+		from    common.Address
+	)
+	for addr, acc := range bp.byAccount {
+		if acc.Len() == 0 {
+			continue
+		}
+		candidate := (*acc)[0]
+		if weakest == nil || candidate.priority().Cmp(weakest.priority()) < 0 {
+			weakest = candidate
+			from = addr
+		}
+	}
+	if weakest == nil {
+		return
+	}
+	bp.removeInternal(weakest.txn.Hash(), from)
+}
+
+// Remove drops the transaction with the given hash from the pool, if
+// present.
+func (bp *BlobPool) Remove(hash common.Hash, feer Feer) {
+	bp.lock.Lock()
+	it, ok := bp.byHash[hash]
+	if !ok {
+		bp.lock.Unlock()
+		return
+	}
+	bp.removeInternal(hash, it.from)
+	bp.lock.Unlock()
+}
+
+// removeInternal is an internal unlocked version of Remove; it also backs
+// evictWeakest, which already knows the account a hash belongs to. Must be
+// called with bp.lock held.
+func (bp *BlobPool) removeInternal(hash common.Hash, from common.Address) {
+	it, ok := bp.byHash[hash]
+	if !ok {
+		return
+	}
+	delete(bp.byHash, hash)
+	bp.store.Delete(hash)
+	if acc, ok := bp.byAccount[from]; ok {
+		heap.Remove(acc, it.index)
+		if acc.Len() == 0 {
+			delete(bp.byAccount, from)
+		}
+	}
+	if bp.subscriptionsOn.Load() {
+		bp.events <- mempoolevent.Event{
+			Type: mempoolevent.TransactionRemoved,
+			Tx:   it.txn,
+		}
+	}
+}
+
+// Get returns the pooled transaction with the given hash, if any. Its
+// sidecar is not fetched; use GetSidecar for that.
+func (bp *BlobPool) Get(hash common.Hash) (*transaction.Transaction, bool) {
+	bp.lock.RLock()
+	defer bp.lock.RUnlock()
+	it, ok := bp.byHash[hash]
+	if !ok {
+		return nil, false
+	}
+	return it.txn, true
+}
+
+// GetSidecar returns the sidecar for the pooled transaction with the given
+// hash, if both it and the sidecar are present.
+func (bp *BlobPool) GetSidecar(hash common.Hash) (*transaction.BlobSidecar, bool) {
+	bp.lock.RLock()
+	if _, ok := bp.byHash[hash]; !ok {
+		bp.lock.RUnlock()
+		return nil, false
+	}
+	bp.lock.RUnlock()
+	return bp.store.Get(hash)
+}
+
+// Pending returns all the transactions currently pooled.
+func (bp *BlobPool) Pending() []*transaction.Transaction {
+	bp.lock.RLock()
+	defer bp.lock.RUnlock()
+	txs := make([]*transaction.Transaction, 0, len(bp.byHash))
+	for _, it := range bp.byHash {
+		txs = append(txs, it.txn)
+	}
+	return txs
+}
+
+// Stats reports the pool's current size. BlobPool has no notion of parked
+// transactions, so Stats.Parked is always 0.
+func (bp *BlobPool) Stats() Stats {
+	bp.lock.RLock()
+	defer bp.lock.RUnlock()
+	return Stats{Pending: len(bp.byHash)}
+}
+
+// RunSubscriptions runs subscriptions goroutine if blob pool subscriptions
+// are enabled. You should manually free the resources by calling
+// StopSubscriptions on pool shutdown.
+func (bp *BlobPool) RunSubscriptions() {
+	if !bp.subscriptionsEnabled {
+		panic("subscriptions are disabled")
+	}
+	if !bp.subscriptionsOn.Load() {
+		bp.subscriptionsOn.Store(true)
+		go bp.notificationDispatcher()
+	}
+}
+
+// StopSubscriptions stops the blob pool events loop.
+func (bp *BlobPool) StopSubscriptions() {
+	if !bp.subscriptionsEnabled {
+		panic("subscriptions are disabled")
+	}
+	if bp.subscriptionsOn.Load() {
+		bp.subscriptionsOn.Store(false)
+		close(bp.stopCh)
+	}
+}
+
+// SubscribeForTransactions adds given channel to new blob pool event
+// broadcasting, same as Pool.SubscribeForTransactions.
+func (bp *BlobPool) SubscribeForTransactions(ch chan<- mempoolevent.Event) {
+	if bp.subscriptionsOn.Load() {
+		bp.subCh <- ch
+	}
+}
+
+// UnsubscribeFromTransactions unsubscribes given channel from blob pool
+// notifications, same as Pool.UnsubscribeFromTransactions.
+func (bp *BlobPool) UnsubscribeFromTransactions(ch chan<- mempoolevent.Event) {
+	if bp.subscriptionsOn.Load() {
+		bp.unsubCh <- ch
+	}
+}
+
+// notificationDispatcher manages subscription to events and broadcasts new events.
+func (bp *BlobPool) notificationDispatcher() {
+	var (
+		txFeed = make(map[chan<- mempoolevent.Event]bool)
+	)
+	for {
+		select {
+		case <-bp.stopCh:
+			return
+		case sub := <-bp.subCh:
+			txFeed[sub] = true
+		case unsub := <-bp.unsubCh:
+			delete(txFeed, unsub)
+		case event := <-bp.events:
+			for ch := range txFeed {
+				ch <- event
+			}
+		}
+	}
+}
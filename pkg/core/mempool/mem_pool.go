@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/core"
 	"github.com/multiverse-dev/saiya/pkg/core/mempoolevent"
 	"github.com/multiverse-dev/saiya/pkg/core/transaction"
 	"go.uber.org/atomic"
@@ -34,6 +35,21 @@ var (
 	// ErrOracleResponse is returned when mempool already contains transaction
 	// with the same oracle response ID and higher network fee.
 	ErrOracleResponse = errors.New("conflicts with memory pool due to OracleResponse attribute")
+	// ErrReplaceUnderpriced is returned when a transaction resubmitted at
+	// an already-occupied (sender, nonce) pair doesn't clear the pool's
+	// PriceBumpPercent over the transaction it would replace.
+	ErrReplaceUnderpriced = errors.New("replacement transaction underpriced")
+	// ErrNonceTooLow is returned when transaction's nonce is lower than
+	// the sender's current account nonce, i.e. it's already been spent.
+	ErrNonceTooLow = errors.New("nonce too low")
+	// ErrSenderLimit is returned when a sender already has as many
+	// pending and queued transactions in the pool as PerSenderLimit allows.
+	ErrSenderLimit = errors.New("sender has too many pending and queued transactions")
+	// ErrIntrinsicGas is returned when a transaction's Gas is below what
+	// core.IntrinsicGas requires for its calldata and access list, so it
+	// could never pay its own EIP-2930 storage warmup even before the EVM
+	// runs.
+	ErrIntrinsicGas = errors.New("intrinsic gas too low")
 )
 
 // item represents a transaction in the the Memory pool.
@@ -41,6 +57,28 @@ type item struct {
 	txn        *transaction.Transaction
 	blockStamp uint32
 	data       interface{}
+	// tip is this item's effective gas tip (see Transaction.EffectiveGasTip)
+	// as of the last time it was (re)computed against the chain's current
+	// base fee. It, not the raw GasPrice/MaxFeePerGas, is what decides
+	// ordering and fee-bump eviction, so a dynamic-fee transaction doesn't
+	// jump the queue just because its fee cap is high while its actual
+	// tip is low.
+	tip *big.Int
+}
+
+// refreshTip recomputes tip against feer's current base fee.
+func (it *item) refreshTip(feer Feer) {
+	it.tip = it.txn.EffectiveGasTip(feer.BaseFee())
+}
+
+// effectiveTip returns the item's cached tip if refreshTip has been called
+// for it, or computes one against a nil base fee otherwise (used for
+// ad-hoc lookup items that were never inserted into the pool).
+func (p item) effectiveTip() *big.Int {
+	if p.tip != nil {
+		return p.tip
+	}
+	return p.txn.EffectiveGasTip(nil)
 }
 
 // items is a slice of item.
@@ -60,9 +98,39 @@ type Pool struct {
 	verifiedTxes items
 	fees         map[common.Address]utilityBalanceAndFees
 
+	// parkedMap and parked hold transactions whose NotValidBefore height
+	// hasn't been reached yet, indexed by that target height so RemoveStale
+	// can cheaply find the buckets that are due for promotion into
+	// verifiedTxes. Each bucket is kept sorted the same way verifiedTxes is.
+	parkedMap map[common.Hash]*transaction.Transaction
+	parked    map[uint32]items
+
+	// senderQueued holds, per sender, transactions whose nonce is more
+	// than one ahead of that sender's next eligible nonce, keyed by
+	// nonce. They're promoted into verifiedTxes as the gap in front of
+	// them closes, either because the missing nonce arrives or because
+	// RemoveStale observes the chain has advanced past it.
+	senderQueued map[common.Address]map[uint64]item
+	// senderNextNonce is, per sender, the next nonce this pool will admit
+	// directly into verifiedTxes without queuing. It's seeded from Feer
+	// the first time a sender is seen and resynced in RemoveStale.
+	senderNextNonce map[common.Address]uint64
+	// senderPendingCount is, per sender, how many of its transactions are
+	// currently in verifiedTxes; together with len(senderQueued[addr]) it
+	// is bounded by perSenderLimit.
+	senderPendingCount map[common.Address]int
+
 	capacity   int
 	feePerByte uint64
 	payerIndex int
+	// priceBumpPercent is the minimum percentage by which a resubmitted
+	// transaction's fee must exceed the one it would replace at the same
+	// (sender, nonce), to prevent cheap churn. Defaults to 10.
+	priceBumpPercent int
+	// perSenderLimit bounds how many pending-plus-queued transactions a
+	// single sender may occupy, so one account can't fill the whole pool.
+	// Defaults to 64.
+	perSenderLimit int
 
 	resendThreshold uint32
 	resendFunc      func(*transaction.Transaction, interface{})
@@ -72,8 +140,9 @@ type Pool struct {
 	subscriptionsOn      atomic.Bool
 	stopCh               chan struct{}
 	events               chan mempoolevent.Event
-	subCh                chan chan<- mempoolevent.Event // there are no other events in mempool except Event, so no need in generic subscribers type
+	subCh                chan subscribeRequest
 	unsubCh              chan chan<- mempoolevent.Event
+	statsCh              chan statsRequest
 }
 
 func (p items) Len() int           { return len(p) }
@@ -86,7 +155,7 @@ func (p items) Less(i, j int) bool { return p[i].CompareTo(p[j]) < 0 }
 // difference > 0 implies p > otherP.
 func (p item) CompareTo(otherP item) int {
 	// Fees sorted ascending.
-	if ret := p.txn.GasPrice().Cmp(otherP.txn.GasPrice()); ret != 0 {
+	if ret := p.effectiveTip().Cmp(otherP.effectiveTip()); ret != 0 {
 		return ret
 	}
 	if p.txn.Gas() > otherP.txn.Gas() {
@@ -122,6 +191,9 @@ func (mp *Pool) containsKey(hash common.Hash) bool {
 	if _, ok := mp.verifiedMap[hash]; ok {
 		return true
 	}
+	if _, ok := mp.parkedMap[hash]; ok {
+		return true
+	}
 
 	return false
 }
@@ -175,43 +247,201 @@ func (mp *Pool) Add(t *transaction.Transaction, fee Feer, data ...interface{}) e
 		txn:        t,
 		blockStamp: fee.BlockHeight(),
 	}
+	pItem.refreshTip(fee)
 	if data != nil {
 		pItem.data = data[0]
 	}
+	payer := t.From()
+	nonce := t.Nonce()
+
+	intrinsic, err := core.IntrinsicGas(t.Data(), t.AccessList(), t.To() == nil)
+	if err != nil {
+		return err
+	}
+	if t.Gas() < intrinsic {
+		updateTxRejectedMetric(ErrIntrinsicGas)
+		return ErrIntrinsicGas
+	}
+
 	mp.lock.Lock()
 	if mp.containsKey(t.Hash()) {
 		mp.lock.Unlock()
+		updateTxRejectedMetric(ErrDup)
 		return ErrDup
 	}
+	next, ok := mp.senderNextNonce[payer]
+	if !ok {
+		next = fee.GetAccountNonce(payer)
+		mp.senderNextNonce[payer] = next
+	}
+	if nonce < next {
+		mp.lock.Unlock()
+		updateTxRejectedMetric(ErrNonceTooLow)
+		return ErrNonceTooLow
+	}
+	if mp.senderPendingCount[payer]+len(mp.senderQueued[payer]) >= mp.perSenderLimit {
+		mp.lock.Unlock()
+		updateTxRejectedMetric(ErrSenderLimit)
+		return ErrSenderLimit
+	}
+	if nvb := t.NotValidBefore(); nvb > fee.BlockHeight() {
+		mp.park(pItem, nvb)
+		mp.lock.Unlock()
+		return nil
+	}
+	if nonce > next+1 {
+		if err := mp.queue(pItem, fee); err != nil {
+			mp.lock.Unlock()
+			updateTxRejectedMetric(err)
+			return err
+		}
+		mp.lock.Unlock()
+		return nil
+	}
 	conflict, err := mp.checkTxConflicts(t, fee)
 	if err != nil {
 		mp.lock.Unlock()
+		updateTxRejectedMetric(err)
 		return err
 	}
 	if conflict != nil {
 		mp.removeInternal(conflict.Hash(), fee)
 	}
-	// Insert into sorted array (from max to min, that could also be done
-	// using sort.Sort(sort.Reverse()), but it incurs more overhead. Notice
-	// also that we're searching for position that is strictly more
-	// prioritized than our new item because we do expect a lot of
-	// transactions with the same priority and appending to the end of the
-	// slice is always more efficient.
+	if !mp.insertVerified(pItem) {
+		mp.lock.Unlock()
+		updateTxRejectedMetric(ErrOOM)
+		return ErrOOM
+	}
+	// we already checked balance in checkTxConflicts, so don't need to check again
+	mp.tryAddSendersFee(pItem.txn, fee, false)
+	mp.senderPendingCount[payer]++
+	if nonce >= mp.senderNextNonce[payer] {
+		mp.senderNextNonce[payer] = nonce + 1
+	}
+	mp.promoteQueued(payer, fee)
+
+	updateMempoolMetrics(len(mp.verifiedTxes))
+	updateMempoolSizeBytesMetric(t.Size())
+	updateSenderCountMetric(payer.String(), mp.senderPendingCount[payer]+len(mp.senderQueued[payer]))
+	updateTxAcceptedMetric()
+	mp.lock.Unlock()
+
+	if mp.subscriptionsOn.Load() {
+		mp.events <- mempoolevent.Event{
+			Type: mempoolevent.TransactionAdded,
+			Tx:   pItem.txn,
+			Data: pItem.data,
+		}
+	}
+	return nil
+}
+
+// meetsPriceBump reports whether newTx's fees clear mp.priceBumpPercent
+// over oldTx's, on both the effective tip and the fee cap, so a
+// fractional-wei resubmission can't churn a pooled transaction out for
+// free.
+func (mp *Pool) meetsPriceBump(newTx, oldTx *transaction.Transaction, baseFee *big.Int) bool {
+	bump := big.NewInt(int64(100 + mp.priceBumpPercent))
+	hundred := big.NewInt(100)
+
+	requiredTip := new(big.Int).Mul(oldTx.EffectiveGasTip(baseFee), bump)
+	requiredTip.Div(requiredTip, hundred)
+	if newTx.EffectiveGasTip(baseFee).Cmp(requiredTip) < 0 {
+		return false
+	}
+
+	requiredCap := new(big.Int).Mul(oldTx.GasFeeCap(), bump)
+	requiredCap.Div(requiredCap, hundred)
+	return newTx.GasFeeCap().Cmp(requiredCap) >= 0
+}
+
+// queue places pItem into its sender's queued bucket, for transactions
+// whose nonce is more than one ahead of the sender's next eligible nonce.
+// A resubmission at an already-queued nonce replaces the queued entry
+// only if it clears the price bump over it, same as a pending replacement
+// would.
+func (mp *Pool) queue(pItem item, fee Feer) error {
+	payer := pItem.txn.From()
+	nonce := pItem.txn.Nonce()
+	bucket, ok := mp.senderQueued[payer]
+	if !ok {
+		bucket = make(map[uint64]item)
+		mp.senderQueued[payer] = bucket
+	}
+	if existing, ok := bucket[nonce]; ok && !mp.meetsPriceBump(pItem.txn, existing.txn, fee.BaseFee()) {
+		return ErrReplaceUnderpriced
+	}
+	bucket[nonce] = pItem
+	return nil
+}
+
+// promoteQueued admits any contiguous run of queued transactions for payer
+// that starts at the sender's current next-eligible nonce, cascading for
+// as long as the following nonce is already queued too.
+func (mp *Pool) promoteQueued(payer common.Address, fee Feer) {
+	bucket, ok := mp.senderQueued[payer]
+	if !ok {
+		return
+	}
+	for {
+		next := mp.senderNextNonce[payer]
+		qItem, ok := bucket[next]
+		if !ok {
+			break
+		}
+		delete(bucket, next)
+		qItem.refreshTip(fee)
+		conflict, err := mp.checkTxConflicts(qItem.txn, fee)
+		if err != nil {
+			continue
+		}
+		if conflict != nil {
+			mp.removeInternal(conflict.Hash(), fee)
+		}
+		if !mp.insertVerified(qItem) {
+			continue
+		}
+		mp.tryAddSendersFee(qItem.txn, fee, false)
+		mp.senderPendingCount[payer]++
+		mp.senderNextNonce[payer] = next + 1
+		if mp.subscriptionsOn.Load() {
+			mp.events <- mempoolevent.Event{
+				Type: mempoolevent.TransactionAdded,
+				Tx:   qItem.txn,
+				Data: qItem.data,
+			}
+		}
+	}
+	if len(bucket) == 0 {
+		delete(mp.senderQueued, payer)
+	}
+}
+
+// SenderStats returns the number of payer's transactions currently pending
+// (admitted into the main queue) and queued (waiting on an earlier nonce).
+func (mp *Pool) SenderStats(addr common.Address) (pending, queued uint64) {
+	mp.lock.RLock()
+	defer mp.lock.RUnlock()
+	return uint64(mp.senderPendingCount[addr]), uint64(len(mp.senderQueued[addr]))
+}
+
+// insertVerified inserts pItem into the sorted main queue (from max to min
+// priority), evicting the lowest-priority
+// entry if the pool is already at capacity. It reports whether pItem was
+// admitted; it's false only when the pool is full and pItem is the least
+// prioritized transaction of all, including itself.
+func (mp *Pool) insertVerified(pItem item) bool {
 	n := sort.Search(len(mp.verifiedTxes), func(n int) bool {
 		return pItem.CompareTo(mp.verifiedTxes[n]) > 0
 	})
-
-	// We've reached our capacity already.
 	if len(mp.verifiedTxes) == mp.capacity {
-		// Less prioritized than the least prioritized we already have, won't fit.
 		if n == len(mp.verifiedTxes) {
-			mp.lock.Unlock()
-			return ErrOOM
+			return false
 		}
-		// Ditch the last one.
 		unlucky := mp.verifiedTxes[len(mp.verifiedTxes)-1]
 		delete(mp.verifiedMap, unlucky.txn.Hash())
-
+		mp.senderPendingCount[unlucky.txn.From()]--
+		updateMempoolSizeBytesMetric(-unlucky.txn.Size())
 		mp.verifiedTxes[len(mp.verifiedTxes)-1] = pItem
 		if mp.subscriptionsOn.Load() {
 			mp.events <- mempoolevent.Event{
@@ -227,21 +457,70 @@ func (mp *Pool) Add(t *transaction.Transaction, fee Feer, data ...interface{}) e
 		copy(mp.verifiedTxes[n+1:], mp.verifiedTxes[n:])
 		mp.verifiedTxes[n] = pItem
 	}
-	mp.verifiedMap[t.Hash()] = t
-	// we already checked balance in checkTxConflicts, so don't need to check again
-	mp.tryAddSendersFee(pItem.txn, fee, false)
+	mp.verifiedMap[pItem.txn.Hash()] = pItem.txn
+	return true
+}
 
-	updateMempoolMetrics(len(mp.verifiedTxes))
-	mp.lock.Unlock()
+// park places a transaction whose NotValidBefore height hasn't been reached
+// yet into the secondary NVB queue, bucketed by that target height so
+// RemoveStale can promote it once the chain catches up. It's subject to the
+// same capacity and fee ordering as the main queue: parking it past capacity
+// evicts the least prioritized parked transaction across all buckets instead.
+func (mp *Pool) park(pItem item, targetHeight uint32) {
+	bucket := mp.parked[targetHeight]
+	n := sort.Search(len(bucket), func(n int) bool {
+		return pItem.CompareTo(bucket[n]) > 0
+	})
+	bucket = append(bucket, item{})
+	copy(bucket[n+1:], bucket[n:])
+	bucket[n] = pItem
+	mp.parked[targetHeight] = bucket
+	mp.parkedMap[pItem.txn.Hash()] = pItem.txn
+	if len(mp.parkedMap) > mp.capacity {
+		mp.evictWeakestParked()
+	}
+}
 
-	if mp.subscriptionsOn.Load() {
-		mp.events <- mempoolevent.Event{
-			Type: mempoolevent.TransactionAdded,
-			Tx:   pItem.txn,
-			Data: pItem.data,
+// evictWeakestParked drops the least prioritized transaction across all NVB
+// buckets.
+func (mp *Pool) evictWeakestParked() {
+	var (
+		weakestHeight uint32
+		found         bool
+	)
+	for h, bucket := range mp.parked {
+		if len(bucket) == 0 {
+			continue
+		}
+		if !found || bucket[len(bucket)-1].CompareTo(mp.parked[weakestHeight][len(mp.parked[weakestHeight])-1]) < 0 {
+			weakestHeight = h
+			found = true
 		}
 	}
-	return nil
+	if !found {
+		return
+	}
+	bucket := mp.parked[weakestHeight]
+	unlucky := bucket[len(bucket)-1]
+	bucket = bucket[:len(bucket)-1]
+	if len(bucket) == 0 {
+		delete(mp.parked, weakestHeight)
+	} else {
+		mp.parked[weakestHeight] = bucket
+	}
+	delete(mp.parkedMap, unlucky.txn.Hash())
+}
+
+// GetParkedTransactions returns the transactions currently parked pending
+// their NotValidBefore height being reached.
+func (mp *Pool) GetParkedTransactions() []*transaction.Transaction {
+	mp.lock.RLock()
+	defer mp.lock.RUnlock()
+	txs := make([]*transaction.Transaction, 0, len(mp.parkedMap))
+	for _, tx := range mp.parkedMap {
+		txs = append(txs, tx)
+	}
+	return txs
 }
 
 // Remove removes an item from the mempool, if it exists there (and does
@@ -272,6 +551,9 @@ func (mp *Pool) removeInternal(hash common.Hash, feer Feer) {
 		senderFee := mp.fees[payer]
 		(&senderFee.feeSum).Sub(&senderFee.feeSum, tx.Cost())
 		mp.fees[payer] = senderFee
+		mp.senderPendingCount[payer]--
+		updateMempoolSizeBytesMetric(-itm.txn.Size())
+		updateSenderCountMetric(payer.String(), mp.senderPendingCount[payer]+len(mp.senderQueued[payer]))
 		if mp.subscriptionsOn.Load() {
 			mp.events <- mempoolevent.Event{
 				Type: mempoolevent.TransactionRemoved,
@@ -293,13 +575,28 @@ func (mp *Pool) RemoveStale(isOK func(*transaction.Transaction) bool, feer Feer)
 	// because items are iterated one-by-one in increasing order.
 	newVerifiedTxes := mp.verifiedTxes[:0]
 	mp.fees = make(map[common.Address]utilityBalanceAndFees) // it'd be nice to reuse existing map, but we can't easily clear it
+	mp.senderPendingCount = make(map[common.Address]int)
 	height := feer.BlockHeight()
 	var (
 		staleItems []item
+		// maxPendingNonce and touched track, per sender seen this round,
+		// the highest nonce it still has pending and whether it has any,
+		// so senderNextNonce can be resynced below without losing track
+		// of nonces the chain doesn't know about yet.
+		maxPendingNonce = make(map[common.Address]uint64)
+		hasPending      = make(map[common.Address]bool)
+		touched         = make(map[common.Address]struct{})
 	)
 	for _, itm := range mp.verifiedTxes {
+		payer := itm.txn.From()
+		touched[payer] = struct{}{}
 		if isOK(itm.txn) && mp.checkPolicy(itm.txn, policyChanged) && mp.tryAddSendersFee(itm.txn, feer, true) {
 			newVerifiedTxes = append(newVerifiedTxes, itm)
+			mp.senderPendingCount[payer]++
+			if n := itm.txn.Nonce(); !hasPending[payer] || n > maxPendingNonce[payer] {
+				maxPendingNonce[payer] = n
+				hasPending[payer] = true
+			}
 			if mp.resendThreshold != 0 {
 				// item is resend at resendThreshold, 2*resendThreshold, 4*resendThreshold ...
 				// so quotient must be a power of two.
@@ -319,10 +616,79 @@ func (mp *Pool) RemoveStale(isOK func(*transaction.Transaction) bool, feer Feer)
 			}
 		}
 	}
+	mp.verifiedTxes = newVerifiedTxes
+
+	// Promote parked transactions whose NotValidBefore height has been
+	// reached into the main queue, firing a single TransactionAdded event
+	// per tx so subscribers never see more than one admission event.
+	for h, bucket := range mp.parked {
+		if h > height {
+			continue
+		}
+		delete(mp.parked, h)
+		for _, itm := range bucket {
+			delete(mp.parkedMap, itm.txn.Hash())
+			if !isOK(itm.txn) || !mp.tryAddSendersFee(itm.txn, feer, true) {
+				continue
+			}
+			itm.refreshTip(feer)
+			if !mp.insertVerified(itm) {
+				continue
+			}
+			payer := itm.txn.From()
+			touched[payer] = struct{}{}
+			mp.senderPendingCount[payer]++
+			if n := itm.txn.Nonce(); !hasPending[payer] || n > maxPendingNonce[payer] {
+				maxPendingNonce[payer] = n
+				hasPending[payer] = true
+			}
+			if mp.subscriptionsOn.Load() {
+				mp.events <- mempoolevent.Event{
+					Type: mempoolevent.TransactionAdded,
+					Tx:   itm.txn,
+					Data: itm.data,
+				}
+			}
+		}
+	}
+
+	// Resync each sender touched this round (plus any with something
+	// still queued) against the chain's account nonce, drop now-stale
+	// queued entries below it, and promote whatever gap that closes.
+	for addr := range mp.senderQueued {
+		touched[addr] = struct{}{}
+	}
+	for addr := range touched {
+		next := feer.GetAccountNonce(addr)
+		if hasPending[addr] && maxPendingNonce[addr]+1 > next {
+			next = maxPendingNonce[addr] + 1
+		}
+		mp.senderNextNonce[addr] = next
+		if bucket, ok := mp.senderQueued[addr]; ok {
+			for nonce := range bucket {
+				if nonce < next {
+					delete(bucket, nonce)
+				}
+			}
+			if len(bucket) == 0 {
+				delete(mp.senderQueued, addr)
+			}
+		}
+		mp.promoteQueued(addr, feer)
+	}
+
 	if len(staleItems) != 0 {
 		go mp.resendStaleItems(staleItems)
 	}
-	mp.verifiedTxes = newVerifiedTxes
+	var totalBytes int
+	for _, itm := range mp.verifiedTxes {
+		totalBytes += itm.txn.Size()
+	}
+	mempoolSizeBytes.Set(float64(totalBytes))
+	for addr := range touched {
+		updateSenderCountMetric(addr.String(), mp.senderPendingCount[addr]+len(mp.senderQueued[addr]))
+	}
+	updateMempoolMetrics(len(mp.verifiedTxes))
 	mp.lock.Unlock()
 }
 
@@ -350,14 +716,22 @@ func New(capacity int, payerIndex int, enableSubscriptions bool) *Pool {
 	mp := &Pool{
 		verifiedMap:          make(map[common.Hash]*transaction.Transaction, capacity),
 		verifiedTxes:         make([]item, 0, capacity),
+		parkedMap:            make(map[common.Hash]*transaction.Transaction),
+		parked:               make(map[uint32]items),
+		senderQueued:         make(map[common.Address]map[uint64]item),
+		senderNextNonce:      make(map[common.Address]uint64),
+		senderPendingCount:   make(map[common.Address]int),
 		capacity:             capacity,
 		payerIndex:           payerIndex,
+		priceBumpPercent:     10,
+		perSenderLimit:       64,
 		fees:                 make(map[common.Address]utilityBalanceAndFees),
 		subscriptionsEnabled: enableSubscriptions,
 		stopCh:               make(chan struct{}),
 		events:               make(chan mempoolevent.Event),
-		subCh:                make(chan chan<- mempoolevent.Event),
+		subCh:                make(chan subscribeRequest),
 		unsubCh:              make(chan chan<- mempoolevent.Event),
+		statsCh:              make(chan statsRequest),
 	}
 	mp.subscriptionsOn.Store(false)
 	return mp
@@ -372,6 +746,24 @@ func (mp *Pool) SetResendThreshold(h uint32, f func(*transaction.Transaction, in
 	mp.resendFunc = f
 }
 
+// SetPriceBumpPercent sets the minimum percentage by which a replacement
+// transaction's fee must exceed the one it's replacing at the same
+// (sender, nonce). The default, set by New, is 10.
+func (mp *Pool) SetPriceBumpPercent(percent int) {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+	mp.priceBumpPercent = percent
+}
+
+// SetSenderLimit sets the maximum number of pending-plus-queued
+// transactions a single sender may occupy in the pool. The default, set
+// by New, is 64.
+func (mp *Pool) SetSenderLimit(limit int) {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+	mp.perSenderLimit = limit
+}
+
 func (mp *Pool) resendStaleItems(items []item) {
 	for i := range items {
 		mp.resendFunc(items[i].txn, items[i].data)
@@ -436,16 +828,37 @@ func (mp *Pool) checkTxConflicts(tx *transaction.Transaction, fee Feer) (*transa
 		actualSenderFee.balance = *(fee.GetUtilityTokenBalance(payer))
 	}
 	var expectedSenderFee = actualSenderFee
-	// Check Conflicts attributes.
 	var conflictToBeRemoved *transaction.Transaction
+	baseFee := fee.BaseFee()
+	// Same sender resubmitting the same nonce replaces the pooled tx only
+	// if it clears PriceBumpPercent over it, on both tip and fee cap;
+	// otherwise the resubmission is rejected outright rather than sitting
+	// alongside the original at the same nonce.
 	for _, existTx := range mp.verifiedMap {
 		if existTx.From() == tx.From() && existTx.Nonce() == tx.Nonce() {
-			if existTx.GasPrice().Cmp(tx.GasPrice()) < 0 {
-				conflictToBeRemoved = existTx
-				(&expectedSenderFee.feeSum).Sub(&expectedSenderFee.feeSum, existTx.Cost())
-				break
+			if !mp.meetsPriceBump(tx, existTx, baseFee) {
+				return nil, ErrReplaceUnderpriced
 			}
+			conflictToBeRemoved = existTx
+			(&expectedSenderFee.feeSum).Sub(&expectedSenderFee.feeSum, existTx.Cost())
+			break
+		}
+	}
+	// Check Conflicts attribute: the incoming tx may name a pooled tx it
+	// refuses to coexist with. Only evict it if the two share a sender
+	// (otherwise anyone could evict someone else's transaction by merely
+	// naming its hash) and the incoming tx pays a strictly higher
+	// effective fee.
+	for _, h := range tx.Conflicts() {
+		existTx, ok := mp.verifiedMap[h]
+		if !ok || existTx.From() != tx.From() {
+			continue
+		}
+		if tx.EffectiveGasTip(baseFee).Cmp(existTx.EffectiveGasTip(baseFee)) <= 0 {
+			return nil, ErrConflictsAttribute
 		}
+		conflictToBeRemoved = existTx
+		(&expectedSenderFee.feeSum).Sub(&expectedSenderFee.feeSum, existTx.Cost())
 	}
 	_, err := checkBalance(tx, expectedSenderFee)
 	return conflictToBeRemoved, err
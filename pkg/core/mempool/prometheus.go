@@ -0,0 +1,123 @@
+package mempool
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics for monitoring the mempool.
+var (
+	//mempoolCount prometheus metric.
+	mempoolCount = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Number of verified transactions currently in the pool",
+			Name:      "mempool_count",
+			Namespace: "saiya",
+		},
+	)
+	//mempoolSizeBytes prometheus metric.
+	mempoolSizeBytes = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Total size in bytes of verified transactions currently in the pool",
+			Name:      "mempool_size_bytes",
+			Namespace: "saiya",
+		},
+	)
+	//mempoolSenderCount prometheus metric.
+	mempoolSenderCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Help:      "Number of pending-plus-queued transactions in the pool for a given sender",
+			Name:      "mempool_sender_count",
+			Namespace: "saiya",
+		},
+		[]string{"sender"},
+	)
+	//mempoolTxAccepted prometheus metric.
+	mempoolTxAccepted = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Help:      "Total number of transactions accepted into the pool",
+			Name:      "mempool_tx_accepted_total",
+			Namespace: "saiya",
+		},
+	)
+	//mempoolTxRejected prometheus metric.
+	mempoolTxRejected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Total number of transactions rejected from the pool, by reason",
+			Name:      "mempool_tx_rejected_total",
+			Namespace: "saiya",
+		},
+		[]string{"reason"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		mempoolCount,
+		mempoolSizeBytes,
+		mempoolSenderCount,
+		mempoolTxAccepted,
+		mempoolTxRejected,
+	)
+}
+
+// MetricsEnabled gates every update*Metric call below, so a node built
+// from config.Metrics.DisableMempool can opt this family out. Defaults to
+// on; there's no node-startup code in this snapshot to flip it from
+// ApplicationConfiguration.Metrics itself (same gap as the rest of
+// cmd/node-level wiring), so whatever constructs a Pool is expected to set
+// it once up front.
+var MetricsEnabled = true
+
+// updateMempoolMetrics reports count as the current number of verified
+// transactions in the pool.
+func updateMempoolMetrics(count int) {
+	if !MetricsEnabled {
+		return
+	}
+	mempoolCount.Set(float64(count))
+}
+
+// rejectReason labels mempoolTxRejected's "reason" dimension with the
+// handful of Add outcomes worth distinguishing on a dashboard; every other
+// error returned by Add (ErrDup, ErrOOM, etc.) isn't a per-sender-actionable
+// policy decision the way these are, so it is counted under "other".
+func rejectReason(err error) string {
+	switch err {
+	case ErrInsufficientFunds, ErrConflict:
+		return "insufficient_funds"
+	case ErrNonceTooLow:
+		return "bad_nonce"
+	case ErrIntrinsicGas:
+		return "gas_too_low"
+	default:
+		return "other"
+	}
+}
+
+func updateTxAcceptedMetric() {
+	if !MetricsEnabled {
+		return
+	}
+	mempoolTxAccepted.Inc()
+}
+
+func updateTxRejectedMetric(err error) {
+	if !MetricsEnabled {
+		return
+	}
+	mempoolTxRejected.WithLabelValues(rejectReason(err)).Inc()
+}
+
+func updateSenderCountMetric(sender string, pendingPlusQueued int) {
+	if !MetricsEnabled {
+		return
+	}
+	mempoolSenderCount.WithLabelValues(sender).Set(float64(pendingPlusQueued))
+}
+
+func updateMempoolSizeBytesMetric(sizeDelta int) {
+	if !MetricsEnabled {
+		return
+	}
+	mempoolSizeBytes.Add(float64(sizeDelta))
+}
@@ -0,0 +1,57 @@
+package mempool
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/core/transaction"
+)
+
+// Stats summarizes a SubPool's current contents for metrics and RPC
+// reporting, independent of how the pool organizes its internal indices.
+type Stats struct {
+	Pending int
+	Parked  int
+}
+
+// SubPool is the common surface Pool and BlobPool both implement, so the
+// node can dispatch an incoming transaction to whichever one understands
+// its typed envelope without caring how that pool organizes itself
+// internally.
+type SubPool interface {
+	// Add validates and admits t, using fee to price it against the
+	// current chain state.
+	Add(t *transaction.Transaction, fee Feer, data ...interface{}) error
+	// Remove drops the transaction with the given hash from the pool, if
+	// present.
+	Remove(hash common.Hash, feer Feer)
+	// Get returns the pooled transaction with the given hash, if any.
+	Get(hash common.Hash) (*transaction.Transaction, bool)
+	// Pending returns the transactions currently eligible for inclusion
+	// in the next block.
+	Pending() []*transaction.Transaction
+	// Stats reports the pool's current size.
+	Stats() Stats
+}
+
+var _ SubPool = (*Pool)(nil)
+
+// Get is Pool's SubPool implementation of Get; it's the same lookup as
+// TryGetValue.
+func (mp *Pool) Get(hash common.Hash) (*transaction.Transaction, bool) {
+	return mp.TryGetValue(hash)
+}
+
+// Pending is Pool's SubPool implementation of Pending; it's an alias for
+// GetVerifiedTransactions kept so Pool satisfies SubPool.
+func (mp *Pool) Pending() []*transaction.Transaction {
+	return mp.GetVerifiedTransactions()
+}
+
+// Stats is Pool's SubPool implementation of Stats.
+func (mp *Pool) Stats() Stats {
+	mp.lock.RLock()
+	defer mp.lock.RUnlock()
+	return Stats{
+		Pending: len(mp.verifiedTxes),
+		Parked:  len(mp.parkedMap),
+	}
+}
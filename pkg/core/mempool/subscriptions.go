@@ -2,6 +2,156 @@ package mempool
 
 import "github.com/multiverse-dev/saiya/pkg/core/mempoolevent"
 
+// subscriberBufferSize is the capacity of each subscriber's ring buffer.
+// notificationDispatcher never sends on a subscriber's channel directly;
+// it only ever pushes into this buffer, which the subscriber's own
+// goroutine drains at its own pace.
+const subscriberBufferSize = 256
+
+// DropPolicy controls what happens to a subscriber that can't keep up,
+// i.e. one whose ring buffer is already full when a new matching Event
+// arrives.
+type DropPolicy byte
+
+const (
+	// DropOldest discards the oldest buffered Event to make room for the
+	// new one. This is what SubscribeForTransactions uses.
+	DropOldest DropPolicy = iota
+	// DropNewest discards the incoming Event, keeping the buffer as is.
+	DropNewest
+	// Disconnect evicts the subscriber: it's unsubscribed and sent a
+	// final mempoolevent.SubscriberEvicted Event on a best-effort basis.
+	Disconnect
+)
+
+// subscriber wraps a subscription's channel with a bounded ring buffer and
+// the goroutine that drains it, so that a slow or stuck consumer only ever
+// blocks its own goroutine, never notificationDispatcher.
+type subscriber struct {
+	ch     chan<- mempoolevent.Event
+	filter mempoolevent.Filter
+	policy DropPolicy
+
+	buf  []mempoolevent.Event
+	head int
+	size int
+
+	delivered uint64
+	dropped   uint64
+
+	notify chan struct{}
+	stopCh chan struct{}
+}
+
+func newSubscriber(ch chan<- mempoolevent.Event, filter mempoolevent.Filter, policy DropPolicy) *subscriber {
+	return &subscriber{
+		ch:     ch,
+		filter: filter,
+		policy: policy,
+		buf:    make([]mempoolevent.Event, subscriberBufferSize),
+		notify: make(chan struct{}, 1),
+		stopCh: make(chan struct{}),
+	}
+}
+
+// push enqueues e for delivery, applying s.policy if the ring buffer is
+// already full. It reports whether s should be evicted, which only ever
+// happens under Disconnect. push is only ever called from
+// notificationDispatcher and never blocks.
+func (s *subscriber) push(e mempoolevent.Event) (evict bool) {
+	if s.size == len(s.buf) {
+		switch s.policy {
+		case DropNewest:
+			s.dropped++
+			return false
+		case Disconnect:
+			s.dropped++
+			return true
+		default: // DropOldest
+			s.head = (s.head + 1) % len(s.buf)
+			s.size--
+			s.dropped++
+		}
+	}
+	s.buf[(s.head+s.size)%len(s.buf)] = e
+	s.size++
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+	return false
+}
+
+// pop removes and returns the oldest buffered Event, if any. It's only
+// ever called from s.run.
+func (s *subscriber) pop() (mempoolevent.Event, bool) {
+	if s.size == 0 {
+		return mempoolevent.Event{}, false
+	}
+	e := s.buf[s.head]
+	s.head = (s.head + 1) % len(s.buf)
+	s.size--
+	return e, true
+}
+
+// run delivers buffered events to s.ch until s.stopCh is closed. Apart from
+// evict's single final send, made from notificationDispatcher, it's the
+// only goroutine that sends on s.ch.
+func (s *subscriber) run() {
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-s.notify:
+		}
+		for {
+			e, ok := s.pop()
+			if !ok {
+				break
+			}
+			select {
+			case s.ch <- e:
+				s.delivered++
+			case <-s.stopCh:
+				return
+			}
+		}
+	}
+}
+
+// evict attempts a single best-effort, non-blocking send of a
+// mempoolevent.SubscriberEvicted Event, then stops s.run.
+func (s *subscriber) evict() {
+	select {
+	case s.ch <- mempoolevent.Event{Type: mempoolevent.SubscriberEvicted}:
+	default:
+	}
+	close(s.stopCh)
+}
+
+// subscribeRequest is what SubscribeForTransactions(Filtered) sends on
+// Pool.subCh for notificationDispatcher to pick up.
+type subscribeRequest struct {
+	ch     chan<- mempoolevent.Event
+	filter mempoolevent.Filter
+	policy DropPolicy
+}
+
+// statsRequest is what SubscriberStats sends on Pool.statsCh for
+// notificationDispatcher to answer, since the subscriber set is owned
+// exclusively by that goroutine.
+type statsRequest struct {
+	ch     chan<- mempoolevent.Event
+	result chan<- SubscriberStats
+}
+
+// SubscriberStats reports how many events a subscriber has had delivered
+// and dropped since it subscribed.
+type SubscriberStats struct {
+	Delivered uint64
+	Dropped   uint64
+}
+
 // RunSubscriptions runs subscriptions goroutine if mempool subscriptions are enabled.
 // You should manually free the resources by calling StopSubscriptions on mempool shutdown.
 func (mp *Pool) RunSubscriptions() {
@@ -27,10 +177,19 @@ func (mp *Pool) StopSubscriptions() {
 
 // SubscribeForTransactions adds given channel to new mempool event broadcasting, so when
 // there is a new transactions added to mempool or an existing transaction removed from
-// mempool you'll receive it via this channel.
+// mempool you'll receive it via this channel. It's equivalent to calling
+// SubscribeForTransactionsFiltered with a zero-value Filter (matches every
+// Event) and DropOldest.
 func (mp *Pool) SubscribeForTransactions(ch chan<- mempoolevent.Event) {
+	mp.SubscribeForTransactionsFiltered(ch, mempoolevent.Filter{}, DropOldest)
+}
+
+// SubscribeForTransactionsFiltered is like SubscribeForTransactions, but
+// only delivers Events matching filter, and applies policy to ch's bounded
+// ring buffer once it fills up instead of ever blocking the dispatcher.
+func (mp *Pool) SubscribeForTransactionsFiltered(ch chan<- mempoolevent.Event, filter mempoolevent.Filter, policy DropPolicy) {
 	if mp.subscriptionsOn.Load() {
-		mp.subCh <- ch
+		mp.subCh <- subscribeRequest{ch: ch, filter: filter, policy: policy}
 	}
 }
 
@@ -42,25 +201,56 @@ func (mp *Pool) UnsubscribeFromTransactions(ch chan<- mempoolevent.Event) {
 	}
 }
 
+// SubscriberStats returns the delivered/dropped counters for ch, and
+// whether ch is currently subscribed.
+func (mp *Pool) SubscriberStats(ch chan<- mempoolevent.Event) (SubscriberStats, bool) {
+	if !mp.subscriptionsOn.Load() {
+		return SubscriberStats{}, false
+	}
+	result := make(chan SubscriberStats, 1)
+	mp.statsCh <- statsRequest{ch: ch, result: result}
+	stats, ok := <-result
+	return stats, ok
+}
+
 // notificationDispatcher manages subscription to events and broadcasts new events.
+// It never blocks on a subscriber's channel: delivery to each subscriber
+// happens on that subscriber's own goroutine, draining a bounded ring
+// buffer that notificationDispatcher only ever pushes into.
 func (mp *Pool) notificationDispatcher() {
-	var (
-		// These are just sets of subscribers, though modelled as maps
-		// for ease of management (not a lot of subscriptions is really
-		// expected, but maps are convenient for adding/deleting elements).
-		txFeed = make(map[chan<- mempoolevent.Event]bool)
-	)
+	subs := make(map[chan<- mempoolevent.Event]*subscriber)
 	for {
 		select {
 		case <-mp.stopCh:
+			for _, sub := range subs {
+				close(sub.stopCh)
+			}
 			return
-		case sub := <-mp.subCh:
-			txFeed[sub] = true
+		case req := <-mp.subCh:
+			sub := newSubscriber(req.ch, req.filter, req.policy)
+			subs[req.ch] = sub
+			go sub.run()
 		case unsub := <-mp.unsubCh:
-			delete(txFeed, unsub)
+			if sub, ok := subs[unsub]; ok {
+				delete(subs, unsub)
+				close(sub.stopCh)
+			}
+		case req := <-mp.statsCh:
+			sub, ok := subs[req.ch]
+			if !ok {
+				close(req.result)
+				continue
+			}
+			req.result <- SubscriberStats{Delivered: sub.delivered, Dropped: sub.dropped}
 		case event := <-mp.events:
-			for ch := range txFeed {
-				ch <- event
+			for ch, sub := range subs {
+				if !sub.filter.Match(event) {
+					continue
+				}
+				if sub.push(event) {
+					delete(subs, ch)
+					sub.evict()
+				}
 			}
 		}
 	}
@@ -0,0 +1,47 @@
+// Package mempoolevent defines the notification pkg/core/mempool's
+// subscription feed delivers to subscribers of Pool.SubscribeForTransactions
+// and Pool.SubscribeForTransactionsFiltered: a transaction was added to or
+// removed from the pool.
+package mempoolevent
+
+import "github.com/multiverse-dev/saiya/pkg/core/transaction"
+
+// Type is the kind of change an Event reports.
+type Type byte
+
+const (
+	// TransactionAdded is fired when a new transaction enters the pool.
+	TransactionAdded Type = iota
+	// TransactionRemoved is fired when a transaction leaves the pool,
+	// whether because it was included in a block, evicted to make room
+	// for a higher-priority one, or invalidated by a conflicting tx.
+	TransactionRemoved
+	// SubscriberEvicted is the final Event a subscriber receives, on a
+	// best-effort basis, when it's dropped for falling too far behind
+	// under the mempool.Disconnect drop policy. Tx and Data are unset.
+	SubscriberEvicted
+)
+
+// String implements fmt.Stringer.
+func (t Type) String() string {
+	switch t {
+	case TransactionAdded:
+		return "added"
+	case TransactionRemoved:
+		return "removed"
+	case SubscriberEvicted:
+		return "evicted"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a single mempool change. Data carries whatever extra value the
+// caller passed to Pool.Add (e.g. a verification context), or nil if none
+// was passed. Pool repeats it on that transaction's TransactionRemoved
+// event; it's nil on SubscriberEvicted.
+type Event struct {
+	Type Type
+	Tx   *transaction.Transaction
+	Data interface{}
+}
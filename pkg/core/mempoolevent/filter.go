@@ -0,0 +1,76 @@
+package mempoolevent
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Types is a bitmask of Type values, used by Filter.Types to restrict
+// delivery to a subset of event kinds.
+type Types uint8
+
+const (
+	TypeAdded   Types = 1 << TransactionAdded
+	TypeRemoved Types = 1 << TransactionRemoved
+	// TypeAll is the zero-value behavior: every Type matches.
+	TypeAll Types = TypeAdded | TypeRemoved
+)
+
+// Filter narrows the Events a subscriber receives from
+// Pool.SubscribeForTransactionsFiltered. Every non-zero field must match
+// for an Event to pass; a zero-value Filter matches everything.
+type Filter struct {
+	// Types restricts delivery to the given Type(s) via their bit in the
+	// mask; zero means TypeAll.
+	Types Types
+	// Senders restricts delivery to transactions sent by one of these
+	// addresses; empty means any sender.
+	Senders []common.Address
+	// To restricts delivery to transactions addressed to one of these
+	// contracts; empty means any destination, including contract creation.
+	To []common.Address
+	// MinFee and MaxFee bound the transaction's GasFeeCap; a nil bound is
+	// unset.
+	MinFee *big.Int
+	MaxFee *big.Int
+}
+
+// Match reports whether e satisfies f. A zero-value Filter matches every
+// Event.
+func (f *Filter) Match(e Event) bool {
+	if f == nil {
+		return true
+	}
+	if f.Types != 0 && f.Types&(1<<e.Type) == 0 {
+		return false
+	}
+	if len(f.Senders) != 0 && !containsAddress(f.Senders, e.Tx.From()) {
+		return false
+	}
+	if len(f.To) != 0 {
+		to := e.Tx.To()
+		if to == nil || !containsAddress(f.To, *to) {
+			return false
+		}
+	}
+	if f.MinFee != nil || f.MaxFee != nil {
+		fee := e.Tx.GasFeeCap()
+		if f.MinFee != nil && fee.Cmp(f.MinFee) < 0 {
+			return false
+		}
+		if f.MaxFee != nil && fee.Cmp(f.MaxFee) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAddress(list []common.Address, addr common.Address) bool {
+	for _, a := range list {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
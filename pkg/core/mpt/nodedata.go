@@ -0,0 +1,87 @@
+package mpt
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/core/storage"
+	"github.com/multiverse-dev/saiya/pkg/crypto/hash"
+	"github.com/multiverse-dev/saiya/pkg/io"
+)
+
+// PutNode stores the raw encoded node data under its own hash, the same way
+// a Trie looks nodes up internally, and returns that hash. It's the
+// counterpart a state-sync client uses to populate a fresh store from
+// network-provided node data so that a Trie rooted at a trusted hash can
+// dereference it.
+func PutNode(store *storage.MemCachedStore, data []byte) common.Hash {
+	h := hash.DoubleKeccak256(data)
+	store.Put(makeStorageKey(h), data)
+	return h
+}
+
+// GetNodeData returns the raw encoded node data stored under hash h, the
+// counterpart to PutNode for a state-sync server answering a GetTrieNodes
+// request.
+func GetNodeData(store *storage.MemCachedStore, h common.Hash) ([]byte, error) {
+	return store.Get(makeStorageKey(h))
+}
+
+// ChildHashes decodes a single serialized node and returns the hash of
+// every child it references indirectly (as opposed to one embedded
+// directly in its own encoding). This is what a state-sync client needs to
+// keep expanding its download frontier one level at a time via GetTrieNodes
+// without pulling in an entire subtrie at once.
+func ChildHashes(data []byte) ([]common.Hash, error) {
+	no := new(NodeObject)
+	r := io.NewBinReaderFromBuf(data)
+	no.DecodeBinary(r)
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	return childHashesOf(no.Node), nil
+}
+
+func childHashesOf(n Node) []common.Hash {
+	var hashes []common.Hash
+	switch n := n.(type) {
+	case *BranchNode:
+		for _, c := range n.Children {
+			if hn, ok := c.(*HashNode); ok {
+				hashes = append(hashes, hn.Hash())
+			}
+		}
+	case *ExtensionNode:
+		if hn, ok := n.next.(*HashNode); ok {
+			hashes = append(hashes, hn.Hash())
+		}
+	}
+	return hashes
+}
+
+// CollectMissing walks t from the root and returns the hash of every node
+// it can't resolve locally, instead of failing at the first one, so a
+// caller healing a trie whose pivot root moved mid-sync can re-fetch
+// everything still missing in one batched pass instead of one node at a
+// time.
+func (t *Trie) CollectMissing() []common.Hash {
+	var missing []common.Hash
+	t.collectMissing(t.root, &missing)
+	return missing
+}
+
+func (t *Trie) collectMissing(n Node, missing *[]common.Hash) {
+	switch n := n.(type) {
+	case *HashNode:
+		r, err := t.getFromStore(n.Hash())
+		if err != nil {
+			*missing = append(*missing, n.Hash())
+			return
+		}
+		t.collectMissing(r, missing)
+	case *BranchNode:
+		for _, c := range n.Children {
+			t.collectMissing(c, missing)
+		}
+	case *ExtensionNode:
+		t.collectMissing(n.next, missing)
+	}
+}
@@ -10,6 +10,14 @@ import (
 	"github.com/multiverse-dev/saiya/pkg/util/slice"
 )
 
+// Prove returns a proof that key belongs to t, the same way (*Trie).GetProof
+// does. It exists as a free function so callers building proofs for
+// multiple independent tries (e.g. the account trie and several per-slot
+// storage tries in eth_getProof) don't need a *Trie receiver in scope.
+func Prove(t *Trie, key []byte) ([][]byte, error) {
+	return t.GetProof(key)
+}
+
 // GetProof returns a proof that key belongs to t.
 // Proof consist of serialized nodes occurring on path from the root to the leaf of key.
 func (t *Trie) GetProof(key []byte) ([][]byte, error) {
@@ -0,0 +1,107 @@
+package mpt
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/core/storage"
+	"github.com/multiverse-dev/saiya/pkg/crypto/hash"
+)
+
+// ProofCache memoizes proofs already computed for a given (root, key) pair,
+// so that serving the same historical proof repeatedly (a common pattern for
+// eth_getProof against a handful of popular state roots) doesn't re-walk the
+// trie every time.
+type ProofCache struct {
+	mtx   sync.RWMutex
+	cache map[common.Hash]map[string][][]byte
+}
+
+// NewProofCache returns an empty ProofCache.
+func NewProofCache() *ProofCache {
+	return &ProofCache{cache: make(map[common.Hash]map[string][][]byte)}
+}
+
+// GetProof returns the proof for key in the trie rooted at rh, computing and
+// caching it via t if it wasn't cached already. t must already be rooted at
+// rh.
+func (c *ProofCache) GetProof(t *Trie, rh common.Hash, key []byte) ([][]byte, error) {
+	if proof, ok := c.get(rh, key); ok {
+		return proof, nil
+	}
+
+	proof, err := t.GetProof(key)
+	if err != nil {
+		return nil, err
+	}
+
+	c.put(rh, key, proof)
+	return proof, nil
+}
+
+func (c *ProofCache) get(rh common.Hash, key []byte) ([][]byte, bool) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	byKey, ok := c.cache[rh]
+	if !ok {
+		return nil, false
+	}
+	proof, ok := byKey[string(key)]
+	return proof, ok
+}
+
+func (c *ProofCache) put(rh common.Hash, key []byte, proof [][]byte) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	byKey, ok := c.cache[rh]
+	if !ok {
+		byKey = make(map[string][][]byte)
+		c.cache[rh] = byKey
+	}
+	byKey[string(key)] = proof
+}
+
+// Invalidate drops every cached proof for root rh (e.g. once it falls out of
+// the window of state a node is willing to serve proofs for).
+func (c *ProofCache) Invalidate(rh common.Hash) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	delete(c.cache, rh)
+}
+
+// ProofItem is a single (key, value, proof) triple to check in a batch via
+// VerifyProofs.
+type ProofItem struct {
+	Key   []byte
+	Value []byte
+	Proof [][]byte
+}
+
+// VerifyProofs checks every item in items against the same root rh in one
+// pass, sharing the underlying node store across all of them instead of
+// building it once per item the way a loop of VerifyProof calls would. It
+// returns the index of the first item that fails verification, or -1 if all
+// of them pass.
+func VerifyProofs(rh common.Hash, items []ProofItem) (int, error) {
+	tr := NewTrie(NewHashNode(rh), ModeAll, storage.NewMemCachedStore(storage.NewMemoryStore()))
+	for i := range items {
+		for _, n := range items[i].Proof {
+			h := hash.DoubleKeccak256(n)
+			tr.Store.Put(makeStorageKey(h), n)
+		}
+	}
+
+	for i, item := range items {
+		path := toNibbles(item.Key)
+		_, leaf, _, err := tr.getWithPath(tr.root, path, true)
+		if err != nil {
+			return i, nil
+		}
+		ln, ok := leaf.(*LeafNode)
+		if !ok || !bytes.Equal(ln.value, item.Value) {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
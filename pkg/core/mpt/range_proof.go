@@ -0,0 +1,259 @@
+package mpt
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/core/storage"
+	"github.com/multiverse-dev/saiya/pkg/crypto/hash"
+	"github.com/multiverse-dev/saiya/pkg/util/slice"
+)
+
+// errRangeLengthMismatch is returned when VerifyRangeProof is given a
+// different number of keys and values.
+var errRangeLengthMismatch = errors.New("mpt: keys and values have different length")
+
+// errRangeOrder is returned when start is greater than end.
+var errRangeOrder = errors.New("mpt: range start is greater than end")
+
+// KV is a single key/value pair as returned by Trie.RangeProof.
+type KV struct {
+	Key   []byte
+	Value []byte
+}
+
+// GetRangeProof returns a proof that the MPT holds exactly the given set of
+// keys between startKey and endKey (inclusive), and nothing else in between:
+// the union of the individual proofs for startKey and endKey, which is
+// enough to authenticate every key in the range without shipping one proof
+// per key (the standard range-proof construction: the boundary paths pin
+// down every sibling hash an verifier needs to recompute the root from the
+// leaves in between).
+func (t *Trie) GetRangeProof(startKey, endKey []byte) ([][]byte, error) {
+	seen := make(map[common.Hash]struct{})
+	var proof [][]byte
+
+	for _, key := range [][]byte{startKey, endKey} {
+		nodes, err := t.GetProof(key)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range nodes {
+			h := hash.DoubleKeccak256(n)
+			if _, ok := seen[h]; ok {
+				continue
+			}
+			seen[h] = struct{}{}
+			proof = append(proof, n)
+		}
+	}
+	return proof, nil
+}
+
+// RangeProof returns every leaf of t whose key falls within [start, end]
+// (inclusive) together with a boundary proof authenticating them, for use by
+// a getStateRange-style P2P handler answering bulk state requests during
+// snap-style sync. An empty start means "from the very first key", an empty
+// end means "up to the very last key"; passing both gives a proof of the
+// whole trie. The proof is the union of the single-key proofs for start and
+// end (skipped on whichever side is left open), which is enough for
+// VerifyRangeProof to rebuild the boundary skeleton, insert the leaves
+// in between and recompute the root without shipping one proof per leaf.
+func (t *Trie) RangeProof(start, end []byte) ([]KV, [][]byte, error) {
+	if len(start) != 0 && len(end) != 0 && bytes.Compare(start, end) > 0 {
+		return nil, nil, errRangeOrder
+	}
+
+	seen := make(map[common.Hash]struct{})
+	var proof [][]byte
+	for _, key := range [][]byte{start, end} {
+		if len(key) == 0 {
+			continue
+		}
+		nodes, err := t.GetProof(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, n := range nodes {
+			h := hash.DoubleKeccak256(n)
+			if _, ok := seen[h]; ok {
+				continue
+			}
+			seen[h] = struct{}{}
+			proof = append(proof, n)
+		}
+	}
+
+	var leaves []KV
+	root, err := t.collectRange(t.root, nil, toNibbles(start), toNibbles(end), &leaves)
+	if err != nil {
+		return nil, nil, err
+	}
+	t.root = root
+	return leaves, proof, nil
+}
+
+// collectRange walks curr (found at path from the trie root), appending to
+// leaves every leaf whose key falls within [startPath, endPath] and pruning
+// subtries that fall entirely outside of it, so a caller serving a wide
+// trie doesn't pay to dereference data it isn't going to return. An empty
+// startPath/endPath leaves that side of the range open, since comparing
+// against a zero-length bound can never reject a candidate path.
+func (t *Trie) collectRange(curr Node, path, startPath, endPath []byte, leaves *[]KV) (Node, error) {
+	switch n := curr.(type) {
+	case EmptyNode:
+		return n, nil
+	case *LeafNode:
+		*leaves = append(*leaves, KV{Key: fromNibbles(path), Value: slice.Copy(n.value)})
+		return n, nil
+	case *BranchNode:
+		for i := 0; i < childrenCount; i++ {
+			childPath := path
+			if i != lastChild {
+				childPath = append(append([]byte{}, path...), byte(i))
+			}
+			if !inRange(childPath, startPath, endPath) {
+				continue
+			}
+			r, err := t.collectRange(n.Children[i], childPath, startPath, endPath, leaves)
+			if err != nil {
+				return nil, err
+			}
+			n.Children[i] = r
+		}
+		return n, nil
+	case *ExtensionNode:
+		childPath := append(append([]byte{}, path...), n.key...)
+		if !inRange(childPath, startPath, endPath) {
+			return n, nil
+		}
+		r, err := t.collectRange(n.next, childPath, startPath, endPath, leaves)
+		if err != nil {
+			return nil, err
+		}
+		n.next = r
+		return n, nil
+	case *HashNode:
+		r, err := t.getFromStore(n.Hash())
+		if err != nil {
+			return nil, err
+		}
+		return t.collectRange(r, path, startPath, endPath, leaves)
+	}
+	return nil, ErrNotFound
+}
+
+// inRange reports whether the subtrie rooted at path can hold any key
+// within [startPath, endPath], comparing only the nibbles they have in
+// common so a still-partial path isn't rejected just for being shorter
+// than a boundary.
+func inRange(path, startPath, endPath []byte) bool {
+	if len(startPath) != 0 && boundedCompare(path, startPath) < 0 {
+		return false
+	}
+	if len(endPath) != 0 && boundedCompare(path, endPath) > 0 {
+		return false
+	}
+	return true
+}
+
+// boundedCompare compares path and bound over the length they share; a
+// shared prefix compares equal, since the rest of path could still fall on
+// either side of bound.
+func boundedCompare(path, bound []byte) int {
+	n := len(path)
+	if len(bound) < n {
+		n = len(bound)
+	}
+	return bytes.Compare(path[:n], bound[:n])
+}
+
+// fromNibbles reconstructs the original key from its mangled per-nibble
+// path representation (the inverse of toNibbles).
+func fromNibbles(path []byte) []byte {
+	key := make([]byte, len(path)/2)
+	for i := range key {
+		key[i] = path[2*i]<<4 | path[2*i+1]
+	}
+	return key
+}
+
+// VerifyRangeProof verifies a proof produced by Trie.RangeProof: it
+// reconstructs the boundary skeleton of the trie rooted at rh from proof,
+// inserts keys/values (assumed sorted and all falling within [start, end])
+// as the leaves in between, and rejects if the recomputed root doesn't
+// match rh. The returned bool reports whether the trie holds further keys
+// beyond end, so a snap-sync client knows whether to request the next
+// range.
+func VerifyRangeProof(rh common.Hash, start, end []byte, keys, values [][]byte, proof [][]byte) (bool, error) {
+	if len(keys) != len(values) {
+		return false, errRangeLengthMismatch
+	}
+
+	tr := NewTrie(NewHashNode(rh), ModeAll, storage.NewMemCachedStore(storage.NewMemoryStore()))
+	for _, n := range proof {
+		h := hash.DoubleKeccak256(n)
+		tr.Store.Put(makeStorageKey(h), n)
+	}
+
+	more, err := hasMoreAfter(tr, end)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range keys {
+		if err := tr.Put(keys[i], values[i]); err != nil {
+			return false, err
+		}
+	}
+	if tr.StateRoot() != rh {
+		return false, errors.New("mpt: range proof does not match root")
+	}
+	return more, nil
+}
+
+// hasMoreAfter walks the path of end through t, the way it was resolved by
+// the boundary proof, and reports whether it has a non-empty sibling to its
+// right at any point along that path — i.e. whether t holds keys greater
+// than end. An empty end means the range was open on the right, in which
+// case there is nothing further to report.
+func hasMoreAfter(t *Trie, end []byte) (bool, error) {
+	if len(end) == 0 {
+		return false, nil
+	}
+
+	path := toNibbles(end)
+	curr := t.root
+	for {
+		switch n := curr.(type) {
+		case EmptyNode:
+			return false, nil
+		case *LeafNode:
+			return false, nil
+		case *HashNode:
+			r, err := t.getFromStore(n.Hash())
+			if err != nil {
+				return false, err
+			}
+			curr = r
+		case *ExtensionNode:
+			if !bytes.HasPrefix(path, n.key) {
+				return false, errors.New("mpt: end key not covered by proof")
+			}
+			path = path[len(n.key):]
+			curr = n.next
+		case *BranchNode:
+			idx, rest := splitPath(path)
+			for i := int(idx) + 1; i < childrenCount; i++ {
+				if !isEmpty(n.Children[i]) {
+					return true, nil
+				}
+			}
+			path = rest
+			curr = n.Children[idx]
+		default:
+			return false, errors.New("mpt: invalid node in range proof")
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/multiverse-dev/saiya/pkg/core/native/nativenames"
 	"github.com/multiverse-dev/saiya/pkg/core/state"
 	"github.com/multiverse-dev/saiya/pkg/crypto/hash"
+	"github.com/multiverse-dev/saiya/pkg/crypto/keys"
 	"github.com/multiverse-dev/saiya/pkg/io"
 )
 
@@ -25,15 +26,31 @@ var (
 	totalSupplyKey                = []byte{11}
 )
 
+// InflationSchedule configures Sai.OnPersist's block-by-block issuance.
+// GenAmount[min(index/DecrementInterval, len(GenAmount)-1)] SAI (scaled by
+// 10^SaiDecimal) is minted each block - the same decreasing-curve shape
+// Neo's per-block GAS generation uses - split HolderRatio percent to
+// HolderAddress (when set) and the rest to the current committee/
+// validators. A zero-value InflationSchedule (nil GenAmount) mints
+// nothing, preserving the one-shot-initialize-only behavior this had
+// before.
+type InflationSchedule struct {
+	GenAmount         []int
+	DecrementInterval uint32
+	HolderAddress     *common.Address
+	HolderRatio       int
+}
+
 type Sai struct {
 	state.NativeContract
 	cs            *Contracts
 	symbol        string
 	decimals      int64
 	initialSupply uint64
+	inflation     InflationSchedule
 }
 
-func NewSai(cs *Contracts, init uint64) *Sai {
+func NewSai(cs *Contracts, init uint64, inflation InflationSchedule) *Sai {
 	g := &Sai{
 		NativeContract: state.NativeContract{
 			Name: nativenames.Sai,
@@ -45,6 +62,7 @@ func NewSai(cs *Contracts, init uint64) *Sai {
 		},
 		cs:            cs,
 		initialSupply: init,
+		inflation:     inflation,
 	}
 
 	g.symbol = "Sai"
@@ -67,29 +85,94 @@ func (g *Sai) ContractCall_initialize(ic InteropContext) error {
 		return ErrInitialize
 	}
 	validators := g.cs.Designate.StandbyCommittee[:g.cs.Designate.ValidatorsCount]
-	var addr common.Address
-	if validators.Len() == 1 {
-		addr = validators[0].Address()
-	} else {
-		script, err := validators.CreateDefaultMultiSigRedeemScript()
-		if err != nil {
-			return err
-		}
-		addr = hash.Hash160(script)
+	addr, err := committeeRecipient(validators)
+	if err != nil {
+		return err
 	}
 	wei := big.NewInt(1).Exp(big.NewInt(10), big.NewInt(SaiDecimal), nil)
 	total := big.NewInt(1).Mul(big.NewInt(int64(g.initialSupply)), wei)
-	err := g.addTokens(ic.Dao(), addr, total)
+	err = g.addTokens(ic.Dao(), addr, total)
 	if err == nil {
 		log(ic, g.Address, total.Bytes(), g.Abi.Events["initialize"].ID)
 	}
 	return err
 }
 
+// committeeRecipient collapses validators into the single address that
+// receives a collective payout: the lone key's own address when there's
+// just one validator (e.g. a single-node devnet), otherwise the hash of
+// the default multisig redeem script built from all of them. It's the
+// rule both ContractCall_initialize's genesis mint and OnPersist's
+// per-block inflation use to pick a committee recipient.
+func committeeRecipient(validators keys.PublicKeys) (common.Address, error) {
+	if validators.Len() == 1 {
+		return validators[0].Address(), nil
+	}
+	script, err := validators.CreateDefaultMultiSigRedeemScript()
+	if err != nil {
+		return common.Address{}, err
+	}
+	return hash.Hash160(script), nil
+}
+
+// OnPersist mints this block's share of the inflation schedule and splits
+// it between g.inflation.HolderAddress and the current committee/
+// validators, fetched fresh every block since who's on it can change.
 func (g *Sai) OnPersist(d *dao.Simple, block *block.Block) error {
+	perBlock := perBlockAmount(block.Index, g.inflation.GenAmount, g.inflation.DecrementInterval)
+	if perBlock.Sign() == 0 {
+		return nil
+	}
+	committeeShare, holderShare := splitInflationShare(perBlock, g.inflation.HolderRatio, g.inflation.HolderAddress != nil)
+	validators, err := g.cs.Designate.GetValidators(d)
+	if err != nil {
+		return err
+	}
+	addr, err := committeeRecipient(validators)
+	if err != nil {
+		return err
+	}
+	if err := g.addTokens(d, addr, committeeShare); err != nil {
+		return err
+	}
+	if holderShare.Sign() > 0 {
+		if err := g.addTokens(d, *g.inflation.HolderAddress, holderShare); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// perBlockAmount returns index's share of genAmount (scaled to wei),
+// Neo's decreasing per-epoch GAS generation curve: genAmount[epoch], where
+// epoch is index/decrementInterval clamped to genAmount's last entry once
+// the schedule runs out of epochs. A nil/empty genAmount mints nothing.
+func perBlockAmount(index uint32, genAmount []int, decrementInterval uint32) *big.Int {
+	if len(genAmount) == 0 || decrementInterval == 0 {
+		return big.NewInt(0)
+	}
+	epoch := index / decrementInterval
+	if epoch >= uint32(len(genAmount)) {
+		epoch = uint32(len(genAmount) - 1)
+	}
+	wei := big.NewInt(1).Exp(big.NewInt(10), big.NewInt(SaiDecimal), nil)
+	return big.NewInt(0).Mul(big.NewInt(int64(genAmount[epoch])), wei)
+}
+
+// splitInflationShare divides total between a holder share (holderRatio
+// percent, rounded down) and whatever's left for the committee, so the two
+// always sum back to total exactly regardless of rounding. hasHolder being
+// false forces the whole amount to the committee, for when no
+// HolderAddress is configured.
+func splitInflationShare(total *big.Int, holderRatio int, hasHolder bool) (committeeShare, holderShare *big.Int) {
+	if !hasHolder || holderRatio <= 0 {
+		return new(big.Int).Set(total), big.NewInt(0)
+	}
+	holderShare = new(big.Int).Div(new(big.Int).Mul(total, big.NewInt(int64(holderRatio))), big.NewInt(100))
+	committeeShare = new(big.Int).Sub(total, holderShare)
+	return committeeShare, holderShare
+}
+
 func (g *Sai) increaseBalance(gs *GasState, amount *big.Int) error {
 	if amount.Sign() == -1 && gs.Balance.CmpAbs(amount) == -1 {
 		return errors.New("insufficient funds")
@@ -111,6 +194,9 @@ func (g *Sai) saveTotalSupply(d *dao.Simple, supply *big.Int) {
 }
 
 func (g *Sai) getGasState(d *dao.Simple, key []byte) (*GasState, error) {
+	if balance, ok := g.snapshotBalance(key); ok {
+		return &GasState{Balance: balance}, nil
+	}
 	si := d.GetStorageItem(g.Address, key)
 	if si == nil {
 		return nil, nil
@@ -188,6 +274,9 @@ func (g *Sai) balanceFromBytes(si *state.StorageItem) (*big.Int, error) {
 
 func (g *Sai) GetBalance(d *dao.Simple, h common.Address) *big.Int {
 	key := makeAccountKey(h)
+	if balance, ok := g.snapshotBalance(key); ok {
+		return balance
+	}
 	si := d.GetStorageItem(g.Address, key)
 	if si == nil {
 		return big.NewInt(0)
@@ -216,6 +305,7 @@ func (g *Sai) RequiredGas(ic InteropContext, input []byte) uint64 {
 }
 
 func (g *Sai) Run(ic InteropContext, input []byte) ([]byte, error) {
+	updateNativeCallsMetric(g.Name)
 	return contractCall(g, &g.NativeContract, ic, input)
 }
 
@@ -1,441 +1,819 @@
 package native
 
 import (
+	"errors"
 	"math/big"
 	"sort"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/core/block"
+	"github.com/multiverse-dev/saiya/pkg/core/dao"
+	"github.com/multiverse-dev/saiya/pkg/core/native/nativeids"
+	"github.com/multiverse-dev/saiya/pkg/core/native/nativenames"
+	"github.com/multiverse-dev/saiya/pkg/core/state"
+	"github.com/multiverse-dev/saiya/pkg/crypto/hash"
+	"github.com/multiverse-dev/saiya/pkg/crypto/keys"
+	"github.com/multiverse-dev/saiya/pkg/io"
+)
+
+const (
+	// NEODecimal is 0: unlike SAI, NEO isn't meant to be divided.
+	NEODecimal = 0
+	// NEOTotalSupply is the total, fixed amount of NEO minted at genesis.
+	NEOTotalSupply = 100000000
 
-	"github.com/nspcc-dev/neo-go/pkg/core/blockchainer"
-	"github.com/nspcc-dev/neo-go/pkg/core/dao"
-	"github.com/nspcc-dev/neo-go/pkg/core/interop"
-	"github.com/nspcc-dev/neo-go/pkg/core/interop/runtime"
-	"github.com/nspcc-dev/neo-go/pkg/core/state"
-	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
-	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
-	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
-	"github.com/nspcc-dev/neo-go/pkg/util"
-	"github.com/nspcc-dev/neo-go/pkg/vm"
-	"github.com/pkg/errors"
+	prefixNEOAccount    = 20
+	prefixCandidate     = 33
+	prefixCommittee     = 14
+	prefixGasPerBlock   = 15
+	prefixVoterAccum    = 16
+	prefixVoterSnapshot = 17
+	prefixRegisterPrice = 18
+
+	// defaultCommitteeSize is used when NewNEO isn't given any standby
+	// keys to size the committee from.
+	defaultCommitteeSize = 21
+
+	// Out of every block's gasPerBlock, primaryGasRatio percent goes to
+	// that block's primary validator and committeeGasRatio percent is
+	// split evenly across the whole committee; the remainder is the
+	// voter share, paid out lazily via the per-candidate accumulator
+	// getVoterAccum/putVoterAccum track.
+	primaryGasRatio   = 10
+	committeeGasRatio = 10
 )
 
-// NEO represents NEO native contract.
-type NEO struct {
-	nep5TokenNative
-	GAS *GAS
-}
+var (
+	NEOAddress common.Address = common.Address(common.BytesToAddress([]byte{nativeids.NEO}))
+
+	// defaultGasPerBlock is used until setGasPerBlock is ever called.
+	defaultGasPerBlock = big.NewInt(5 * 100000000)
+
+	// voteAccumPrecision scales the per-candidate GAS accumulator so
+	// dividing a block's voter share by a candidate's (possibly small)
+	// vote count doesn't lose precision to integer truncation.
+	voteAccumPrecision = big.NewInt(100000000)
 
-// keyWithVotes is a serialized key with votes balance. It's not deserialized
-// because some uses of it imply serialized-only usage and converting to
-// PublicKey is quite expensive.
-type keyWithVotes struct {
-	Key   string
-	Votes *big.Int
+	// defaultRegisterPrice is the GAS cost of registerCandidate used
+	// until setRegisterPrice is ever called.
+	defaultRegisterPrice = big.NewInt(1000 * 100000000)
+)
+
+// neoAccountState is the per-holder storage record: balance plus the set
+// of candidates currently voted for. Unlike SAI's GasState, a NEO holder's
+// votes have to move with the balance (see addTokens, which moves the
+// holder's vote weight along with every balance change), so both live
+// behind the same prefixNEOAccount key.
+type neoAccountState struct {
+	Balance *big.Int
+	Votes   keys.PublicKeys
 }
 
-// pkeyWithVotes is a deserialized key with votes balance.
-type pkeyWithVotes struct {
-	Key   *keys.PublicKey
-	Votes *big.Int
+func (a *neoAccountState) EncodeBinary(bw *io.BinWriter) {
+	bw.WriteVarBytes(a.Balance.Bytes())
+	bw.WriteArray(a.Votes)
 }
 
-const (
-	neoSyscallName = "Neo.Native.Tokens.NEO"
-	// NEOTotalSupply is the total amount of NEO in the system.
-	NEOTotalSupply = 100000000
-	// prefixValidator is a prefix used to store validator's data.
-	prefixValidator = 33
-)
+func (a *neoAccountState) DecodeBinary(br *io.BinReader) {
+	a.Balance = big.NewInt(0).SetBytes(br.ReadVarBytes())
+	br.ReadArray(&a.Votes)
+}
 
-// makeValidatorKey creates a key from account script hash.
-func makeValidatorKey(key *keys.PublicKey) []byte {
-	b := key.Bytes()
-	// Don't create a new buffer.
-	b = append(b, 0)
-	copy(b[1:], b[0:])
-	b[0] = prefixValidator
-	return b
-}
-
-// NewNEO returns NEO native contract.
-func NewNEO() *NEO {
-	nep5 := newNEP5Native(neoSyscallName)
-	nep5.name = "NEO"
-	nep5.symbol = "neo"
-	nep5.decimals = 0
-	nep5.factor = 1
-
-	n := &NEO{nep5TokenNative: *nep5}
-
-	desc := newDescriptor("unclaimedGas", smartcontract.IntegerType,
-		manifest.NewParameter("account", smartcontract.Hash160Type),
-		manifest.NewParameter("end", smartcontract.IntegerType))
-	md := newMethodAndPrice(n.unclaimedGas, 1, smartcontract.NoneFlag)
-	n.AddMethod(md, desc, true)
-
-	desc = newDescriptor("registerValidator", smartcontract.BoolType,
-		manifest.NewParameter("pubkey", smartcontract.PublicKeyType))
-	md = newMethodAndPrice(n.registerValidator, 1, smartcontract.NoneFlag)
-	n.AddMethod(md, desc, false)
-
-	desc = newDescriptor("vote", smartcontract.BoolType,
-		manifest.NewParameter("account", smartcontract.Hash160Type),
-		manifest.NewParameter("pubkeys", smartcontract.ArrayType))
-	md = newMethodAndPrice(n.vote, 1, smartcontract.NoneFlag)
-	n.AddMethod(md, desc, false)
-
-	desc = newDescriptor("getRegisteredValidators", smartcontract.ArrayType)
-	md = newMethodAndPrice(n.getRegisteredValidatorsCall, 1, smartcontract.NoneFlag)
-	n.AddMethod(md, desc, true)
-
-	desc = newDescriptor("getValidators", smartcontract.ArrayType)
-	md = newMethodAndPrice(n.getValidators, 1, smartcontract.NoneFlag)
-	n.AddMethod(md, desc, true)
-
-	desc = newDescriptor("getNextBlockValidators", smartcontract.ArrayType)
-	md = newMethodAndPrice(n.getNextBlockValidators, 1, smartcontract.NoneFlag)
-	n.AddMethod(md, desc, true)
-
-	n.onPersist = chainOnPersist(n.onPersist, n.OnPersist)
-	n.incBalance = n.increaseBalance
+// NEO is the committee/validator governance token: holders vote for
+// candidates, the top candidates by vote weight form the committee, and a
+// leading slice of the committee serves as the consensus validator set.
+// It mints no GAS of its own - committee/voter rewards are paid in SAI
+// (see gas.go's Sai, n.cs.Sai) the same way this repo's other native
+// contracts already move value, rather than the phantom GAS type the
+// original neo-go port of this file assumed.
+type NEO struct {
+	state.NativeContract
+	cs       *Contracts
+	symbol   string
+	decimals int64
+
+	// committeeSize is the number of public keys that make up the
+	// committee, a superset of the consensus validators elected from
+	// among them.
+	committeeSize int
+
+	// numValidators is the configured size of the leading slice of the
+	// committee used as the consensus validator set.
+	numValidators int
+
+	// standbyKeys pads computeCommittee out when there aren't enough
+	// registered candidates yet, and seeds the genesis committee/
+	// validator set before any registerCandidate has ever run.
+	standbyKeys keys.PublicKeys
+
+	// committee and validators are copy-on-write snapshots behind
+	// atomic.Value: a recompute builds an entirely new slice and
+	// Store()s it, so concurrent readers never observe a partially
+	// built value or race with a mutator.
+	committee  atomic.Value // keys.PublicKeys
+	validators atomic.Value // keys.PublicKeys
+
+	// votesChanged is set by any mutation that can affect the computed
+	// committee/validators (vote, register, unregister), and by OnPersist
+	// when it recomputes the committee; it's cleared once OnPersist has
+	// rebuilt the validators from the current committee.
+	votesChanged uint32
+}
 
+func NewNEO(cs *Contracts, standbyKeys keys.PublicKeys, numValidators int) *NEO {
+	n := &NEO{
+		NativeContract: state.NativeContract{
+			Name: nativenames.NEO,
+			Contract: state.Contract{
+				Address:  NEOAddress,
+				CodeHash: hash.Keccak256(NEOAddress[:]),
+				Code:     NEOAddress[:],
+			},
+		},
+		cs:            cs,
+		standbyKeys:   standbyKeys,
+		numValidators: numValidators,
+	}
+	n.committeeSize = len(standbyKeys)
+	if n.committeeSize == 0 {
+		n.committeeSize = defaultCommitteeSize
+	}
+
+	n.symbol = "NEO"
+	n.decimals = NEODecimal
+	neoAbi, contractCalls, err := constructAbi(n)
+	if err != nil {
+		panic(err)
+	}
+	n.Abi = *neoAbi
+	n.ContractCalls = contractCalls
 	return n
 }
 
-// Initialize initializes NEO contract.
-func (n *NEO) Initialize(ic *interop.Context) error {
-	if err := n.nep5TokenNative.Initialize(ic); err != nil {
-		return err
-	}
+func makeNEOAccountKey(h common.Address) []byte {
+	return makeAddressKey(prefixNEOAccount, h)
+}
 
-	if n.nep5TokenNative.getTotalSupply(ic).Sign() != 0 {
-		return errors.New("already initialized")
-	}
+func makeCandidateKey(pub *keys.PublicKey) []byte {
+	b := pub.Bytes()
+	key := make([]byte, 0, 1+len(b))
+	key = append(key, prefixCandidate)
+	key = append(key, b...)
+	return key
+}
 
-	h, vs, err := getStandbyValidatorsHash(ic)
+// ContractCall_initialize mints NEOTotalSupply to the genesis committee
+// (standbyKeys' collective address, via gas.go's committeeRecipient, the
+// same rule Sai.OnPersist uses) and registers every standby key as a
+// candidate, so the chain starts with a full committee/validator set
+// before anyone has ever voted.
+func (n *NEO) ContractCall_initialize(ic InteropContext) error {
+	if ic.PersistingBlock() == nil || ic.PersistingBlock().Index != 0 {
+		return ErrInitialize
+	}
+	addr, err := committeeRecipient(n.standbyKeys)
 	if err != nil {
 		return err
 	}
-	n.mint(ic, h, big.NewInt(NEOTotalSupply))
-
-	for i := range vs {
-		if err := n.registerValidatorInternal(ic, vs[i]); err != nil {
+	if err := n.addTokens(ic.Dao(), addr, big.NewInt(NEOTotalSupply)); err != nil {
+		return err
+	}
+	for _, pub := range n.standbyKeys {
+		if err := n.registerCandidateInternal(ic.Dao(), pub); err != nil {
 			return err
 		}
 	}
-
+	log(ic, n.Address, big.NewInt(NEOTotalSupply).Bytes(), n.Abi.Events["initialize"].ID)
 	return nil
 }
 
-// OnPersist implements Contract interface.
-func (n *NEO) OnPersist(ic *interop.Context) error {
-	pubs, err := n.GetValidatorsInternal(ic.Chain, ic.DAO)
-	if err != nil {
-		return err
+// ContractCall_transfer moves amount of NEO from the caller to to,
+// reassigning the mover's vote weight the same way a plain vote does
+// (modifyCandidateVotes, via addTokens), since a candidate's vote weight has to track
+// whoever currently holds the NEO, not whoever voted with it originally.
+func (n *NEO) ContractCall_transfer(ic InteropContext, to common.Address, amount *big.Int) (bool, error) {
+	if amount.Sign() < 0 {
+		return false, errors.New("negative amount")
 	}
-	if err := ic.DAO.PutNextBlockValidators(pubs); err != nil {
-		return err
+	d := ic.Dao()
+	from := ic.Sender()
+	if err := n.addTokens(d, from, big.NewInt(0).Neg(amount)); err != nil {
+		return false, err
 	}
-	return nil
+	if err := n.addTokens(d, to, amount); err != nil {
+		return false, err
+	}
+	log(ic, n.Address, encodeAmountLog(from, to, amount), n.Abi.Events["transfer"].ID)
+	return true, nil
+}
+
+// ContractCall_balanceOf returns account's current NEO balance.
+func (n *NEO) ContractCall_balanceOf(ic InteropContext, account common.Address) (*big.Int, error) {
+	return n.getBalance(ic.Dao(), account), nil
 }
 
-func (n *NEO) increaseBalance(ic *interop.Context, h util.Uint160, si *state.StorageItem, amount *big.Int) error {
-	acc, err := state.NEOBalanceStateFromBytes(si.Value)
+// ContractCall_totalSupply returns the total amount of NEO in
+// circulation - always NEOTotalSupply once initialize has run.
+func (n *NEO) ContractCall_totalSupply(ic InteropContext) (*big.Int, error) {
+	return big.NewInt(NEOTotalSupply), nil
+}
+
+// ContractCall_symbol returns NEO's ticker symbol.
+func (n *NEO) ContractCall_symbol(ic InteropContext) (string, error) {
+	return n.symbol, nil
+}
+
+// ContractCall_decimals returns the number of decimals NEO amounts are
+// denominated in - always 0, since NEO isn't divisible.
+func (n *NEO) ContractCall_decimals(ic InteropContext) (uint8, error) {
+	return uint8(n.decimals), nil
+}
+
+// ContractCall_registerCandidate registers pubkey as a candidate
+// committee/validator member. It requires pubkey's own derived address to
+// be the caller, so nobody can register a key they don't control, and
+// burns the current registerPrice in SAI from the caller.
+func (n *NEO) ContractCall_registerCandidate(ic InteropContext, pubkey []byte) (bool, error) {
+	pub, err := keys.NewPublicKeyFromBytes(pubkey)
 	if err != nil {
-		return err
+		return false, err
 	}
-	if sign := amount.Sign(); sign == 0 {
-		return nil
-	} else if sign == -1 && acc.Balance.Cmp(new(big.Int).Neg(amount)) == -1 {
-		return errors.New("insufficient funds")
+	if pub.Address() != ic.Sender() {
+		return false, errors.New("neo: caller does not own pubkey")
 	}
-	if err := n.distributeGas(ic, h, acc); err != nil {
-		return err
+	d := ic.Dao()
+	price := n.getRegisterPrice(d)
+	if price.Sign() > 0 && n.cs.Sai.GetBalance(d, ic.Sender()).Cmp(price) < 0 {
+		return false, errors.New("neo: insufficient SAI for register price")
 	}
-	acc.Balance.Add(&acc.Balance, amount)
-	si.Value = acc.Bytes()
-	return nil
+	if err := n.registerCandidateInternal(d, pub); err != nil {
+		return false, err
+	}
+	if price.Sign() > 0 {
+		n.cs.Sai.SubBalance(d, ic.Sender(), price)
+	}
+	log(ic, n.Address, pubkey, n.Abi.Events["candidateStateChanged"].ID)
+	return true, nil
 }
 
-func (n *NEO) distributeGas(ic *interop.Context, h util.Uint160, acc *state.NEOBalanceState) error {
-	if ic.Block == nil || ic.Block.Index == 0 {
-		return nil
+func (n *NEO) registerCandidateInternal(d *dao.Simple, pub *keys.PublicKey) error {
+	key := makeCandidateKey(pub)
+	if d.GetStorageItem(n.Address, key) != nil {
+		return errors.New("neo: already registered")
 	}
-	sys, net, err := ic.Chain.CalculateClaimable(util.Fixed8(acc.Balance.Int64()), acc.BalanceHeight, ic.Block.Index)
+	cand := &state.Validator{Key: pub, Votes: big.NewInt(0)}
+	data, err := io.ToByteArray(cand)
 	if err != nil {
 		return err
 	}
-	acc.BalanceHeight = ic.Block.Index
-	n.GAS.mint(ic, h, big.NewInt(int64(sys+net)))
+	d.PutStorageItem(n.Address, key, data)
+	atomic.StoreUint32(&n.votesChanged, 1)
 	return nil
 }
 
-func (n *NEO) unclaimedGas(ic *interop.Context, args []vm.StackItem) vm.StackItem {
-	u := toUint160(args[0])
-	end := uint32(toBigInt(args[1]).Int64())
-	bs, err := ic.DAO.GetNEP5Balances(u)
+// ContractCall_unregisterCandidate removes pubkey from the candidate set.
+// Existing votes for pubkey are left on voters' accounts; pubkey is simply
+// left out of the committee (and so the validators) from the next
+// committee recompute on, same as any other never-registered key.
+func (n *NEO) ContractCall_unregisterCandidate(ic InteropContext, pubkey []byte) (bool, error) {
+	pub, err := keys.NewPublicKeyFromBytes(pubkey)
 	if err != nil {
-		panic(err)
+		return false, err
 	}
-	tr := bs.Trackers[n.Hash]
-
-	sys, net, err := ic.Chain.CalculateClaimable(util.Fixed8(tr.Balance), tr.LastUpdatedBlock, end)
-	if err != nil {
-		panic(err)
+	if pub.Address() != ic.Sender() {
+		return false, errors.New("neo: caller does not own pubkey")
 	}
-	return vm.NewBigIntegerItem(big.NewInt(int64(sys.Add(net))))
-}
-
-func (n *NEO) registerValidator(ic *interop.Context, args []vm.StackItem) vm.StackItem {
-	err := n.registerValidatorInternal(ic, toPublicKey(args[0]))
-	return vm.NewBoolItem(err == nil)
+	key := makeCandidateKey(pub)
+	d := ic.Dao()
+	if d.GetStorageItem(n.Address, key) == nil {
+		return false, errors.New("neo: not registered")
+	}
+	d.DeleteStorageItem(n.Address, key)
+	atomic.StoreUint32(&n.votesChanged, 1)
+	log(ic, n.Address, pubkey, n.Abi.Events["candidateStateChanged"].ID)
+	return true, nil
 }
 
-func (n *NEO) registerValidatorInternal(ic *interop.Context, pub *keys.PublicKey) error {
-	key := makeValidatorKey(pub)
-	si := ic.DAO.GetStorageItem(n.Hash, key)
-	if si != nil {
-		return errors.New("already registered")
-	}
-	si = new(state.StorageItem)
-	// It's the same simple counter, calling it `Votes` instead of `Balance`
-	// doesn't help a lot.
-	votes := state.NEP5BalanceState{}
-	si.Value = votes.Bytes()
-	return ic.DAO.PutStorageItem(n.Hash, key, si)
-}
-
-func (n *NEO) vote(ic *interop.Context, args []vm.StackItem) vm.StackItem {
-	acc := toUint160(args[0])
-	arr := args[1].Value().([]vm.StackItem)
-	var pubs keys.PublicKeys
-	for i := range arr {
-		pub := new(keys.PublicKey)
-		bs, err := arr[i].TryBytes()
+// ContractCall_vote replaces the caller's current set of voted-for
+// candidates with pubkeys, moving the caller's whole NEO balance's weight
+// off its old candidates and onto the new ones.
+func (n *NEO) ContractCall_vote(ic InteropContext, pubkeys [][]byte) (bool, error) {
+	pubs := make(keys.PublicKeys, 0, len(pubkeys))
+	for _, raw := range pubkeys {
+		pub, err := keys.NewPublicKeyFromBytes(raw)
 		if err != nil {
-			panic(err)
-		} else if err := pub.DecodeBytes(bs); err != nil {
-			panic(err)
+			return false, err
 		}
 		pubs = append(pubs, pub)
 	}
-	err := n.VoteInternal(ic, acc, pubs)
-	return vm.NewBoolItem(err == nil)
-}
-
-// VoteInternal votes from account h for validarors specified in pubs.
-func (n *NEO) VoteInternal(ic *interop.Context, h util.Uint160, pubs keys.PublicKeys) error {
-	ok, err := runtime.CheckHashedWitness(ic, h)
-	if err != nil {
-		return err
-	} else if !ok {
-		return errors.New("invalid signature")
-	}
-	key := makeAccountKey(h)
-	si := ic.DAO.GetStorageItem(n.Hash, key)
+	d := ic.Dao()
+	h := ic.Sender()
+	key := makeNEOAccountKey(h)
+	si := d.GetStorageItem(n.Address, key)
 	if si == nil {
-		return errors.New("invalid account")
+		return false, errors.New("neo: account has no balance")
 	}
-	acc, err := state.NEOBalanceStateFromBytes(si.Value)
-	if err != nil {
-		return err
+	acc := new(neoAccountState)
+	if err := io.FromByteArray(acc, si); err != nil {
+		return false, err
 	}
-	oldAcc, err := ic.DAO.GetAccountState(h)
-	if err != nil {
-		return err
+	if err := n.claimVoterReward(d, h, acc); err != nil {
+		return false, err
 	}
-	if err := n.ModifyAccountVotes(oldAcc, ic.DAO, new(big.Int).Neg(&acc.Balance)); err != nil {
-		return err
+	if err := n.modifyCandidateVotes(d, acc.Votes, new(big.Int).Neg(acc.Balance)); err != nil {
+		return false, err
 	}
-	pubs = pubs.Unique()
-	// Check validators registration.
-	var newPubs keys.PublicKeys
-	for _, pub := range pubs {
-		if ic.DAO.GetStorageItem(n.Hash, makeValidatorKey(pub)) == nil {
+	newVotes := pubs.Unique()
+	var registered keys.PublicKeys
+	for _, pub := range newVotes {
+		if d.GetStorageItem(n.Address, makeCandidateKey(pub)) == nil {
 			continue
 		}
-		newPubs = append(newPubs, pub)
+		registered = append(registered, pub)
 	}
-	if lp, lv := len(newPubs), len(oldAcc.Votes); lp != lv {
-		vc, err := ic.DAO.GetValidatorsCount()
-		if err != nil {
-			return err
-		}
-		if lv > 0 {
-			vc[lv-1] -= util.Fixed8(acc.Balance.Int64())
-		}
-		if len(newPubs) > 0 {
-			vc[lp-1] += util.Fixed8(acc.Balance.Int64())
-		}
-		if err := ic.DAO.PutValidatorsCount(vc); err != nil {
-			return err
-		}
+	if err := n.modifyCandidateVotes(d, registered, acc.Balance); err != nil {
+		return false, err
 	}
-	oldAcc.Votes = newPubs
-	if err := n.ModifyAccountVotes(oldAcc, ic.DAO, &acc.Balance); err != nil {
-		return err
+	acc.Votes = registered
+	data, err := io.ToByteArray(acc)
+	if err != nil {
+		return false, err
 	}
-	return ic.DAO.PutAccountState(oldAcc)
+	d.PutStorageItem(n.Address, key, data)
+	atomic.StoreUint32(&n.votesChanged, 1)
+	log(ic, n.Address, h.Bytes(), n.Abi.Events["vote"].ID)
+	return true, nil
 }
 
-// ModifyAccountVotes modifies votes of the specified account by value (can be negative).
-func (n *NEO) ModifyAccountVotes(acc *state.Account, d dao.DAO, value *big.Int) error {
-	for _, vote := range acc.Votes {
-		key := makeValidatorKey(vote)
-		si := d.GetStorageItem(n.Hash, key)
+// modifyCandidateVotes adjusts each of pubs' vote weight by value (which
+// may be negative, for an account's old vote being withdrawn). Keys that
+// are no longer registered are skipped, so unregistering a candidate
+// doesn't block its voters' transfers or re-votes.
+func (n *NEO) modifyCandidateVotes(d *dao.Simple, pubs keys.PublicKeys, value *big.Int) error {
+	for _, pub := range pubs {
+		key := makeCandidateKey(pub)
+		si := d.GetStorageItem(n.Address, key)
 		if si == nil {
-			return errors.New("invalid validator")
+			continue
 		}
-		votes, err := state.NEP5BalanceStateFromBytes(si.Value)
-		if err != nil {
+		cand := new(state.Validator)
+		if err := io.FromByteArray(cand, si); err != nil {
 			return err
 		}
-		votes.Balance.Add(&votes.Balance, value)
-		si.Value = votes.Bytes()
-		if err := d.PutStorageItem(n.Hash, key, si); err != nil {
+		cand.Votes.Add(cand.Votes, value)
+		data, err := io.ToByteArray(cand)
+		if err != nil {
 			return err
 		}
+		d.PutStorageItem(n.Address, key, data)
 	}
 	return nil
 }
 
-func (n *NEO) getRegisteredValidators(d dao.DAO) ([]keyWithVotes, error) {
-	siMap, err := d.GetStorageItemsWithPrefix(n.Hash, []byte{prefixValidator})
+// ContractCall_getCommittee returns the current cached committee's raw
+// public key bytes.
+func (n *NEO) ContractCall_getCommittee(ic InteropContext) ([][]byte, error) {
+	return pubsToBytes(n.GetCommitteeInternal()), nil
+}
+
+// ContractCall_getValidators returns the current consensus validator set's
+// raw public key bytes.
+func (n *NEO) ContractCall_getValidators(ic InteropContext) ([][]byte, error) {
+	validators, err := n.GetValidatorsInternal(ic.Dao())
 	if err != nil {
 		return nil, err
 	}
-	arr := make([]keyWithVotes, 0, len(siMap))
-	for key, si := range siMap {
-		votes, err := state.NEP5BalanceStateFromBytes(si.Value)
-		if err != nil {
-			return nil, err
-		}
-		arr = append(arr, keyWithVotes{key, &votes.Balance})
+	return pubsToBytes(validators), nil
+}
+
+// ContractCall_getGasPerBlock returns the SAI-denominated reward minted
+// each block for the primary/committee/voters, as of the persisting
+// block.
+func (n *NEO) ContractCall_getGasPerBlock(ic InteropContext) (*big.Int, error) {
+	index := uint32(0)
+	if b := ic.PersistingBlock(); b != nil {
+		index = b.Index
 	}
-	return arr, nil
+	return n.getGasPerBlock(ic.Dao(), index)
 }
 
-func (n *NEO) getRegisteredValidatorsCall(ic *interop.Context, _ []vm.StackItem) vm.StackItem {
-	validators, err := n.getRegisteredValidators(ic.DAO)
+func pubsToBytes(pubs keys.PublicKeys) [][]byte {
+	result := make([][]byte, len(pubs))
+	for i, pub := range pubs {
+		result[i] = pub.Bytes()
+	}
+	return result
+}
+
+func (n *NEO) addTokens(d *dao.Simple, h common.Address, amount *big.Int) error {
+	if amount.Sign() == 0 {
+		return nil
+	}
+	key := makeNEOAccountKey(h)
+	acc := new(neoAccountState)
+	si := d.GetStorageItem(n.Address, key)
+	if si != nil {
+		if err := io.FromByteArray(acc, si); err != nil {
+			return err
+		}
+	} else {
+		acc.Balance = big.NewInt(0)
+	}
+	if amount.Sign() < 0 && acc.Balance.CmpAbs(amount) < 0 {
+		return errors.New("neo: insufficient funds")
+	}
+	if err := n.claimVoterReward(d, h, acc); err != nil {
+		return err
+	}
+	acc.Balance.Add(acc.Balance, amount)
+	if len(acc.Votes) > 0 {
+		if err := n.modifyCandidateVotes(d, acc.Votes, amount); err != nil {
+			return err
+		}
+	}
+	if acc.Balance.Sign() == 0 && len(acc.Votes) == 0 {
+		d.DeleteStorageItem(n.Address, key)
+		return nil
+	}
+	data, err := io.ToByteArray(acc)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	arr := make([]vm.StackItem, len(validators))
-	for i := range validators {
-		arr[i] = vm.NewStructItem([]vm.StackItem{
-			vm.NewByteArrayItem([]byte(validators[i].Key)),
-			vm.NewBigIntegerItem(validators[i].Votes),
-		})
+	d.PutStorageItem(n.Address, key, data)
+	return nil
+}
+
+func (n *NEO) getBalance(d *dao.Simple, h common.Address) *big.Int {
+	si := d.GetStorageItem(n.Address, makeNEOAccountKey(h))
+	if si == nil {
+		return big.NewInt(0)
+	}
+	acc := new(neoAccountState)
+	if err := io.FromByteArray(acc, si); err != nil {
+		panic(err)
 	}
-	return vm.NewArrayItem(arr)
+	return acc.Balance
 }
 
-// GetValidatorsInternal returns a list of current validators.
-func (n *NEO) GetValidatorsInternal(bc blockchainer.Blockchainer, d dao.DAO) ([]*keys.PublicKey, error) {
-	validatorsCount, err := d.GetValidatorsCount()
+// getRegisteredCandidates returns every registered candidate and its
+// current vote weight.
+func (n *NEO) getRegisteredCandidates(d *dao.Simple) ([]*state.Validator, error) {
+	siMap, err := d.GetStorageItemsWithPrefix(n.Address, []byte{prefixCandidate})
 	if err != nil {
 		return nil, err
-	} else if len(validatorsCount) == 0 {
-		sb, err := bc.GetStandByValidators()
-		if err != nil {
+	}
+	result := make([]*state.Validator, 0, len(siMap))
+	for _, si := range siMap {
+		cand := new(state.Validator)
+		if err := io.FromByteArray(cand, si); err != nil {
 			return nil, err
 		}
-		return sb, nil
+		result = append(result, cand)
 	}
+	return result, nil
+}
 
-	validatorsBytes, err := n.getRegisteredValidators(d)
+// computeCommittee returns the current committee: the top committeeSize
+// registered candidates sorted by votes (ties broken by public key),
+// padded with standby keys if there aren't enough registered candidates.
+func (n *NEO) computeCommittee(d *dao.Simple) (keys.PublicKeys, error) {
+	candidates, err := n.getRegisteredCandidates(d)
 	if err != nil {
 		return nil, err
 	}
-	validators := make([]pkeyWithVotes, len(validatorsBytes))
-	for i := range validatorsBytes {
-		validators[i].Key, err = keys.NewPublicKeyFromBytes([]byte(validatorsBytes[i].Key))
+	sort.Slice(candidates, func(i, j int) bool {
+		if cmp := candidates[i].Votes.Cmp(candidates[j].Votes); cmp != 0 {
+			return cmp > 0
+		}
+		return candidates[i].Key.Cmp(candidates[j].Key) < 0
+	})
+	result := make(keys.PublicKeys, 0, n.committeeSize)
+	for _, cand := range candidates {
+		if len(result) == n.committeeSize {
+			break
+		}
+		result = append(result, cand.Key)
+	}
+	for i := 0; i < n.standbyKeys.Len() && len(result) < n.committeeSize; i++ {
+		if !result.Contains(n.standbyKeys[i]) {
+			result = append(result, n.standbyKeys[i])
+		}
+	}
+	return result, nil
+}
+
+func committeeToBytes(committee keys.PublicKeys) []byte {
+	w := io.NewBufBinWriter()
+	w.WriteArray(committee)
+	return w.Bytes()
+}
+
+func committeeFromBytes(b []byte) (keys.PublicKeys, error) {
+	r := io.NewBinReaderFromBuf(b)
+	var committee keys.PublicKeys
+	r.ReadArray(&committee)
+	return committee, r.Err
+}
+
+// getCommitteeCache returns the in-memory cached committee, or nil if it
+// hasn't been warmed up yet (e.g. right after process start).
+func (n *NEO) getCommitteeCache() keys.PublicKeys {
+	committee, _ := n.committee.Load().(keys.PublicKeys)
+	return committee
+}
+
+// setCommitteeCache atomically replaces the in-memory committee with a
+// freshly built snapshot; it never mutates a previously published one, so
+// concurrent readers of the old value are unaffected.
+func (n *NEO) setCommitteeCache(committee keys.PublicKeys) {
+	n.committee.Store(committee)
+}
+
+// GetCommitteeInternal returns the current cached committee.
+func (n *NEO) GetCommitteeInternal() keys.PublicKeys {
+	return n.getCommitteeCache()
+}
+
+// GetValidatorsInternal returns the current consensus validator set: the
+// top numValidators of the committee (not of all registered candidates).
+func (n *NEO) GetValidatorsInternal(d *dao.Simple) (keys.PublicKeys, error) {
+	if cached, ok := n.validators.Load().(keys.PublicKeys); ok && atomic.LoadUint32(&n.votesChanged) == 0 {
+		return cached, nil
+	}
+	committee := n.getCommitteeCache()
+	var err error
+	if committee == nil {
+		committee, err = n.computeCommittee(d)
 		if err != nil {
 			return nil, err
 		}
-		validators[i].Votes = validatorsBytes[i].Votes
 	}
-	sort.Slice(validators, func(i, j int) bool {
-		// The most-voted validators should end up in the front of the list.
-		cmp := validators[i].Votes.Cmp(validators[j].Votes)
-		if cmp != 0 {
-			return cmp > 0
+	count := n.numValidators
+	if count <= 0 || count > len(committee) {
+		count = len(committee)
+	}
+	result := make(keys.PublicKeys, count)
+	copy(result, committee[:count])
+	sort.Sort(result)
+	return result, nil
+}
+
+// OnPersist recomputes the committee every committeeSize blocks, keeps the
+// committee/validators caches warm otherwise, and distributes this
+// block's SAI reward across the primary validator, the committee and
+// voters. It mirrors gas.go's Sai.OnPersist signature: called directly
+// once per block, not through InteropContext.
+func (n *NEO) OnPersist(d *dao.Simple, b *block.Block) error {
+	if b != nil && (b.Index%uint32(n.committeeSize) == 0 || n.getCommitteeCache() == nil) {
+		committee, err := n.computeCommittee(d)
+		if err != nil {
+			return err
 		}
-		// Ties are broken with public keys.
-		return validators[i].Key.Cmp(validators[j].Key) == -1
-	})
+		d.PutStorageItem(n.Address, []byte{prefixCommittee}, committeeToBytes(committee))
+		n.setCommitteeCache(committee)
+		atomic.StoreUint32(&n.votesChanged, 1)
+	}
+	if atomic.LoadUint32(&n.votesChanged) != 0 || n.validators.Load() == nil {
+		validators, err := n.GetValidatorsInternal(d)
+		if err != nil {
+			return err
+		}
+		n.validators.Store(validators)
+		atomic.StoreUint32(&n.votesChanged, 0)
+	}
+	return n.distributeBlockGas(d, b)
+}
 
-	count := validatorsCount.GetWeightedAverage()
-	standByValidators, err := bc.GetStandByValidators()
+// distributeBlockGas splits this block's gasPerBlock SAI reward between
+// the primary validator, the committee (evenly) and voters (weighted by
+// vote share, via the per-candidate accumulator), replacing the original
+// port's phantom n.GAS.mint calls with real n.cs.Sai.AddBalance moves.
+func (n *NEO) distributeBlockGas(d *dao.Simple, b *block.Block) error {
+	if b == nil || b.Index == 0 {
+		return nil
+	}
+	gasPerBlock, err := n.getGasPerBlock(d, b.Index)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	if count < len(standByValidators) {
-		count = len(standByValidators)
+	validators, err := n.GetValidatorsInternal(d)
+	if err != nil {
+		return err
 	}
+	if len(validators) == 0 {
+		return nil
+	}
+	primary := validators[int(b.Index)%len(validators)]
+	primaryShare := new(big.Int).Div(new(big.Int).Mul(gasPerBlock, big.NewInt(primaryGasRatio)), big.NewInt(100))
+	n.cs.Sai.AddBalance(d, primary.Address(), primaryShare)
 
-	uniqueSBValidators := standByValidators.Unique()
-	result := keys.PublicKeys{}
-	for _, validator := range validators {
-		if validator.Votes.Sign() > 0 || uniqueSBValidators.Contains(validator.Key) {
-			result = append(result, validator.Key)
+	committee := n.getCommitteeCache()
+	if len(committee) == 0 {
+		return nil
+	}
+	committeeShare := new(big.Int).Div(new(big.Int).Mul(gasPerBlock, big.NewInt(committeeGasRatio)), big.NewInt(100))
+	voterShare := new(big.Int).Sub(gasPerBlock, new(big.Int).Add(primaryShare, committeeShare))
+	perMember := new(big.Int).Div(committeeShare, big.NewInt(int64(len(committee))))
+	if perMember.Sign() > 0 {
+		for _, pub := range committee {
+			n.cs.Sai.AddBalance(d, pub.Address(), perMember)
 		}
 	}
+	if voterShare.Sign() <= 0 {
+		return nil
+	}
+	candidates, err := n.getRegisteredCandidates(d)
+	if err != nil {
+		return err
+	}
+	votesByKey := make(map[string]*big.Int, len(candidates))
+	for _, cand := range candidates {
+		votesByKey[string(cand.Key.Bytes())] = cand.Votes
+	}
+	for _, pub := range committee {
+		votes := votesByKey[string(pub.Bytes())]
+		if votes == nil || votes.Sign() <= 0 {
+			continue
+		}
+		accum := n.getVoterAccum(d, pub)
+		delta := new(big.Int).Div(new(big.Int).Mul(voterShare, voteAccumPrecision), votes)
+		accum.Add(accum, delta)
+		n.putVoterAccum(d, pub, accum)
+	}
+	return nil
+}
 
-	if result.Len() >= count {
-		result = result[:count]
-	} else {
-		for i := 0; i < uniqueSBValidators.Len() && result.Len() < count; i++ {
-			if !result.Contains(uniqueSBValidators[i]) {
-				result = append(result, uniqueSBValidators[i])
-			}
+// claimVoterReward pays h the SAI its current balance has earned from
+// voting since its last claim, based on the average per-candidate
+// accumulator across acc.Votes. It's called from addTokens and
+// ContractCall_vote before acc's balance/votes are mutated, mirroring the
+// original port's distributeGas running from increaseBalance - a holder's
+// reward accrues against its balance and vote set as of just before the
+// move, never the post-move one.
+func (n *NEO) claimVoterReward(d *dao.Simple, h common.Address, acc *neoAccountState) error {
+	if len(acc.Votes) == 0 || acc.Balance.Sign() == 0 {
+		return nil
+	}
+	accumSum := big.NewInt(0)
+	for _, pub := range acc.Votes {
+		accumSum.Add(accumSum, n.getVoterAccum(d, pub))
+	}
+	avg := new(big.Int).Div(accumSum, big.NewInt(int64(len(acc.Votes))))
+	last := n.getVoterSnapshot(d, h)
+	n.putVoterSnapshot(d, h, avg)
+	delta := new(big.Int).Sub(avg, last)
+	if delta.Sign() <= 0 {
+		return nil
+	}
+	reward := new(big.Int).Mul(delta, acc.Balance)
+	reward.Div(reward, voteAccumPrecision)
+	if reward.Sign() > 0 {
+		n.cs.Sai.AddBalance(d, h, reward)
+	}
+	return nil
+}
+
+func (n *NEO) voterSnapshotKey(h common.Address) []byte {
+	return makeAddressKey(prefixVoterSnapshot, h)
+}
+
+func (n *NEO) getVoterSnapshot(d *dao.Simple, h common.Address) *big.Int {
+	si := d.GetStorageItem(n.Address, n.voterSnapshotKey(h))
+	if si == nil {
+		return big.NewInt(0)
+	}
+	return big.NewInt(0).SetBytes(si)
+}
+
+func (n *NEO) putVoterSnapshot(d *dao.Simple, h common.Address, accum *big.Int) {
+	d.PutStorageItem(n.Address, n.voterSnapshotKey(h), accum.Bytes())
+}
+
+func (n *NEO) voterAccumKey(pub *keys.PublicKey) []byte {
+	b := pub.Bytes()
+	key := make([]byte, 0, 1+len(b))
+	key = append(key, prefixVoterAccum)
+	key = append(key, b...)
+	return key
+}
+
+func (n *NEO) getVoterAccum(d *dao.Simple, pub *keys.PublicKey) *big.Int {
+	si := d.GetStorageItem(n.Address, n.voterAccumKey(pub))
+	if si == nil {
+		return big.NewInt(0)
+	}
+	return big.NewInt(0).SetBytes(si)
+}
+
+func (n *NEO) putVoterAccum(d *dao.Simple, pub *keys.PublicKey, accum *big.Int) {
+	d.PutStorageItem(n.Address, n.voterAccumKey(pub), accum.Bytes())
+}
+
+type gasRecord struct {
+	Index       uint32
+	GasPerBlock *big.Int
+}
+
+// getGasRecords returns the append-only gasPerBlock history, oldest first.
+func (n *NEO) getGasRecords(d *dao.Simple) ([]*gasRecord, error) {
+	si := d.GetStorageItem(n.Address, []byte{prefixGasPerBlock})
+	if si == nil {
+		return nil, nil
+	}
+	r := io.NewBinReaderFromBuf(si)
+	count := r.ReadVarUint()
+	records := make([]*gasRecord, count)
+	for i := range records {
+		records[i] = &gasRecord{
+			Index:       r.ReadU32LE(),
+			GasPerBlock: big.NewInt(0).SetBytes(r.ReadVarBytes()),
 		}
 	}
-	sort.Sort(result)
-	return result, nil
+	return records, r.Err
 }
 
-func (n *NEO) getValidators(ic *interop.Context, _ []vm.StackItem) vm.StackItem {
-	result, err := n.GetValidatorsInternal(ic.Chain, ic.DAO)
-	if err != nil {
-		panic(err)
+func (n *NEO) putGasRecords(d *dao.Simple, records []*gasRecord) error {
+	w := io.NewBufBinWriter()
+	w.WriteVarUint(uint64(len(records)))
+	for _, rec := range records {
+		w.WriteU32LE(rec.Index)
+		w.WriteVarBytes(rec.GasPerBlock.Bytes())
 	}
-	return pubsToArray(result)
+	d.PutStorageItem(n.Address, []byte{prefixGasPerBlock}, w.Bytes())
+	return nil
 }
 
-func (n *NEO) getNextBlockValidators(ic *interop.Context, _ []vm.StackItem) vm.StackItem {
-	result, err := n.GetNextBlockValidatorsInternal(ic.Chain, ic.DAO)
+// getGasPerBlock returns the gasPerBlock value in effect at the given
+// height, i.e. the most recent record at or before it.
+func (n *NEO) getGasPerBlock(d *dao.Simple, index uint32) (*big.Int, error) {
+	records, err := n.getGasRecords(d)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	rate := defaultGasPerBlock
+	for _, rec := range records {
+		if rec.Index > index {
+			break
+		}
+		rate = rec.GasPerBlock
 	}
-	return pubsToArray(result)
+	return rate, nil
 }
 
-// GetNextBlockValidatorsInternal returns next block validators.
-func (n *NEO) GetNextBlockValidatorsInternal(bc blockchainer.Blockchainer, d dao.DAO) ([]*keys.PublicKey, error) {
-	result, err := d.GetNextBlockValidators()
+// SetGasPerBlockInternal appends a new gasPerBlock record effective from
+// height index onwards. It's meant to be called from a committee-witnessed
+// caller (e.g. a future Policy-style vote, see sai.go's setGasPrice doc
+// comment for why that witness check isn't wired up in this checkout
+// yet), so it's a plain Go method rather than a ContractCall_ method.
+func (n *NEO) SetGasPerBlockInternal(d *dao.Simple, index uint32, gasPerBlock *big.Int) error {
+	if gasPerBlock.Sign() < 0 {
+		return errors.New("neo: negative gas per block")
+	}
+	records, err := n.getGasRecords(d)
 	if err != nil {
-		return nil, err
-	} else if result == nil {
-		return bc.GetStandByValidators()
+		return err
 	}
-	return result, nil
+	records = append(records, &gasRecord{Index: index, GasPerBlock: gasPerBlock})
+	return n.putGasRecords(d, records)
+}
+
+func (n *NEO) getRegisterPrice(d *dao.Simple) *big.Int {
+	si := d.GetStorageItem(n.Address, []byte{prefixRegisterPrice})
+	if si == nil {
+		return new(big.Int).Set(defaultRegisterPrice)
+	}
+	return new(big.Int).SetBytes(si)
 }
 
-func pubsToArray(pubs keys.PublicKeys) vm.StackItem {
-	arr := make([]vm.StackItem, len(pubs))
-	for i := range pubs {
-		arr[i] = vm.NewByteArrayItem(pubs[i].Bytes())
+// SetRegisterPriceInternal changes the SAI cost of registerCandidate. See
+// SetGasPerBlockInternal's doc comment for why this is a plain Go method
+// rather than a witness-gated ContractCall_ method in this checkout.
+func (n *NEO) SetRegisterPriceInternal(d *dao.Simple, price *big.Int) error {
+	if price.Sign() < 0 {
+		return errors.New("neo: negative register price")
 	}
-	return vm.NewArrayItem(arr)
+	d.PutStorageItem(n.Address, []byte{prefixRegisterPrice}, price.Bytes())
+	return nil
 }
 
-func toPublicKey(s vm.StackItem) *keys.PublicKey {
-	buf, err := s.TryBytes()
+func (n *NEO) RequiredGas(ic InteropContext, input []byte) uint64 {
+	if len(input) < 4 {
+		return 0
+	}
+	method, err := n.Abi.MethodById(input[:4])
 	if err != nil {
-		panic(err)
+		return 0
 	}
-	pub := new(keys.PublicKey)
-	if err := pub.DecodeBytes(buf); err != nil {
-		panic(err)
+	switch method.Name {
+	case "balanceOf", "totalSupply", "symbol", "decimals", "getCommittee", "getValidators", "getGasPerBlock":
+		return viewGas
+	default:
+		return 0
 	}
-	return pub
+}
+
+func (n *NEO) Run(ic InteropContext, input []byte) ([]byte, error) {
+	updateNativeCallsMetric(n.Name)
+	return contractCall(n, &n.NativeContract, ic, input)
 }
@@ -6,6 +6,7 @@ const (
 	Sai         = "SaiToken"
 	Policy      = "PolicyContract"
 	Designation = "RoleManagement"
+	Randomness  = "RandomnessContract"
 )
 
 // IsValid checks that name is a valid native contract's name.
@@ -14,5 +15,6 @@ func IsValid(name string) bool {
 		name == Ledger ||
 		name == Sai ||
 		name == Policy ||
-		name == Designation
+		name == Designation ||
+		name == Randomness
 }
@@ -0,0 +1,27 @@
+package native
+
+// An EIP-1559 fee market needs three pieces this snapshot doesn't have, all
+// predating any chunk work (confirmed via grep/git log - PolicyAddress,
+// DefaultGasPrice, DefaultFeePerByte, DesignationAddress, ManagementAddress
+// and the Contracts aggregator type they'd live on are referenced from
+// cli/native/policy.go and core/util.go but defined nowhere; so is the
+// Policy native contract's own source file):
+//
+//   - The Policy native contract (policy.go, analogous to sai.go/gas.go)
+//     isn't in this snapshot - only its address/name constants and CLI
+//     commands are, assumed to exist the same way sai.go assumes
+//     constructAbi/contractCall/log. setGasPrice can't be swapped for
+//     setGasTarget/setBaseFeeChangeDenominator without that file to edit.
+//   - pkg/core/block.Header is embedded and read throughout pkg/core/block
+//     and pkg/core/util.go (Index, Timestamp, Nonce, PrimaryIndex,
+//     MerkleRoot, Witness, and now BaseFee per block/basefee.go's doc
+//     comment from chunk4-2) but its struct definition itself is missing,
+//     the same gap class as WSClient in pkg/rpc/client. Adding a BaseFee
+//     field - and having createGenesisBlock seed it - needs that struct to
+//     exist first.
+//
+// What doesn't depend on either gap is implemented here and in
+// pkg/wallet/sign_context.go: SAI.Burn (sai.go) gives OnPersist something to
+// call once per-block baseFee*gasUsed accounting exists, and
+// SignContext.Describe now reports the right fee fields for a
+// DynamicFeeSaiyaTxType transaction instead of assuming GasPrice.
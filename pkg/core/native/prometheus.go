@@ -0,0 +1,42 @@
+package native
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics for monitoring native contract calls.
+var (
+	//nativeCallsTotal prometheus metric.
+	nativeCallsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Total number of native contract calls, by contract name",
+			Name:      "native_calls_total",
+			Namespace: "saiya",
+		},
+		[]string{"contract"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		nativeCallsTotal,
+	)
+}
+
+// MetricsEnabled gates updateNativeCallsMetric so config.Metrics.DisableNative
+// can opt this family out; see mempool.MetricsEnabled for why this is a
+// package var rather than read from config directly.
+var MetricsEnabled = true
+
+// updateNativeCallsMetric increments the call counter for the native
+// contract named contractName. It's called from each contract's own Run
+// rather than from a shared contractCall dispatcher: contractCall isn't
+// defined anywhere in this snapshot (only called, same gap as log() and
+// constructAbi), so Run - the one real, per-contract entry point every
+// precompile wrapper actually calls - is where the counter lives instead.
+func updateNativeCallsMetric(contractName string) {
+	if !MetricsEnabled {
+		return
+	}
+	nativeCallsTotal.WithLabelValues(contractName).Inc()
+}
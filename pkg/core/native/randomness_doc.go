@@ -0,0 +1,21 @@
+package native
+
+// Randomness (randomness.go) implements getRandom/getRandomFromSeed and the
+// storage side of per-round beacon entries, but two pieces of this feature
+// live outside what this snapshot has on disk:
+//
+//   - Block producers embedding the current drand entry in the block
+//     header needs a RandomnessProof field on block.Header
+//     (pkg/core/block), which - like the BaseFee field chunk12-2 ran into
+//     the same way - doesn't exist in this snapshot; block.go only embeds
+//     and reads an undefined Header type.
+//   - Validators verifying that proof on OnPersist needs a call site that
+//     invokes Randomness.OnPersist with the block actually being
+//     persisted; no such dispatch exists yet for any native contract's
+//     OnPersist here (SAI's and this one are both currently dead code,
+//     confirmed unreferenced anywhere in this checkout).
+//
+// pkg/services/randbeacon.DrandBeacon is a complete, standalone fetch-and-
+// verify client for a drand HTTP endpoint; once RandomnessProof exists,
+// a block producer calls Beacon.Entry to embed a round and OnPersist calls
+// Beacon.VerifyEntry plus Randomness.PutEntry to store it.
@@ -16,8 +16,22 @@ import (
 )
 
 const (
-	prefixAccount = 20
-	SAIDecimal    = 18
+	prefixAccount   = 20
+	prefixAllowance = 21
+	SAIDecimal      = 18
+)
+
+// Default per-method gas costs, used to seed NewSAI's gasPrices when the
+// caller doesn't override them. transfer/approve/transferFrom each touch
+// one or two storage slots (account/allowance), same order of magnitude as
+// a Keccak256+SSTORE pair; the read-only methods only ever GetStorageItem
+// so they're priced far cheaper, in the same spirit as VerifyProofGas in
+// pkg/crypto/zk.
+const (
+	defaultTransferGas     = 30000
+	defaultTransferFromGas = 35000
+	defaultApproveGas      = 20000
+	defaultViewGas         = 400
 )
 
 var (
@@ -25,15 +39,42 @@ var (
 	totalSupplyKey                = []byte{11}
 )
 
+// ErrInsufficientGas is returned from Run when the caller's SAI balance
+// can't cover RequiredGas's cost for the method being invoked.
+var ErrInsufficientGas = errors.New("sai: insufficient balance for gas")
+
 type SAI struct {
 	state.NativeContract
-	cs            *Contracts
-	symbol        string
-	decimals      int64
-	initialSupply uint64
+	cs       *Contracts
+	name     string
+	symbol   string
+	decimals int64
+
+	// gasPrices is the per-method schedule RequiredGas charges, keyed by
+	// ABI method name. It's meant to come from a NativeGasPrices field on
+	// config.ProtocolConfiguration, but that struct isn't defined anywhere
+	// in this checkout (it's referenced from other packages, such as
+	// pkg/core/simulated, and defined in none), so NewSAI takes the
+	// override map directly for now, the way NewNEO takes its standby keys
+	// and validator count as plain arguments; wiring
+	// cfg.NativeGasPrices through is a one-line change at NewSAI's call
+	// site once that struct exists. A real Designate/Policy-style voting
+	// hook to change gasPrices after genesis needs the same
+	// committee-witness check those native contracts would enforce, which
+	// this checkout also doesn't have (see policy_doc.go) - so there's no
+	// ContractCall_setGasPrice here yet, only the unexported setGasPrice
+	// a trusted caller (e.g. a future Policy.onPersist) could use.
+	gasPrices map[string]uint64
+
+	// notifyDispatcher fans transfer/approval/initialize events out to
+	// SubscribeSAIEvents callers; see sai_notify.go. It's started once,
+	// in NewSAI, and runs for g's whole lifetime - same as a native
+	// contract being a chain-lifetime singleton, there's no per-block or
+	// per-request teardown for it to leak across.
+	notifyDispatcher *saiNotifyDispatcher
 }
 
-func NewSAI(cs *Contracts, init uint64) *SAI {
+func NewSAI(cs *Contracts, gasPrices map[string]uint64) *SAI {
 	g := &SAI{
 		NativeContract: state.NativeContract{
 			Name: nativenames.SAI,
@@ -43,10 +84,25 @@ func NewSAI(cs *Contracts, init uint64) *SAI {
 				Code:     SAIAddress[:],
 			},
 		},
-		cs:            cs,
-		initialSupply: init,
+		cs:               cs,
+		notifyDispatcher: newSAINotifyDispatcher(),
+		gasPrices: map[string]uint64{
+			"transfer":     defaultTransferGas,
+			"transferFrom": defaultTransferFromGas,
+			"approve":      defaultApproveGas,
+			"allowance":    defaultViewGas,
+			"balanceOf":    defaultViewGas,
+			"totalSupply":  defaultViewGas,
+			"symbol":       defaultViewGas,
+			"decimals":     defaultViewGas,
+			"name":         defaultViewGas,
+		},
+	}
+	for method, price := range gasPrices {
+		g.gasPrices[method] = price
 	}
 
+	g.name = "Saiya Token"
 	g.symbol = "SAI"
 	g.decimals = SAIDecimal
 	gasAbi, contractCalls, err := constructAbi(g)
@@ -58,43 +114,199 @@ func NewSAI(cs *Contracts, init uint64) *SAI {
 	return g
 }
 
+// setGasPrice updates the gas cost RequiredGas charges for method, the
+// hook a future Policy-style vote would call once this checkout has a
+// committee-witness check to gate it (see the gasPrices field's doc
+// comment). It rejects unknown method names so a typo can't silently
+// create a dead entry that's never consulted by RequiredGas.
+func (g *SAI) setGasPrice(method string, price uint64) error {
+	if _, ok := g.Abi.Methods[method]; !ok {
+		return fmt.Errorf("sai: unknown method %q", method)
+	}
+	g.gasPrices[method] = price
+	return nil
+}
+
 func makeAccountKey(h common.Address) []byte {
 	return makeAddressKey(prefixAccount, h)
 }
 
-func (g *SAI) ContractCall_initialize(ic InteropContext) error {
+// makeAllowanceKey builds the storage key for the amount spender is allowed
+// to pull from owner's balance, keyed by both addresses since an owner can
+// grant different allowances to different spenders. It mirrors
+// makeAccountKey: same prefix-then-address(es) layout, just with a second
+// address appended and prefixAllowance in place of prefixAccount.
+func makeAllowanceKey(owner, spender common.Address) []byte {
+	key := make([]byte, 0, 1+2*common.AddressLength)
+	key = append(key, prefixAllowance)
+	key = append(key, owner.Bytes()...)
+	key = append(key, spender.Bytes()...)
+	return key
+}
+
+// ContractCall_initialize credits each addresses[i] with amounts[i],
+// replacing the single derived committee address this used to mint the
+// whole supply to: a genesis.json can now fund an arbitrary allocation
+// list instead of one multi-sig address.
+func (g *SAI) ContractCall_initialize(ic InteropContext, addresses []common.Address, amounts []*big.Int) error {
 	if ic.PersistingBlock() == nil || ic.PersistingBlock().Index != 0 {
 		return ErrInitialize
 	}
-	validators, err := g.cs.Designate.GetValidators(ic.Dao(), 0)
-	if err != nil {
-		return err
+	if len(addresses) != len(amounts) {
+		return errors.New("sai: mismatched allocation addresses/amounts")
 	}
-	wei := big.NewInt(1).Exp(big.NewInt(10), big.NewInt(SAIDecimal), nil)
-	var addr common.Address
-	if validators.Len() == 1 {
-		addr = validators[0].Address()
-	} else {
-		script, err := validators.CreateDefaultMultiSigRedeemScript()
-		if err != nil {
+	total := big.NewInt(0)
+	for i, addr := range addresses {
+		if err := g.addTokens(ic.Dao(), addr, amounts[i]); err != nil {
 			return err
 		}
-		addr = hash.Hash160(script)
+		total.Add(total, amounts[i])
+	}
+	log(ic, g.Address, total.Bytes(), g.Abi.Events["initialize"].ID)
+	g.notify("initialize", total.Bytes())
+	return nil
+}
+
+// ContractCall_transfer moves amount from the caller to to, emitting a
+// Transfer event on success. It satisfies the ERC-20 transfer(address,
+// uint256) returns (bool) surface so ordinary EVM wallets can hold SAI.
+func (g *SAI) ContractCall_transfer(ic InteropContext, to common.Address, amount *big.Int) (bool, error) {
+	if amount.Sign() < 0 {
+		return false, errors.New("negative amount")
+	}
+	if err := g.transfer(ic, ic.Sender(), to, amount); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ContractCall_transferFrom moves amount from from to to on the caller's
+// behalf, debiting the allowance from granted via approve. It satisfies
+// ERC-20's transferFrom(address,address,uint256) returns (bool).
+func (g *SAI) ContractCall_transferFrom(ic InteropContext, from, to common.Address, amount *big.Int) (bool, error) {
+	if amount.Sign() < 0 {
+		return false, errors.New("negative amount")
+	}
+	spender := ic.Sender()
+	d := ic.Dao()
+	allowed := g.getAllowance(d, from, spender)
+	if allowed.Cmp(amount) < 0 {
+		return false, errors.New("insufficient allowance")
+	}
+	if err := g.transfer(ic, from, to, amount); err != nil {
+		return false, err
+	}
+	remaining := big.NewInt(0).Sub(allowed, amount)
+	g.putAllowance(d, from, spender, remaining)
+	return true, nil
+}
+
+// ContractCall_approve lets spender pull up to amount from the caller's
+// balance via transferFrom, replacing any previous allowance. It satisfies
+// ERC-20's approve(address,uint256) returns (bool).
+func (g *SAI) ContractCall_approve(ic InteropContext, spender common.Address, amount *big.Int) (bool, error) {
+	if amount.Sign() < 0 {
+		return false, errors.New("negative amount")
+	}
+	owner := ic.Sender()
+	g.putAllowance(ic.Dao(), owner, spender, amount)
+	log(ic, g.Address, encodeAmountLog(owner, spender, amount), g.Abi.Events["approval"].ID)
+	g.notify("approval", encodeAmountLog(owner, spender, amount))
+	return true, nil
+}
+
+// ContractCall_allowance returns the amount spender is still allowed to
+// pull from owner's balance.
+func (g *SAI) ContractCall_allowance(ic InteropContext, owner, spender common.Address) (*big.Int, error) {
+	return g.getAllowance(ic.Dao(), owner, spender), nil
+}
+
+// ContractCall_balanceOf returns account's current SAI balance.
+func (g *SAI) ContractCall_balanceOf(ic InteropContext, account common.Address) (*big.Int, error) {
+	return g.GetBalance(ic.Dao(), account), nil
+}
+
+// ContractCall_totalSupply returns the total amount of SAI in circulation.
+func (g *SAI) ContractCall_totalSupply(ic InteropContext) (*big.Int, error) {
+	supply := g.getTotalSupply(ic.Dao())
+	if supply == nil {
+		supply = big.NewInt(0)
+	}
+	return supply, nil
+}
+
+// ContractCall_symbol returns SAI's ticker symbol.
+func (g *SAI) ContractCall_symbol(ic InteropContext) (string, error) {
+	return g.symbol, nil
+}
+
+// ContractCall_decimals returns the number of decimals SAI amounts are
+// denominated in.
+func (g *SAI) ContractCall_decimals(ic InteropContext) (uint8, error) {
+	return uint8(g.decimals), nil
+}
+
+// ContractCall_name returns SAI's display name.
+func (g *SAI) ContractCall_name(ic InteropContext) (string, error) {
+	return g.name, nil
+}
+
+// transfer debits amount from from and credits it to to, in that order so
+// a transfer that fails for insufficient balance never writes a partial
+// state change, then emits a Transfer event.
+func (g *SAI) transfer(ic InteropContext, from, to common.Address, amount *big.Int) error {
+	d := ic.Dao()
+	if err := g.addTokens(d, from, big.NewInt(0).Neg(amount)); err != nil {
+		return err
 	}
-	total := big.NewInt(1).Mul(big.NewInt(int64(g.initialSupply)), wei)
-	err = g.addTokens(ic.Dao(), addr, total)
-	if err == nil {
-		log(ic, g.Address, total.Bytes(), g.Abi.Events["initialize"].ID)
+	if err := g.addTokens(d, to, amount); err != nil {
+		return err
 	}
-	return err
+	log(ic, g.Address, encodeAmountLog(from, to, amount), g.Abi.Events["transfer"].ID)
+	g.notify("transfer", encodeAmountLog(from, to, amount))
+	return nil
+}
+
+// encodeAmountLog packs a (address, address, uint256) triple the same way
+// non-indexed ABI event data would be: each field left-padded to a 32-byte
+// word. The log helper doesn't support indexed topics, so unlike a real
+// Solidity Transfer/Approval event, from/to/owner/spender end up in the
+// log's data rather than its topics.
+func encodeAmountLog(a, b common.Address, amount *big.Int) []byte {
+	data := make([]byte, 0, 96)
+	data = append(data, common.LeftPadBytes(a.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(b.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(amount.Bytes(), 32)...)
+	return data
+}
+
+func (g *SAI) getAllowance(d *dao.Simple, owner, spender common.Address) *big.Int {
+	si := d.GetStorageItem(g.Address, makeAllowanceKey(owner, spender))
+	if si == nil {
+		return big.NewInt(0)
+	}
+	return big.NewInt(0).SetBytes(si)
+}
+
+func (g *SAI) putAllowance(d *dao.Simple, owner, spender common.Address, amount *big.Int) {
+	key := makeAllowanceKey(owner, spender)
+	if amount.Sign() == 0 {
+		d.DeleteStorageItem(g.Address, key)
+		return
+	}
+	d.PutStorageItem(g.Address, key, amount.Bytes())
 }
 
 func (g *SAI) OnPersist(d *dao.Simple, block *block.Block) {
 
 }
 
+// increaseBalance adds amount (which may be negative, for a debit) to gs.
+// big.Int has no fixed width to overflow, so underflow - debiting more than
+// gs holds - is the only hazard, and it's rejected before Add ever mutates
+// gs.Balance.
 func (g *SAI) increaseBalance(gs *GasState, amount *big.Int) error {
-	if amount.Sign() == -1 && gs.Balance.CmpAbs(amount) == -1 {
+	if amount.Sign() < 0 && gs.Balance.CmpAbs(amount) < 0 {
 		return errors.New("insufficient funds")
 	}
 	gs.Balance.Add(gs.Balance, amount)
@@ -115,6 +327,9 @@ func (g *SAI) saveTotalSupply(d *dao.Simple, supply *big.Int) {
 }
 
 func (g *SAI) getGasState(d *dao.Simple, key []byte) (*GasState, error) {
+	if balance, ok := g.snapshotBalance(key); ok {
+		return &GasState{Balance: balance}, nil
+	}
 	si := d.GetStorageItem(g.Address, key)
 	if si == nil {
 		return nil, nil
@@ -171,6 +386,28 @@ func (g *SAI) addTokens(d *dao.Simple, h common.Address, amount *big.Int) error
 	return nil
 }
 
+// Burn permanently removes amount from circulation by debiting totalSupply
+// without crediting any account - the way an EIP-1559 base fee is
+// destroyed rather than paid to the block proposer, unlike the tip, which
+// still flows to an account via AddBalance. It's meant to be called from
+// OnPersist with each block's baseFee*gasUsed once that accounting exists;
+// see policy_doc.go for why it isn't wired up there yet.
+func (g *SAI) Burn(d *dao.Simple, amount *big.Int) error {
+	if amount.Sign() == 0 {
+		return nil
+	}
+	if amount.Sign() < 0 {
+		return errors.New("negative amount")
+	}
+	supply := g.getTotalSupply(d)
+	if supply == nil || supply.Cmp(amount) < 0 {
+		return errors.New("insufficient supply to burn")
+	}
+	supply.Sub(supply, amount)
+	g.saveTotalSupply(d, supply)
+	return nil
+}
+
 func (g *SAI) AddBalance(d *dao.Simple, h common.Address, amount *big.Int) {
 	g.addTokens(d, h, amount)
 }
@@ -192,6 +429,9 @@ func (g *SAI) balanceFromBytes(si *state.StorageItem) (*big.Int, error) {
 
 func (g *SAI) GetBalance(d *dao.Simple, h common.Address) *big.Int {
 	key := makeAccountKey(h)
+	if balance, ok := g.snapshotBalance(key); ok {
+		return balance
+	}
 	si := d.GetStorageItem(g.Address, key)
 	if si == nil {
 		return big.NewInt(0)
@@ -203,6 +443,60 @@ func (g *SAI) GetBalance(d *dao.Simple, h common.Address) *big.Int {
 	return balance
 }
 
+// StateDBI is the slice of pkg/evm/vm.StateDB's surface GetBalanceState
+// and Transfer need to read and mutate an address's EVM-visible balance,
+// the same interface block_context.go's vm.BlockContext.CanTransfer/
+// Transfer closures already take for the EVM's own BALANCE/CALL-
+// value-transfer opcodes. Any *vm.StateDB satisfies it structurally, but
+// g doesn't need to import pkg/evm/vm itself to call through it - the same
+// reason InteropContext exists instead of every native contract importing
+// the interpreter package directly.
+type StateDBI interface {
+	GetBalance(addr common.Address) *big.Int
+	AddBalance(addr common.Address, amount *big.Int)
+}
+
+// GetBalanceState returns addr's balance as the EVM state trie sees it via
+// sdb, rather than g's own prefixAccount storage (see GetBalance). Once
+// Transfer is the only thing moving SAI during EVM execution, the two stay
+// in lockstep; this exists for callers already holding a StateDBI (e.g. an
+// EVM precompile) that would otherwise have no way to read a balance
+// without also holding a *dao.Simple.
+func (g *SAI) GetBalanceState(sdb StateDBI, addr common.Address) *big.Int {
+	return sdb.GetBalance(addr)
+}
+
+// Transfer moves amount from from to to in sdb - the EVM state trie's view
+// of SAI - debiting via AddBalance with a negated amount rather than a
+// separate SubBalance, the same convention block_context.go's
+// vm.BlockContext.Transfer closure already uses, then replays the same
+// move against g's own prefixAccount storage via d so GetBalance/
+// ContractCall_balanceOf keep agreeing with whatever ordinary EVM opcodes
+// just did to the state trie. d isn't part of the Transfer(sdb, from, to,
+// amount) shape this was sketched with, but there's no syncing prefixAccount
+// without it - this isn't a ContractCall_ method, so there's no
+// InteropContext.Dao() to pull it from, and the caller (whatever wires
+// sdb's EVM execution into block persistence) has to pass it explicitly.
+func (g *SAI) Transfer(sdb StateDBI, d *dao.Simple, from, to common.Address, amount *big.Int) error {
+	if amount.Sign() < 0 {
+		return errors.New("sai: negative amount")
+	}
+	if sdb.GetBalance(from).Cmp(amount) < 0 {
+		return errors.New("sai: insufficient balance")
+	}
+	neg := big.NewInt(0).Neg(amount)
+	sdb.AddBalance(from, neg)
+	sdb.AddBalance(to, amount)
+	if err := g.addTokens(d, from, neg); err != nil {
+		return err
+	}
+	return g.addTokens(d, to, amount)
+}
+
+// RequiredGas looks up input's method by its 4-byte selector and returns
+// the cost g.gasPrices has on file for it, 0 for initialize (only callable
+// once, from the genesis block, never by a metered caller) and for any
+// method gasPrices doesn't recognize.
 func (g *SAI) RequiredGas(ic InteropContext, input []byte) uint64 {
 	if len(input) < 4 {
 		return 0
@@ -211,15 +505,21 @@ func (g *SAI) RequiredGas(ic InteropContext, input []byte) uint64 {
 	if err != nil {
 		return 0
 	}
-	switch method.Name {
-	case "initialize":
-		return 0
-	default:
-		return 0
-	}
+	return g.gasPrices[method.Name]
 }
 
+// Run charges RequiredGas's cost against the caller's own SAI balance -
+// burning it the same way Burn destroys a base fee, rather than crediting
+// it anywhere - before dispatching, so a call that can't afford its method
+// never reaches contractCall.
 func (g *SAI) Run(ic InteropContext, input []byte) ([]byte, error) {
+	updateNativeCallsMetric(g.Name)
+	if cost := g.RequiredGas(ic, input); cost > 0 {
+		fee := new(big.Int).SetUint64(cost)
+		if err := g.addTokens(ic.Dao(), ic.Sender(), fee.Neg(fee)); err != nil {
+			return nil, ErrInsufficientGas
+		}
+	}
 	return contractCall(g, &g.NativeContract, ic, input)
 }
 
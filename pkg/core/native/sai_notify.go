@@ -0,0 +1,166 @@
+package native
+
+import "github.com/multiverse-dev/saiya/pkg/core/state"
+
+// saiNotifyBufferSize is the capacity of each subscriber's ring buffer.
+// saiNotifyDispatcher never sends on a subscriber's channel directly; it
+// only ever pushes into this buffer, which the subscriber's own goroutine
+// drains at its own pace - the same split pkg/core/mempool's
+// notificationDispatcher/subscriber pair uses so a slow consumer only ever
+// blocks its own goroutine.
+const saiNotifyBufferSize = 256
+
+// saiSubscriber wraps a subscription's channel with a bounded ring buffer
+// and the goroutine that drains it. Unlike pkg/core/mempool's subscriber,
+// there's no DropPolicy/Filter here: a full buffer just drops the oldest
+// entry, and every subscriber sees every event, since SAI only has three
+// event kinds and nothing yet needs finer-grained delivery.
+type saiSubscriber struct {
+	ch chan<- *state.NotificationEvent
+
+	buf  []*state.NotificationEvent
+	head int
+	size int
+
+	notify chan struct{}
+	stopCh chan struct{}
+}
+
+func newSAISubscriber(ch chan<- *state.NotificationEvent) *saiSubscriber {
+	return &saiSubscriber{
+		ch:     ch,
+		buf:    make([]*state.NotificationEvent, saiNotifyBufferSize),
+		notify: make(chan struct{}, 1),
+		stopCh: make(chan struct{}),
+	}
+}
+
+// push enqueues e for delivery, dropping the oldest buffered event if the
+// ring buffer is already full. It's only ever called from
+// saiNotifyDispatcher.run and never blocks.
+func (s *saiSubscriber) push(e *state.NotificationEvent) {
+	if s.size == len(s.buf) {
+		s.head = (s.head + 1) % len(s.buf)
+		s.size--
+	}
+	s.buf[(s.head+s.size)%len(s.buf)] = e
+	s.size++
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// pop removes and returns the oldest buffered event, if any. It's only
+// ever called from s.run.
+func (s *saiSubscriber) pop() (*state.NotificationEvent, bool) {
+	if s.size == 0 {
+		return nil, false
+	}
+	e := s.buf[s.head]
+	s.head = (s.head + 1) % len(s.buf)
+	s.size--
+	return e, true
+}
+
+// run delivers buffered events to s.ch until s.stopCh is closed. It's the
+// only goroutine that ever sends on s.ch.
+func (s *saiSubscriber) run() {
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-s.notify:
+		}
+		for {
+			e, ok := s.pop()
+			if !ok {
+				break
+			}
+			select {
+			case s.ch <- e:
+			case <-s.stopCh:
+				return
+			}
+		}
+	}
+}
+
+// saiNotifyDispatcher manages subscriptions to a SAI contract's
+// transfer/approval/initialize notification feed and fans new events out
+// to them, the same sub/unsub-channel shape pkg/core/mempool's
+// notificationDispatcher uses. subCh/unsubCh are only ever read from
+// run, which is why Subscribe/UnsubscribeSAIEvents just send on them
+// rather than touching the subscriber map directly - run keeps draining
+// both regardless of what else it's doing, so a concurrent Unsubscribe
+// call never deadlocks against an in-flight event delivery.
+type saiNotifyDispatcher struct {
+	subCh   chan chan<- *state.NotificationEvent
+	unsubCh chan chan<- *state.NotificationEvent
+	events  chan *state.NotificationEvent
+	stopCh  chan struct{}
+}
+
+func newSAINotifyDispatcher() *saiNotifyDispatcher {
+	d := &saiNotifyDispatcher{
+		subCh:   make(chan chan<- *state.NotificationEvent),
+		unsubCh: make(chan chan<- *state.NotificationEvent),
+		events:  make(chan *state.NotificationEvent),
+		stopCh:  make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+func (d *saiNotifyDispatcher) run() {
+	subs := make(map[chan<- *state.NotificationEvent]*saiSubscriber)
+	for {
+		select {
+		case <-d.stopCh:
+			for _, sub := range subs {
+				close(sub.stopCh)
+			}
+			return
+		case ch := <-d.subCh:
+			sub := newSAISubscriber(ch)
+			subs[ch] = sub
+			go sub.run()
+		case ch := <-d.unsubCh:
+			if sub, ok := subs[ch]; ok {
+				delete(subs, ch)
+				close(sub.stopCh)
+			}
+		case evt := <-d.events:
+			for _, sub := range subs {
+				sub.push(evt)
+			}
+		}
+	}
+}
+
+// SubscribeSAIEvents adds ch to g's transfer/approval/initialize
+// notification feed: every ContractCall_transfer, ContractCall_transferFrom,
+// ContractCall_approve and ContractCall_initialize raises one on it. Call
+// UnsubscribeSAIEvents with the same channel when done; ch is never closed
+// by g.
+func (g *SAI) SubscribeSAIEvents(ch chan<- *state.NotificationEvent) {
+	g.notifyDispatcher.subCh <- ch
+}
+
+// UnsubscribeSAIEvents removes ch from g's notification feed. Passing an
+// unsubscribed channel is a no-op.
+func (g *SAI) UnsubscribeSAIEvents(ch chan<- *state.NotificationEvent) {
+	g.notifyDispatcher.unsubCh <- ch
+}
+
+// notify raises a NotificationEvent named name on g's subscription feed,
+// alongside whatever log(ic, ...) already wrote to the EVM-style log -
+// this exists for RPC subscribers that want SAI's transfer/approval/
+// initialize events as a push feed rather than polling eth_getLogs.
+func (g *SAI) notify(name string, data []byte) {
+	g.notifyDispatcher.events <- &state.NotificationEvent{
+		ScriptHash: g.Address,
+		Name:       name,
+		Data:       data,
+	}
+}
@@ -0,0 +1,50 @@
+package native
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/core/snapshot"
+	"github.com/multiverse-dev/saiya/pkg/io"
+)
+
+// ActiveSnapshot, when non-nil, lets SAI's read paths answer balance
+// queries straight out of pkg/core/snapshot's flat layer stack instead of
+// paying for a dao.Simple -> storage trie round trip on every call. It's a
+// package var rather than a field on Contracts because NewContracts'
+// constructor signature (config.ProtocolConfiguration only) has no node-
+// level wiring point to pass a *snapshot.Tree built elsewhere through; see
+// mempool.MetricsEnabled for the same shape of constraint.
+var ActiveSnapshot *snapshot.Tree
+
+// snapshotBalance looks up key (as built by makeAccountKey) in
+// ActiveSnapshot's head layer, returning the decoded GasState.Balance and
+// true on a hit, or false if there's no active snapshot or the layer
+// doesn't hold the key (in which case the caller should fall back to d).
+func (g *SAI) snapshotBalance(key []byte) (*big.Int, bool) {
+	return snapshotBalance(g.Address, key)
+}
+
+// snapshotBalance is the Sai (GAS) counterpart of SAI.snapshotBalance; the
+// two token contracts keep separate, near-identical implementations
+// throughout this package (see gas.go's addTokens/GetBalance vs sai.go's),
+// so this stays a package function taking the contract address explicitly
+// rather than a method on either type.
+func (g *Sai) snapshotBalance(key []byte) (*big.Int, bool) {
+	return snapshotBalance(g.Address, key)
+}
+
+func snapshotBalance(contract common.Address, key []byte) (*big.Int, bool) {
+	if ActiveSnapshot == nil {
+		return nil, false
+	}
+	raw, found := ActiveSnapshot.Head().Storage(contract, key)
+	if !found {
+		return nil, false
+	}
+	gs := &GasState{}
+	if err := io.FromByteArray(gs, raw); err != nil {
+		return nil, false
+	}
+	return gs.Balance, true
+}
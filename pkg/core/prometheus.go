@@ -1,6 +1,8 @@
 package core
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -30,6 +32,14 @@ var (
 			Namespace: "saiya",
 		},
 	)
+	//blockProcessingDuration prometheus metric.
+	blockProcessingDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Help:      "Time to process (verify and persist) a single block",
+			Name:      "block_processing_duration_seconds",
+			Namespace: "saiya",
+		},
+	)
 )
 
 func init() {
@@ -37,9 +47,19 @@ func init() {
 		blockHeight,
 		persistedHeight,
 		headerHeight,
+		blockProcessingDuration,
 	)
 }
 
+// ObserveBlockProcessingDuration records how long processing a block took,
+// starting from start. Like updateBlockHeightMetric/updatePersistedHeightMetric
+// below, nothing in this snapshot calls it yet - there's no Blockchain.Persist
+// loop here to wrap, only the gauges and this histogram a future one would
+// update.
+func ObserveBlockProcessingDuration(start time.Time) {
+	blockProcessingDuration.Observe(time.Since(start).Seconds())
+}
+
 func updatePersistedHeightMetric(pHeight uint32) {
 	persistedHeight.Set(float64(pHeight))
 }
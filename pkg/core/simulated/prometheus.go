@@ -0,0 +1,60 @@
+package simulated
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/core/native"
+	"github.com/multiverse-dev/saiya/pkg/core/native/nativenames"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics for monitoring EVM execution time.
+var (
+	//evmExecutionDuration prometheus metric.
+	evmExecutionDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Help:      "Time to execute a single transaction's call into the EVM, labeled by its destination",
+			Name:      "evm_execution_duration_seconds",
+			Namespace: "saiya",
+		},
+		[]string{"to"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		evmExecutionDuration,
+	)
+}
+
+// MetricsEnabled gates observeEVMExecution so config.Metrics.DisableEVM can
+// opt this family out; see mempool.MetricsEnabled for why this is a
+// package var rather than read from config directly.
+var MetricsEnabled = true
+
+// evmDestinationLabel reports to's "to" label: the name of the native
+// precompile it addresses (e.g. "SaiToken"), or "contract" for any
+// other address, so a dashboard can separate native-call latency from
+// ordinary contract execution without a high-cardinality per-address label.
+func evmDestinationLabel(to common.Address) string {
+	switch to {
+	case native.SAIAddress:
+		return nativenames.Sai
+	case native.PolicyAddress:
+		return nativenames.Policy
+	case native.DesignationAddress:
+		return nativenames.Designation
+	default:
+		return "contract"
+	}
+}
+
+// observeEVMExecution records how long an EVM call to to took, starting
+// from start.
+func observeEVMExecution(to common.Address, start time.Time) {
+	if !MetricsEnabled {
+		return
+	}
+	evmExecutionDuration.WithLabelValues(evmDestinationLabel(to)).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,48 @@
+package snapshot
+
+import "github.com/ethereum/go-ethereum/common"
+
+// storageKey identifies a single storage slot within a contract, the same
+// (contract, key) pair native.SAI's makeAccountKey/makeAllowanceKey fold
+// into one dao storage key.
+type storageKey struct {
+	contract common.Address
+	key      string
+}
+
+// diffLayer is an in-memory layer holding every storage write made while
+// processing a single block, on top of its parent layer. A nil value
+// marks a slot deleted by this block, distinct from a slot this layer
+// simply never touched (which falls through to Parent).
+type diffLayer struct {
+	root    common.Hash
+	parent  Layer
+	storage map[storageKey][]byte
+}
+
+// newDiffLayer builds a diffLayer for root on top of parent from the
+// (contract, key) -> value writes made by the block that produced root.
+func newDiffLayer(parent Layer, root common.Hash, writes map[storageKey][]byte) *diffLayer {
+	return &diffLayer{root: root, parent: parent, storage: writes}
+}
+
+// Root implements Layer.
+func (d *diffLayer) Root() common.Hash {
+	return d.root
+}
+
+// Parent implements Layer.
+func (d *diffLayer) Parent() Layer {
+	return d.parent
+}
+
+// Storage implements Layer.
+func (d *diffLayer) Storage(contract common.Address, key []byte) ([]byte, bool) {
+	if v, ok := d.storage[storageKey{contract, string(key)}]; ok {
+		return v, v != nil
+	}
+	if d.parent == nil {
+		return nil, false
+	}
+	return d.parent.Storage(contract, key)
+}
@@ -0,0 +1,60 @@
+package snapshot
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/core/storage"
+)
+
+// diskLayer is the base of the layer stack: every diff layer that falls
+// deeper than the tree's retained depth gets merged down into it in turn,
+// the same way an MPT's older generations get garbage-collected once no
+// live trie references them any more.
+type diskLayer struct {
+	root  common.Hash
+	store *storage.MemCachedStore
+}
+
+// Root implements Layer.
+func (d *diskLayer) Root() common.Hash {
+	return d.root
+}
+
+// Parent implements Layer; the disk layer has none.
+func (d *diskLayer) Parent() Layer {
+	return nil
+}
+
+// Storage implements Layer.
+func (d *diskLayer) Storage(contract common.Address, key []byte) ([]byte, bool) {
+	v, err := d.store.Get(encodeKey(contract, key))
+	if err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// flatten merges layer's writes into d in place and advances d.root to
+// layer.root. The caller (Tree.Cap) is responsible for only ever flattening
+// the diff layer directly above d, in order, so this never skips a
+// generation.
+func (d *diskLayer) flatten(layer *diffLayer) {
+	for k, v := range layer.storage {
+		storeKey := encodeKey(k.contract, []byte(k.key))
+		if v == nil {
+			d.store.Delete(storeKey)
+		} else {
+			d.store.Put(storeKey, v)
+		}
+	}
+	d.root = layer.root
+}
+
+// encodeKey folds a (contract, key) pair into the single flat key the disk
+// layer's store indexes by, contract first so one contract's keys sit
+// contiguously in the store's key order.
+func encodeKey(contract common.Address, key []byte) []byte {
+	out := make([]byte, 0, common.AddressLength+len(key))
+	out = append(out, contract.Bytes()...)
+	out = append(out, key...)
+	return out
+}
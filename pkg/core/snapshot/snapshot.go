@@ -0,0 +1,34 @@
+// Package snapshot maintains a flat, account-and-storage-keyed mirror of
+// chain state alongside the trie-based pkg/core/statedb and pkg/core/mpt,
+// the same disk-layer-plus-diff-layers design go-ethereum's
+// core/state/snapshot package uses: each newly committed block stacks a
+// diff layer holding just that block's writes on top of the previous
+// layer, and only once a diff layer falls deeper than the tree's retained
+// depth does it get flattened down into the disk layer.
+//
+// Its purpose is twofold: a full node can answer a GetSnapshotRange
+// request from a syncing peer with a single ordered scan over the disk
+// layer instead of an MPT walk, and hot read paths like native.SAI's
+// GetBalance/getGasState can consult the snapshot directly instead of
+// paying for trie traversal on every call.
+package snapshot
+
+import "github.com/ethereum/go-ethereum/common"
+
+// Layer is one level of the snapshot: either the disk layer or a diff
+// layer stacked on top of another Layer.
+type Layer interface {
+	// Root returns the state root this layer was built for.
+	Root() common.Hash
+
+	// Storage returns the value stored under key in contract's storage as
+	// of this layer, and whether it was found. A miss (found=false) means
+	// the caller should fall back to the trie rather than treat the slot
+	// as empty, since a layer only ever represents what's been loaded or
+	// written through it, not the full key space.
+	Storage(contract common.Address, key []byte) (value []byte, found bool)
+
+	// Parent returns the layer this one was built on top of, or nil for
+	// the disk layer.
+	Parent() Layer
+}
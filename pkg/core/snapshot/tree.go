@@ -0,0 +1,128 @@
+package snapshot
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/core/storage"
+)
+
+// retainedDepth is how many diff layers Tree keeps in memory behind the
+// current head before flattening the oldest one into the disk layer, the
+// same tradeoff go-ethereum's snapshot tree makes between reorg depth and
+// memory: deep enough to survive an ordinary chain reorg, shallow enough
+// that memory doesn't grow without bound.
+const retainedDepth = 128
+
+// ErrSnapshotMissing is returned when a lookup or Cap names a root Tree
+// has no layer for, either because it was never built or because it has
+// already been capped away.
+var ErrSnapshotMissing = errors.New("snapshot: unknown root")
+
+// Tree indexes every retained Layer by its root, letting concurrent
+// readers resolve storage lookups against whichever block they're
+// processing against without blocking on the layer that's actively being
+// built on top.
+type Tree struct {
+	mu     sync.RWMutex
+	layers map[common.Hash]Layer
+	disk   *diskLayer
+	head   common.Hash
+}
+
+// NewTree returns a Tree whose disk layer is diskRoot, backed by store.
+// Callers building a fresh chain pass the genesis root; callers resuming
+// from an existing database pass whatever root the disk layer was last
+// flattened to.
+func NewTree(store *storage.MemCachedStore, diskRoot common.Hash) *Tree {
+	disk := &diskLayer{root: diskRoot, store: store}
+	return &Tree{
+		layers: map[common.Hash]Layer{diskRoot: disk},
+		disk:   disk,
+		head:   diskRoot,
+	}
+}
+
+// Head returns the layer at the tip of the tree, i.e. whatever root the
+// most recently applied Update call produced (or the disk layer, before
+// any Update has run). Read paths that want the latest state rather than
+// a specific historical root - like native.SAI.GetBalance - go through
+// this instead of tracking a root of their own.
+func (t *Tree) Head() Layer {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.layers[t.head]
+}
+
+// Snapshot returns the Layer for root, or ErrSnapshotMissing if Tree holds
+// none.
+func (t *Tree) Snapshot(root common.Hash) (Layer, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	l, ok := t.layers[root]
+	if !ok {
+		return nil, ErrSnapshotMissing
+	}
+	return l, nil
+}
+
+// Update stacks a new diff layer for root on top of parentRoot, recording
+// writes as the (contract, key) -> value pairs that changed while
+// processing the block that produced root. A nil value marks a deletion.
+func (t *Tree) Update(parentRoot, root common.Hash, writes map[storageKey][]byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	parent, ok := t.layers[parentRoot]
+	if !ok {
+		return ErrSnapshotMissing
+	}
+	t.layers[root] = newDiffLayer(parent, root, writes)
+	t.head = root
+	return nil
+}
+
+// Cap flattens any diff layer more than retainedDepth layers below root
+// into the disk layer, oldest first, dropping it from the index and
+// re-parenting the layer above it onto the disk layer. Layers built on a
+// flattened layer that aren't on root's chain stay in the index. It's meant to be called once per
+// committed block, mirroring the way pkg/core/mpt relies on its own
+// generation counter to know when a node can be collected.
+func (t *Tree) Cap(root common.Hash) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	chain, err := t.chain(root)
+	if err != nil {
+		return err
+	}
+	// chain[0] is the disk layer; chain[1] is the oldest diff layer.
+	for len(chain)-1 > retainedDepth {
+		bottom, ok := chain[1].(*diffLayer)
+		if !ok {
+			break
+		}
+		t.disk.flatten(bottom)
+		delete(t.layers, bottom.Root())
+		t.layers[t.disk.root] = t.disk
+		if next, ok := chain[2].(*diffLayer); ok {
+			next.parent = t.disk
+		}
+		chain = append(chain[:1], chain[2:]...)
+	}
+	return nil
+}
+
+// chain walks root back to the disk layer, returning layers oldest-first.
+func (t *Tree) chain(root common.Hash) ([]Layer, error) {
+	l, ok := t.layers[root]
+	if !ok {
+		return nil, ErrSnapshotMissing
+	}
+	var chain []Layer
+	for l != nil {
+		chain = append([]Layer{l}, chain...)
+		l = l.Parent()
+	}
+	return chain, nil
+}
@@ -0,0 +1,20 @@
+package state
+
+import "github.com/ethereum/go-ethereum/common"
+
+// NotificationEvent is a single contract-emitted notification, modeled on
+// nspcc-dev/neo-go's own NotificationEvent but adapted from a NeoVM stack
+// item payload to the raw ABI-encoded Data this tree's native contracts
+// already build (see native.SAI's encodeAmountLog and friends) rather than
+// introducing a stackitem dependency just for this.
+type NotificationEvent struct {
+	// ScriptHash is the contract that raised the notification.
+	ScriptHash common.Address
+	// Name is the event name, e.g. "transfer", "approval" or
+	// "initialize" for native.SAI - the same key its ABI's Events map
+	// uses.
+	Name string
+	// Data is the event's ABI-encoded payload, in whatever shape Name's
+	// event defines it.
+	Data []byte
+}
@@ -0,0 +1,45 @@
+package stateroot
+
+import (
+	"encoding/binary"
+
+	"github.com/multiverse-dev/saiya/pkg/core/mpt"
+	"github.com/multiverse-dev/saiya/pkg/core/storage"
+)
+
+// prefixPendingDelete indexes MPT nodes that Flush marked inactive at a
+// given height, keyed by (height, hash) under DataMPTAux. GC drains this
+// index directly instead of rescanning every node under DataMPT to find
+// the ones eligible for removal, which is what made GC pause time scale
+// with total chain size rather than with how much changed since the last
+// run.
+const prefixPendingDelete = 0x04
+
+// pendingDeleteKey builds the prefixPendingDelete index key for a node
+// hash made inactive at height, with height encoded big-endian so a seek
+// over the bare prefix visits entries in ascending height order.
+func pendingDeleteKey(height uint32, hash []byte) []byte {
+	key := make([]byte, 2+4, 2+4+len(hash))
+	key[0] = byte(storage.DataMPTAux)
+	key[1] = prefixPendingDelete
+	binary.BigEndian.PutUint32(key[2:6], height)
+	return append(key, hash...)
+}
+
+// queueBatchPendingDeletes scans cache - the small per-block overlay
+// AddMPTBatch just flushed a batch into, not the full store - for nodes
+// Flush marked inactive, and indexes each one's hash under
+// prefixPendingDelete by the height it became inactive at, so a later GC
+// can find it without rescanning all of DataMPT. Since cache only ever
+// holds this block's dirty keys, this pass costs O(dirty), the same as the
+// write it's riding alongside.
+func (s *Module) queueBatchPendingDeletes(cache *storage.MemCachedStore) {
+	cache.Seek(storage.SeekRange{Prefix: []byte{byte(storage.DataMPT)}}, func(k, v []byte) bool {
+		if !mpt.IsActiveValue(v) {
+			hash := append([]byte{}, k[1:]...)
+			height := binary.LittleEndian.Uint32(v[len(v)-4:])
+			cache.Put(pendingDeleteKey(height, hash), hash)
+		}
+		return true
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/multiverse-dev/saiya/pkg/core/state"
 	"github.com/multiverse-dev/saiya/pkg/core/storage"
 	"github.com/multiverse-dev/saiya/pkg/core/transaction"
+	"github.com/multiverse-dev/saiya/pkg/crypto/bls"
 	"github.com/multiverse-dev/saiya/pkg/crypto/hash"
 	"github.com/multiverse-dev/saiya/pkg/crypto/keys"
 	"go.uber.org/atomic"
@@ -25,12 +26,14 @@ type (
 	VerifierFunc func(common.Address, hash.Hashable, *transaction.Witness) error
 	// Module represents module for local processing of state roots.
 	Module struct {
-		Store    *storage.MemCachedStore
-		chainId  uint64
-		mode     mpt.TrieMode
-		mpt      *mpt.Trie
-		verifier VerifierFunc
-		log      *zap.Logger
+		Store   *storage.MemCachedStore
+		chainId uint64
+		mode    mpt.TrieMode
+		mpt     *mpt.Trie
+		log     *zap.Logger
+
+		verifiersMtx sync.RWMutex
+		verifiers    map[string]VerifierFunc
 
 		currentLocal    atomic.Value
 		localHeight     atomic.Uint32
@@ -43,14 +46,19 @@ type (
 	}
 
 	keyCache struct {
-		height           uint32
-		validatorsKeys   keys.PublicKeys
-		validatorsHash   common.Address
-		validatorsScript []byte
+		height            uint32
+		validatorsKeys    keys.PublicKeys
+		validatorsHash    common.Address
+		validatorsScript  []byte
+		validatorsBLSKeys []*bls.PublicKey
+		aggregatedBLSKey  *bls.PublicKey
 	}
 )
 
-// NewModule returns new instance of stateroot module.
+// NewModule returns new instance of stateroot module. verif is registered as
+// the verifier for TagECDSAMultisig, the scheme every witness used before
+// RegisterVerifier existed; other schemes (e.g. TagBLSAgg, registered by this
+// constructor too) can be swapped out with RegisterVerifier.
 func NewModule(cfg config.ProtocolConfiguration, verif VerifierFunc, log *zap.Logger, s *storage.MemCachedStore) *Module {
 	var mode mpt.TrieMode
 	if cfg.KeepOnlyLatestState {
@@ -59,13 +67,17 @@ func NewModule(cfg config.ProtocolConfiguration, verif VerifierFunc, log *zap.Lo
 	if cfg.RemoveUntraceableBlocks {
 		mode |= mpt.ModeGC
 	}
-	return &Module{
-		chainId:  cfg.ChainID,
-		mode:     mode,
-		verifier: verif,
-		log:      log,
-		Store:    s,
+	m := &Module{
+		chainId: cfg.ChainID,
+		mode:    mode,
+		log:     log,
+		Store:   s,
+		verifiers: map[string]VerifierFunc{
+			TagECDSAMultisig: verif,
+		},
 	}
+	m.verifiers[TagBLSAgg] = m.verifyBLSWitness
+	return m
 }
 
 // GetState returns value at the specified key fom the MPT with the specified root.
@@ -88,6 +100,10 @@ func (s *Module) FindStates(root common.Hash, prefix, start []byte, max int) ([]
 
 // GetStateProof returns proof of having key in the MPT with the specified root.
 func (s *Module) GetStateProof(root common.Hash, key []byte) ([][]byte, error) {
+	stateProofRequests.Inc()
+	if root == s.CurrentLocalStateRoot() {
+		stateProofCacheHits.Inc()
+	}
 	// Allow accessing old values, it's RO thing.
 	tr := mpt.NewTrie(mpt.NewHashNode(root), s.mode&^mpt.ModeGCFlag, storage.NewMemCachedStore(s.Store))
 	return tr.GetProof(key)
@@ -117,7 +133,9 @@ func (s *Module) CurrentValidatedHeight() uint32 {
 func (s *Module) Init(height uint32) error {
 	data, err := s.Store.Get([]byte{byte(storage.DataMPTAux), prefixValidated})
 	if err == nil {
-		s.validatedHeight.Store(binary.LittleEndian.Uint32(data))
+		validated := binary.LittleEndian.Uint32(data)
+		s.validatedHeight.Store(validated)
+		updateStateHeightMetric(validated)
 	}
 
 	if height == 0 {
@@ -131,6 +149,7 @@ func (s *Module) Init(height uint32) error {
 	}
 	s.currentLocal.Store(r.Root)
 	s.localHeight.Store(r.Index)
+	stateRootLocalHeight.Set(float64(r.Index))
 	s.mpt = mpt.NewTrie(mpt.NewHashNode(r.Root), s.mode, s.Store)
 	return nil
 }
@@ -164,42 +183,46 @@ func (s *Module) JumpToState(sr *state.MPTRoot) {
 	binary.LittleEndian.PutUint32(data, sr.Index)
 	s.Store.Put([]byte{byte(storage.DataMPTAux), prefixValidated}, data)
 	s.validatedHeight.Store(sr.Index)
+	updateStateHeightMetric(sr.Index)
 
 	s.currentLocal.Store(sr.Root)
 	s.localHeight.Store(sr.Index)
+	stateRootLocalHeight.Set(float64(sr.Index))
 	s.mpt = mpt.NewTrie(mpt.NewHashNode(sr.Root), s.mode, s.Store)
 }
 
-// GC performs garbage collection.
+// GC performs garbage collection. Instead of rescanning every node under
+// DataMPT to find the ones Flush marked inactive (an O(total state) scan
+// that used to dominate GC pause time on a large chain), it drains the
+// prefixPendingDelete index that queueBatchPendingDeletes built up as those
+// nodes were written, so pause time scales with how much changed since the
+// last run.
 func (s *Module) GC(index uint32, store storage.Store) time.Duration {
 	if !s.mode.GC() {
 		panic("stateroot: GC invoked, but not enabled")
 	}
 	var removed int
-	var stored int64
 	s.log.Info("starting MPT garbage collection", zap.Uint32("index", index))
 	start := time.Now()
 	err := store.SeekGC(storage.SeekRange{
-		Prefix: []byte{byte(storage.DataMPT)},
+		Prefix: []byte{byte(storage.DataMPTAux), prefixPendingDelete},
 	}, func(k, v []byte) bool {
-		stored++
-		if !mpt.IsActiveValue(v) {
-			h := binary.LittleEndian.Uint32(v[len(v)-4:])
-			if h <= index {
-				removed++
-				stored--
-				return false
-			}
+		height := binary.BigEndian.Uint32(k[2:6])
+		if height > index {
+			return true
 		}
-		return true
+		store.Delete(append([]byte{byte(storage.DataMPT)}, v...))
+		removed++
+		return false
 	})
 	dur := time.Since(start)
+	mptGCDuration.Observe(dur.Seconds())
+	mptGCRemovedNodes.Add(float64(removed))
 	if err != nil {
 		s.log.Error("failed to flush MPT GC changeset", zap.Duration("time", dur), zap.Error(err))
 	} else {
 		s.log.Info("finished MPT garbage collection",
 			zap.Int("removed", removed),
-			zap.Int64("kept", stored),
 			zap.Duration("time", dur))
 	}
 	return dur
@@ -207,12 +230,18 @@ func (s *Module) GC(index uint32, store storage.Store) time.Duration {
 
 // AddMPTBatch updates using provided batch.
 func (s *Module) AddMPTBatch(index uint32, b mpt.Batch, cache *storage.MemCachedStore) (*mpt.Trie, *state.MPTRoot, error) {
+	start := time.Now()
+	defer func() { mptAddBatchDuration.Observe(time.Since(start).Seconds()) }()
+
 	mpt := *s.mpt
 	mpt.Store = cache
 	if _, err := mpt.PutBatch(b); err != nil {
 		return nil, nil, err
 	}
 	mpt.Flush(index)
+	if s.mode.GC() {
+		s.queueBatchPendingDeletes(cache)
+	}
 	sr := &state.MPTRoot{
 		Index: index,
 		Root:  mpt.StateRoot(),
@@ -226,6 +255,7 @@ func (s *Module) UpdateCurrentLocal(mpt *mpt.Trie, sr *state.MPTRoot) {
 	s.mpt = mpt
 	s.currentLocal.Store(sr.Root)
 	s.localHeight.Store(sr.Index)
+	stateRootLocalHeight.Set(float64(sr.Index))
 }
 
 // VerifyStateRoot checks if state root is valid.
@@ -241,11 +271,3 @@ func (s *Module) VerifyStateRoot(r *state.MPTRoot) error {
 }
 
 const maxVerificationGAS = 2_00000000
-
-// verifyWitness verifies state root witness.
-func (s *Module) verifyWitness(r *state.MPTRoot) error {
-	s.mtx.Lock()
-	h := s.getKeyCacheForHeight(r.Index).validatorsHash
-	s.mtx.Unlock()
-	return s.verifier(h, r, &r.Witness)
-}
@@ -0,0 +1,33 @@
+package stateroot
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/core/mpt"
+	"github.com/multiverse-dev/saiya/pkg/core/storage"
+)
+
+// ServeTrieNodes answers a GetTrieNodes request: it returns the raw encoded
+// node data stored under each of hashes, skipping any this node doesn't
+// have (nodes are content-addressed by their own hash, so the caller isn't
+// expected to have rooted the request at any particular state root).
+func (s *Module) ServeTrieNodes(hashes []common.Hash) [][]byte {
+	nodes := make([][]byte, 0, len(hashes))
+	for _, h := range hashes {
+		data, err := mpt.GetNodeData(s.Store, h)
+		if err != nil {
+			continue
+		}
+		nodes = append(nodes, data)
+	}
+	return nodes
+}
+
+// ServeAccountRange answers a GetAccountRange request: it returns every
+// leaf of the MPT rooted at root whose key falls within [start, end] along
+// with the boundary proof authenticating them, for a state-sync client
+// bootstrapping via snap-style sync instead of replaying blocks.
+func (s *Module) ServeAccountRange(root common.Hash, start, end []byte) ([]mpt.KV, [][]byte, error) {
+	// Allow serving ranges for any validated root, not just the current one.
+	tr := mpt.NewTrie(mpt.NewHashNode(root), s.mode&^mpt.ModeGCFlag, storage.NewMemCachedStore(s.Store))
+	return tr.RangeProof(start, end)
+}
@@ -0,0 +1,149 @@
+package stateroot
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/core/state"
+	"github.com/multiverse-dev/saiya/pkg/core/transaction"
+	"github.com/multiverse-dev/saiya/pkg/crypto/bls"
+	"github.com/multiverse-dev/saiya/pkg/crypto/hash"
+	"github.com/multiverse-dev/saiya/pkg/crypto/keys"
+)
+
+// Tags under which a witness verifier can be registered with RegisterVerifier.
+const (
+	// TagECDSAMultisig is the scheme every witness used before scheme
+	// tagging existed: one ECDSA signature per validator over the
+	// committee's multisig redeem script.
+	TagECDSAMultisig = "ecdsa-multisig"
+	// TagBLSAgg identifies a witness carrying a single BLS signature
+	// aggregated over the whole committee.
+	TagBLSAgg = "bls12381-agg"
+)
+
+// schemeTags maps the leading byte of Witness.VerificationScript to the tag
+// its verifier is registered under.
+var schemeTags = map[byte]string{
+	0x00: TagECDSAMultisig,
+	0x01: TagBLSAgg,
+}
+
+// RegisterVerifier adds (or replaces) the witness verifier used for the
+// given scheme tag. It's safe for concurrent use, but is normally only
+// called during node startup, before any state root needs verifying.
+func (s *Module) RegisterVerifier(tag string, verif VerifierFunc) {
+	s.verifiersMtx.Lock()
+	defer s.verifiersMtx.Unlock()
+	s.verifiers[tag] = verif
+}
+
+// verifyWitness verifies state root witness, dispatching to the verifier
+// registered for the scheme selected by the leading byte of
+// Witness.VerificationScript.
+func (s *Module) verifyWitness(r *state.MPTRoot) error {
+	start := time.Now()
+	defer func() { stateRootVerifyDuration.Observe(time.Since(start).Seconds()) }()
+
+	scheme := r.Witness.VerificationScript[0]
+	tag, ok := schemeTags[scheme]
+	if !ok {
+		return fmt.Errorf("stateroot: unknown witness scheme byte %#x", scheme)
+	}
+
+	s.verifiersMtx.RLock()
+	verif, ok := s.verifiers[tag]
+	s.verifiersMtx.RUnlock()
+	if !ok {
+		return fmt.Errorf("stateroot: no verifier registered for scheme %q", tag)
+	}
+
+	s.mtx.Lock()
+	h := s.getKeyCacheForHeight(r.Index).validatorsHash
+	s.mtx.Unlock()
+	return verif(h, r, &r.Witness)
+}
+
+// getKeyCacheForHeight returns the validator key set effective at height:
+// the latest one registered at or before it. Must be called with s.mtx held.
+func (s *Module) getKeyCacheForHeight(height uint32) *keyCache {
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		if s.keys[i].height <= height {
+			return &s.keys[i]
+		}
+	}
+	return &keyCache{}
+}
+
+// UpdateValidators registers the validator set effective from height onwards,
+// along with its committee's BLS public keys for TagBLSAgg witnesses, and
+// notifies the update-validators callback if one is set.
+func (s *Module) UpdateValidators(height uint32, pubs keys.PublicKeys, blsPubs []*bls.PublicKey) error {
+	script, err := pubs.CreateDefaultMultiSigRedeemScript()
+	if err != nil {
+		return err
+	}
+	s.mtx.Lock()
+	s.keys = append(s.keys, keyCache{
+		height:            height,
+		validatorsKeys:    pubs,
+		validatorsHash:    hash.Hash160(script),
+		validatorsScript:  script,
+		validatorsBLSKeys: blsPubs,
+	})
+	cb := s.updateValidatorsCb
+	s.mtx.Unlock()
+	if cb != nil {
+		cb(height, pubs)
+	}
+	return nil
+}
+
+// aggregatedBLSKey returns the committee's aggregate BLS public key effective
+// at height, computing it once and caching it on the keyCache entry so that
+// verifying every block's witness doesn't re-aggregate the same curve points.
+func (s *Module) aggregatedBLSKey(height uint32) (*bls.PublicKey, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	kc := s.getKeyCacheForHeight(height)
+	if kc.aggregatedBLSKey != nil {
+		return kc.aggregatedBLSKey, nil
+	}
+	agg, err := bls.AggregatePublicKeys(kc.validatorsBLSKeys)
+	if err != nil {
+		return nil, err
+	}
+	kc.aggregatedBLSKey = agg
+	return agg, nil
+}
+
+// verifyBLSWitness is the VerifierFunc registered under TagBLSAgg: instead of
+// one ECDSA signature per validator, it checks a single signature aggregated
+// over the whole committee, so a witness stays constant-size as the
+// committee grows.
+func (s *Module) verifyBLSWitness(_ common.Address, item hash.Hashable, w *transaction.Witness) error {
+	r, ok := item.(*state.MPTRoot)
+	if !ok {
+		return fmt.Errorf("stateroot: bls12381-agg witness only supports *state.MPTRoot, got %T", item)
+	}
+	sig, err := bls.Unmarshal(w.InvocationScript)
+	if err != nil {
+		return fmt.Errorf("stateroot: invalid aggregate BLS signature: %w", err)
+	}
+	agg, err := s.aggregatedBLSKey(r.Index)
+	if err != nil {
+		return fmt.Errorf("stateroot: can't aggregate committee BLS keys: %w", err)
+	}
+	return bls.VerifyAggregateWithKey(sig, agg, blsSignedData(s.chainId, r.Root))
+}
+
+// blsSignedData binds chainId into the message so a witness signed for one
+// chain can't be replayed as valid on another sharing the same committee.
+func blsSignedData(chainId uint64, root common.Hash) []byte {
+	buf := make([]byte, 8+len(root))
+	binary.BigEndian.PutUint64(buf, chainId)
+	copy(buf[8:], root[:])
+	return buf
+}
@@ -0,0 +1,144 @@
+// Package statesync implements a snap-sync-style bootstrap for a fresh
+// node: instead of replaying every block from genesis, it downloads the
+// MPT state at a trusted, validator-signed state root directly, then hands
+// off to normal block processing from there.
+package statesync
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/core/mpt"
+	"github.com/multiverse-dev/saiya/pkg/core/state"
+	"github.com/multiverse-dev/saiya/pkg/core/stateroot"
+	"github.com/multiverse-dev/saiya/pkg/core/storage"
+	"github.com/multiverse-dev/saiya/pkg/network/payload"
+)
+
+// maxRangeBytes caps how much payload a single GetAccountRange request asks
+// a peer to return, mirroring the per-request budget go-ethereum's snap/1
+// protocol uses.
+const maxRangeBytes = 4 << 20
+
+// maxHealIterations bounds how many re-walk/re-fetch rounds the healing
+// phase will run before giving up, so a peer that keeps serving a moving
+// pivot can't stall a sync forever.
+const maxHealIterations = 16
+
+// Peer is the subset of networking a Syncer needs: requesting account
+// ranges and individual trie nodes from a remote state-sync server (backed,
+// on the other end, by stateroot.Module.ServeAccountRange/ServeTrieNodes).
+type Peer interface {
+	GetAccountRange(root common.Hash, start []byte, maxBytes uint64) (*payload.AccountRange, error)
+	GetTrieNodes(root common.Hash, hashes []common.Hash) (*payload.TrieNodes, error)
+}
+
+// Syncer downloads the MPT under a pivot state root from a Peer and applies
+// it to a stateroot.Module in place of replaying blocks.
+type Syncer struct {
+	peer  Peer
+	store *storage.MemCachedStore
+	mod   *stateroot.Module
+}
+
+// NewSyncer returns a Syncer that fetches trie data from peer, stages it in
+// store, and finalizes into mod once a pivot is fully synced and healed.
+func NewSyncer(peer Peer, store *storage.MemCachedStore, mod *stateroot.Module) *Syncer {
+	return &Syncer{peer: peer, store: store, mod: mod}
+}
+
+// Run downloads the full MPT under pivot one account range at a time,
+// heals any node left dangling because the pivot moved while downloading,
+// then jumps mod to pivot so the node can resume normal block processing.
+func (s *Syncer) Run(pivot *state.MPTRoot) error {
+	if err := s.downloadRanges(pivot); err != nil {
+		return fmt.Errorf("statesync: range download failed: %w", err)
+	}
+	if err := s.heal(pivot.Root); err != nil {
+		return fmt.Errorf("statesync: healing failed: %w", err)
+	}
+	s.mod.JumpToState(pivot)
+	return nil
+}
+
+// downloadRanges requests successive account ranges starting from the
+// empty key until the peer reports no more remain, verifying each response
+// against pivot.Root via mpt.VerifyRangeProof and inserting its leaves into
+// a freshly-built local trie, then checks that the trie it ends up with
+// hashes to the same root.
+func (s *Syncer) downloadRanges(pivot *state.MPTRoot) error {
+	tr := mpt.NewTrie(nil, mpt.ModeAll, s.store)
+	var start []byte
+	for {
+		resp, err := s.peer.GetAccountRange(pivot.Root, start, maxRangeBytes)
+		if err != nil {
+			return err
+		}
+
+		keys := make([][]byte, len(resp.Accounts))
+		values := make([][]byte, len(resp.Accounts))
+		for i, a := range resp.Accounts {
+			keys[i], values[i] = a.Key, a.Value
+		}
+		more, err := mpt.VerifyRangeProof(pivot.Root, start, nil, keys, values, resp.Proof)
+		if err != nil {
+			return fmt.Errorf("invalid account range proof starting at %x: %w", start, err)
+		}
+		for i := range keys {
+			if err := tr.Put(keys[i], values[i]); err != nil {
+				return err
+			}
+		}
+		if !more || len(keys) == 0 {
+			break
+		}
+		start = append(append([]byte{}, keys[len(keys)-1]...), 0)
+	}
+
+	tr.Flush(pivot.Index)
+	if tr.StateRoot() != pivot.Root {
+		return errors.New("reconstructed root does not match pivot")
+	}
+	return nil
+}
+
+// heal re-walks the trie rooted at root, looking for nodes it still can't
+// resolve locally (because the set of ranges downloaded settled on an
+// earlier or later version of the trie than root), and re-requests exactly
+// those via GetTrieNodes until none remain.
+func (s *Syncer) heal(root common.Hash) error {
+	tr := mpt.NewTrie(mpt.NewHashNode(root), mpt.ModeAll, s.store)
+	for i := 0; i < maxHealIterations; i++ {
+		missing := tr.CollectMissing()
+		if len(missing) == 0 {
+			return nil
+		}
+		if err := s.downloadNodes(root, missing); err != nil {
+			return err
+		}
+	}
+	return errors.New("did not converge")
+}
+
+// downloadNodes fetches hashes from the peer in batches of at most
+// payload.MaxTrieNodeHashes and stages the returned node data in store.
+func (s *Syncer) downloadNodes(root common.Hash, hashes []common.Hash) error {
+	for len(hashes) > 0 {
+		n := len(hashes)
+		if n > payload.MaxTrieNodeHashes {
+			n = payload.MaxTrieNodeHashes
+		}
+		batch := hashes[:n]
+		hashes = hashes[n:]
+
+		resp, err := s.peer.GetTrieNodes(root, batch)
+		if err != nil {
+			return err
+		}
+		for _, data := range resp.Nodes {
+			mpt.PutNode(s.store, data)
+		}
+	}
+	return nil
+}
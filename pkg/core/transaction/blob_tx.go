@@ -0,0 +1,389 @@
+package transaction
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/multiverse-dev/saiya/pkg/crypto/hash"
+	"github.com/multiverse-dev/saiya/pkg/io"
+	"golang.org/x/crypto/sha3"
+)
+
+// GasPerBlob is EIP-4844's fixed blob gas cost of a single blob
+// (2^17), used to price BlobTxType transactions alongside their ordinary
+// execution gas.
+const GasPerBlob = 1 << 17
+
+// BlobSidecar carries the large blob payload and the KZG commitments and
+// proofs that back BlobVersionedHashes. It is never part of the hash
+// preimage (only the versioned hashes are, mirroring EIP-4844) and the
+// pool keeps it out-of-band from the transaction index so it can be
+// pruned independently once the blobs are no longer needed for gossip.
+type BlobSidecar struct {
+	Blobs       [][]byte `json:"blobs"`
+	Commitments [][]byte `json:"commitments"`
+	Proofs      [][]byte `json:"proofs"`
+}
+
+// BlobTx is an EIP-4844-style transaction that ferries one or more blobs of
+// data alongside an ordinary call. Unlike SaiyaTx, it always carries a
+// ChainID and AccessList and never creates a contract (To is mandatory),
+// following the upstream spec it mirrors.
+type BlobTx struct {
+	ChainID   *big.Int
+	Nonce     uint64
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+	Gas       uint64
+	From      common.Address
+	To        common.Address
+	Value     *big.Int
+	Data      []byte
+	Witness   Witness
+
+	AccessList []AccessTuple
+
+	// BlobVersionedHashes commits to each blob in Sidecar, in the same
+	// order, via its KZG commitment's versioned hash. These, not Sidecar
+	// itself, are what the transaction hash covers.
+	BlobVersionedHashes []common.Hash
+	// MaxFeePerBlobGas caps what the sender is willing to pay per unit of
+	// blob gas, independent of GasFeeCap/GasTipCap which price ordinary
+	// execution gas.
+	MaxFeePerBlobGas *big.Int
+
+	// Sidecar holds the actual blob payload this transaction commits to.
+	// It's carried alongside the transaction for gossip and pool storage
+	// but is not a hashable field: BlobVersionedHashes already commits to
+	// it, and stripping it keeps the hashed/serialized envelope small.
+	Sidecar *BlobSidecar
+
+	len    int
+	hash   common.Hash
+	hashed bool
+
+	Trimmed bool
+}
+
+// EffectiveGasTip returns the per-gas tip this transaction actually pays a
+// block's proposer given baseFee: min(GasFeeCap-baseFee, GasTipCap).
+// baseFee may be nil, in which case the full GasFeeCap is returned.
+func (t *BlobTx) EffectiveGasTip(baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return new(big.Int).Set(t.GasFeeCap)
+	}
+	fromFeeCap := new(big.Int).Sub(t.GasFeeCap, baseFee)
+	if fromFeeCap.Cmp(t.GasTipCap) > 0 {
+		return new(big.Int).Set(t.GasTipCap)
+	}
+	return fromFeeCap
+}
+
+// EffectiveBlobFeeTip returns the per-unit tip this transaction actually
+// pays for blob gas given blobBaseFee: MaxFeePerBlobGas-blobBaseFee.
+// blobBaseFee may be nil, in which case the full MaxFeePerBlobGas is
+// returned.
+func (t *BlobTx) EffectiveBlobFeeTip(blobBaseFee *big.Int) *big.Int {
+	if blobBaseFee == nil {
+		return new(big.Int).Set(t.MaxFeePerBlobGas)
+	}
+	return new(big.Int).Sub(t.MaxFeePerBlobGas, blobBaseFee)
+}
+
+// NewBlobTxFromBytes decodes a BlobTx from its serialized form.
+func NewBlobTxFromBytes(b []byte) (*BlobTx, error) {
+	tx := &BlobTx{}
+	err := io.FromByteArray(tx, b)
+	if err != nil {
+		return nil, err
+	}
+	return tx, err
+}
+
+// Hash returns the hash of the transaction.
+func (t *BlobTx) Hash() common.Hash {
+	if !t.hashed {
+		if t.createHash() != nil {
+			panic("failed to compute hash!")
+		}
+	}
+	return t.hash
+}
+
+// decodeHashableFields decodes the fields that are used for signing the
+// transaction, which are all fields except the witness and the sidecar.
+func (t *BlobTx) decodeHashableFields(br *io.BinReader, buf []byte) {
+	var start, end int
+
+	if buf != nil {
+		start = len(buf) - br.Len()
+	}
+	chainbs := br.ReadVarBytes()
+	t.ChainID = big.NewInt(0).SetBytes(chainbs)
+	t.Nonce = br.ReadU64LE()
+	tipbs := br.ReadVarBytes()
+	t.GasTipCap = big.NewInt(0).SetBytes(tipbs)
+	capbs := br.ReadVarBytes()
+	t.GasFeeCap = big.NewInt(0).SetBytes(capbs)
+	t.Gas = br.ReadU64LE()
+	br.ReadBytes(t.From[:])
+	br.ReadBytes(t.To[:])
+	valuebs := br.ReadVarBytes()
+	t.Value = big.NewInt(0).SetBytes(valuebs)
+	t.Data = br.ReadVarBytes(MaxScriptLength)
+	decodeAccessList(br, &t.AccessList)
+	blobfeebs := br.ReadVarBytes()
+	t.MaxFeePerBlobGas = big.NewInt(0).SetBytes(blobfeebs)
+	decodeHashes(br, &t.BlobVersionedHashes)
+	if br.Err == nil {
+		br.Err = t.isValid()
+	}
+	if buf != nil {
+		end = len(buf) - br.Len()
+		t.hash = hash.Keccak256(buf[start:end])
+		t.hashed = true
+	}
+}
+
+func (t *BlobTx) decodeBinaryNoSize(br *io.BinReader, buf []byte) {
+	t.decodeHashableFields(br, buf)
+	if br.Err != nil {
+		return
+	}
+	t.Witness.DecodeBinary(br)
+
+	// Create the hash of the transaction at decode, so we dont need
+	// to do it anymore.
+	if br.Err == nil && buf == nil {
+		br.Err = t.createHash()
+	}
+}
+
+// DecodeBinary implements Serializable interface.
+func (t *BlobTx) DecodeBinary(br *io.BinReader) {
+	t.decodeBinaryNoSize(br, nil)
+
+	if br.Err == nil {
+		_ = t.Size()
+	}
+}
+
+// EncodeBinary implements Serializable interface.
+func (t *BlobTx) EncodeBinary(bw *io.BinWriter) {
+	t.encodeHashableFields(bw)
+	t.Witness.EncodeBinary(bw)
+}
+
+// encodeHashableFields encodes the fields that are used for signing the
+// transaction, which are all fields except the witness and the sidecar.
+func (t *BlobTx) encodeHashableFields(bw *io.BinWriter) {
+	if t.ChainID == nil {
+		bw.WriteVarUint(0)
+	} else {
+		bw.WriteVarBytes(t.ChainID.Bytes())
+	}
+	bw.WriteU64LE(t.Nonce)
+	if t.GasTipCap == nil {
+		bw.WriteVarUint(0)
+	} else {
+		bw.WriteVarBytes(t.GasTipCap.Bytes())
+	}
+	if t.GasFeeCap == nil {
+		bw.WriteVarUint(0)
+	} else {
+		bw.WriteVarBytes(t.GasFeeCap.Bytes())
+	}
+	bw.WriteU64LE(t.Gas)
+	bw.WriteBytes(t.From.Bytes())
+	bw.WriteBytes(t.To.Bytes())
+	if t.Value == nil {
+		bw.WriteVarUint(0)
+	} else {
+		bw.WriteVarBytes(t.Value.Bytes())
+	}
+	bw.WriteVarBytes(t.Data)
+	encodeAccessList(bw, t.AccessList)
+	if t.MaxFeePerBlobGas == nil {
+		bw.WriteVarUint(0)
+	} else {
+		bw.WriteVarBytes(t.MaxFeePerBlobGas.Bytes())
+	}
+	encodeHashes(bw, t.BlobVersionedHashes)
+}
+
+// EncodeHashableFields returns serialized transaction's fields which are hashed.
+func (t *BlobTx) EncodeHashableFields() ([]byte, error) {
+	bw := io.NewBufBinWriter()
+	t.encodeHashableFields(bw.BinWriter)
+	if bw.Err != nil {
+		return nil, bw.Err
+	}
+	return bw.Bytes(), nil
+}
+
+// createHash creates the hash of the transaction.
+func (t *BlobTx) createHash() error {
+	shaHash := sha3.NewLegacyKeccak256()
+	bw := io.NewBinWriterFromIO(shaHash)
+	t.encodeHashableFields(bw)
+	if bw.Err != nil {
+		return bw.Err
+	}
+
+	shaHash.Sum(t.hash[:0])
+	t.hashed = true
+	return nil
+}
+
+// Bytes converts the transaction to []byte. It never includes Sidecar:
+// callers that need to gossip or persist blobs do so separately, keyed by
+// this transaction's hash.
+func (t *BlobTx) Bytes() ([]byte, error) {
+	buf := io.NewBufBinWriter()
+	t.EncodeBinary(buf.BinWriter)
+	if buf.Err != nil {
+		return nil, buf.Err
+	}
+	return buf.Bytes(), nil
+}
+
+// Size returns size of the serialized transaction, not counting Sidecar.
+func (t *BlobTx) Size() int {
+	if t.len == 0 {
+		t.len = io.GetVarSize(t)
+	}
+	return t.len
+}
+
+// blobTxJson is a wrapper for BlobTx used for correct marshalling of
+// transaction.Data. Sidecar is included when set, unlike in the wire
+// encoding, which never carries it.
+type blobTxJson struct {
+	TxID                 common.Hash     `json:"hash"`
+	Size                 hexutil.Uint    `json:"size"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	From                 common.Address  `json:"from"`
+	To                   common.Address  `json:"to"`
+	Value                hexutil.Big     `json:"value"`
+	Data                 hexutil.Bytes   `json:"data"`
+	AccessList           []AccessTuple   `json:"accessList,omitempty"`
+	BlobVersionedHashes  []common.Hash   `json:"blobVersionedHashes"`
+	MaxFeePerBlobGas     *hexutil.Big    `json:"maxFeePerBlobGas"`
+	Sidecar              *BlobSidecar    `json:"sidecar,omitempty"`
+	Witness              Witness         `json:"witness"`
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (t *BlobTx) MarshalJSON() ([]byte, error) {
+	tx := blobTxJson{
+		TxID:                 t.Hash(),
+		Size:                 hexutil.Uint(t.Size()),
+		ChainID:              (*hexutil.Big)(t.ChainID),
+		Nonce:                hexutil.Uint64(t.Nonce),
+		MaxPriorityFeePerGas: (*hexutil.Big)(t.GasTipCap),
+		MaxFeePerGas:         (*hexutil.Big)(t.GasFeeCap),
+		Gas:                  hexutil.Uint64(t.Gas),
+		From:                 t.From,
+		To:                   t.To,
+		Value:                hexutil.Big(*t.Value),
+		Data:                 t.Data,
+		AccessList:           t.AccessList,
+		BlobVersionedHashes:  t.BlobVersionedHashes,
+		MaxFeePerBlobGas:     (*hexutil.Big)(t.MaxFeePerBlobGas),
+		Sidecar:              t.Sidecar,
+		Witness:              t.Witness,
+	}
+	return json.Marshal(tx)
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (t *BlobTx) UnmarshalJSON(data []byte) error {
+	tx := new(blobTxJson)
+	if err := json.Unmarshal(data, tx); err != nil {
+		return err
+	}
+	if tx.ChainID == nil {
+		return ErrMissingChainID
+	}
+	if tx.MaxPriorityFeePerGas == nil || tx.MaxFeePerGas == nil {
+		return ErrMissingFeeCap
+	}
+	if tx.MaxFeePerBlobGas == nil {
+		return ErrMissingBlobFeeCap
+	}
+	t.ChainID = (*big.Int)(tx.ChainID)
+	t.Nonce = uint64(tx.Nonce)
+	t.GasTipCap = (*big.Int)(tx.MaxPriorityFeePerGas)
+	t.GasFeeCap = (*big.Int)(tx.MaxFeePerGas)
+	t.Gas = uint64(tx.Gas)
+	t.From = tx.From
+	t.To = tx.To
+	t.Value = (*big.Int)(&tx.Value)
+	t.Data = tx.Data
+	t.AccessList = tx.AccessList
+	t.BlobVersionedHashes = tx.BlobVersionedHashes
+	t.MaxFeePerBlobGas = (*big.Int)(tx.MaxFeePerBlobGas)
+	t.Sidecar = tx.Sidecar
+	t.Witness = tx.Witness
+	return t.isValid()
+}
+
+// Various errors for blob transaction validation.
+var (
+	ErrMissingBlobFeeCap   = errors.New("missing maxFeePerBlobGas for blob transaction")
+	ErrMissingBlobHashes   = errors.New("blob transaction must declare at least one blob versioned hash")
+	ErrMissingSidecar      = errors.New("blob transaction sidecar is missing a commitment for one of its versioned hashes")
+	ErrSidecarHashMismatch = errors.New("sidecar does not match blob versioned hashes")
+)
+
+// isValid checks whether decoded/unmarshalled transaction has all fields valid.
+func (t *BlobTx) isValid() error {
+	if t.Value.Sign() < 0 {
+		return ErrNegativeValue
+	}
+	if t.Value.Cmp(maxUint256) > 0 {
+		return ErrValueVeryHigh
+	}
+	if err := validateFeeCap(t.GasTipCap, t.GasFeeCap); err != nil {
+		return err
+	}
+	if len(t.BlobVersionedHashes) == 0 {
+		return ErrMissingBlobHashes
+	}
+	if t.Sidecar != nil {
+		return t.verifySidecar()
+	}
+	return nil
+}
+
+// verifySidecar checks, per EIP-4844, that Sidecar carries exactly one
+// commitment for every entry of BlobVersionedHashes and, in order, that
+// each commitment's versioned hash (the KZG version byte followed by the
+// trailing 31 bytes of its SHA-256 digest) matches. It does not check a
+// blob against its own commitment/proof, which needs the full KZG trusted
+// setup; no code in this repository makes that check.
+func (t *BlobTx) verifySidecar() error {
+	if len(t.Sidecar.Commitments) != len(t.BlobVersionedHashes) {
+		return ErrMissingSidecar
+	}
+	for i, c := range t.Sidecar.Commitments {
+		var commitment kzg4844.Commitment
+		if len(c) != len(commitment) {
+			return ErrSidecarHashMismatch
+		}
+		copy(commitment[:], c)
+		if kzg4844.CalcBlobHashV1(sha256.New(), &commitment) != t.BlobVersionedHashes[i] {
+			return ErrSidecarHashMismatch
+		}
+	}
+	return nil
+}
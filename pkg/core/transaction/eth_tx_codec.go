@@ -0,0 +1,33 @@
+package transaction
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/multiverse-dev/saiya/pkg/io"
+)
+
+// EncodeBinary implements rlpstruct.Codec. The inner go-ethereum
+// transaction is RLP-encoded and written length-prefixed, so DecodeBinary
+// never has to guess where the RLP body ends the way the old hand-rolled
+// test (reading a hardcoded trailing byte) did.
+func (t *EthTx) EncodeBinary(bw *io.BinWriter) {
+	b, err := rlp.EncodeToBytes(&t.Transaction)
+	if err != nil {
+		bw.Err = err
+		return
+	}
+	bw.WriteVarBytes(b)
+	bw.WriteBytes(t.Sender.Bytes())
+}
+
+// DecodeBinary is the inverse of EncodeBinary.
+func (t *EthTx) DecodeBinary(br *io.BinReader) {
+	b := br.ReadVarBytes()
+	if br.Err != nil {
+		return
+	}
+	if err := rlp.DecodeBytes(b, &t.Transaction); err != nil {
+		br.Err = err
+		return
+	}
+	br.ReadBytes(t.Sender[:])
+}
@@ -1,9 +1,12 @@
 package transaction
 
 import (
+	"math/big"
 	"math/rand"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/io"
 	"github.com/stretchr/testify/require"
 )
 
@@ -20,3 +23,66 @@ func FuzzReader(f *testing.F) {
 		})
 	})
 }
+
+// FuzzSaiyaTxRoundTrip asserts Decode(Encode(x)) == x for SaiyaTx: the
+// bytes produced by re-encoding a freshly decoded transaction must match
+// what was encoded in the first place, and Size() must agree with the
+// actual encoded length on both sides of the round trip.
+func FuzzSaiyaTxRoundTrip(f *testing.F) {
+	f.Add(uint64(0), uint64(0), uint64(1), []byte{})
+	f.Add(uint64(1), uint64(21000), uint64(1_000_000_000), []byte{1, 2, 3})
+	f.Add(uint64(1<<32), uint64(1<<20), uint64(1<<30), make([]byte, 64))
+
+	f.Fuzz(func(t *testing.T, nonce, gas, gasPrice uint64, data []byte) {
+		if len(data) > MaxScriptLength {
+			data = data[:MaxScriptLength]
+		}
+		tx := &SaiyaTx{
+			Nonce:    nonce,
+			GasPrice: new(big.Int).SetUint64(gasPrice),
+			Gas:      gas,
+			Value:    big.NewInt(0),
+			Data:     data,
+		}
+		want, err := tx.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, len(want), tx.Size())
+
+		got := &SaiyaTx{}
+		require.NoError(t, io.FromByteArray(got, want))
+		gotBytes, err := got.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, want, gotBytes)
+		require.Equal(t, len(want), got.Size())
+	})
+}
+
+// FuzzBlobTxRoundTrip is FuzzSaiyaTxRoundTrip's counterpart for BlobTx.
+func FuzzBlobTxRoundTrip(f *testing.F) {
+	f.Add(uint64(0), uint64(1))
+	f.Add(uint64(7), uint64(1_000_000_000))
+
+	f.Fuzz(func(t *testing.T, nonce, gasFeeCap uint64) {
+		tx := &BlobTx{
+			ChainID:             big.NewInt(1),
+			Nonce:               nonce,
+			GasTipCap:           big.NewInt(0),
+			GasFeeCap:           new(big.Int).SetUint64(gasFeeCap),
+			Gas:                 21000,
+			To:                  common.Address{1},
+			Value:               big.NewInt(0),
+			MaxFeePerBlobGas:    big.NewInt(1),
+			BlobVersionedHashes: []common.Hash{{1}},
+		}
+		want, err := tx.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, len(want), tx.Size())
+
+		got := &BlobTx{}
+		require.NoError(t, io.FromByteArray(got, want))
+		gotBytes, err := got.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, want, gotBytes)
+		require.Equal(t, len(want), got.Size())
+	})
+}
@@ -18,8 +18,57 @@ var (
 	ErrNoSender = errors.New("no sender in trimmed tx")
 )
 
+// SaiyaTxType selects which of SaiyaTx's field sets (legacy, access-list, or
+// dynamic-fee) was used to build and sign it, following the EIP-2718 typed
+// envelope convention: the byte is written first and included in the hash
+// preimage, so the two fee models can never be confused for one another.
+type SaiyaTxType byte
+
+const (
+	// LegacySaiyaTxType is a single-gas-price transaction, saiya's
+	// original (and still default) format.
+	LegacySaiyaTxType SaiyaTxType = iota
+	// AccessListSaiyaTxType adds an EIP-2930 access list to the legacy
+	// single-gas-price format.
+	AccessListSaiyaTxType
+	// DynamicFeeSaiyaTxType is an EIP-1559 style transaction with a base
+	// fee tip and cap instead of a single gas price.
+	DynamicFeeSaiyaTxType
+)
+
+// AccessTuple is a single entry of an EIP-2930 access list: an address and
+// the set of its storage slots the transaction declares it will touch.
+type AccessTuple struct {
+	Address     common.Address `json:"address"`
+	StorageKeys []common.Hash  `json:"storageKeys"`
+}
+
+// ToEthAccessList converts a SaiyaTx-native access list to go-ethereum's
+// core/types.AccessList, letting Transaction.AccessList (and callers that
+// need intrinsic gas against a SaiyaTx before it's wrapped) return a
+// single type regardless of whether the underlying tx is an EthTx or a
+// SaiyaTx.
+func ToEthAccessList(l []AccessTuple) types.AccessList {
+	if l == nil {
+		return nil
+	}
+	out := make(types.AccessList, len(l))
+	for i, t := range l {
+		out[i] = types.AccessTuple{
+			Address:     t.Address,
+			StorageKeys: t.StorageKeys,
+		}
+	}
+	return out
+}
+
 type SaiyaTx struct {
-	Nonce    uint64
+	Type SaiyaTxType
+
+	Nonce uint64
+	// GasPrice is only meaningful for LegacySaiyaTxType and
+	// AccessListSaiyaTxType; DynamicFeeSaiyaTxType uses
+	// MaxPriorityFeePerGas/MaxFeePerGas instead.
 	GasPrice *big.Int
 	Gas      uint64
 	From     common.Address
@@ -28,6 +77,29 @@ type SaiyaTx struct {
 	Data     []byte
 	Witness  Witness
 
+	// ChainID is set for AccessListSaiyaTxType and DynamicFeeSaiyaTxType,
+	// replay-protecting them the way EIP-155 does for legacy transactions
+	// via the witness chain ID instead.
+	ChainID *big.Int
+	// AccessList is set for AccessListSaiyaTxType and DynamicFeeSaiyaTxType.
+	AccessList []AccessTuple
+	// MaxPriorityFeePerGas and MaxFeePerGas are only set for
+	// DynamicFeeSaiyaTxType.
+	MaxPriorityFeePerGas *big.Int
+	MaxFeePerGas         *big.Int
+
+	// Conflicts lists hashes of other transactions the sender refuses to
+	// coexist with: when a named transaction is pooled and shares the
+	// sender, the mempool evicts it if this one pays a strictly higher
+	// effective tip and rejects this one otherwise, so a later, higher-fee
+	// resubmission can push out a stuck earlier one. Block validation does
+	// not look at this field.
+	Conflicts []common.Hash
+	// NotValidBefore is the block height before which the transaction must
+	// not be admitted to the mempool or a block. It's 0, the default,
+	// for transactions with no such restriction.
+	NotValidBefore uint32
+
 	len    int
 	hash   common.Hash
 	hashed bool
@@ -35,6 +107,28 @@ type SaiyaTx struct {
 	Trimmed bool
 }
 
+// EffectiveGasTip returns the per-gas tip this transaction actually pays a
+// block's proposer given baseFee: min(MaxFeePerGas-baseFee,
+// MaxPriorityFeePerGas) for DynamicFeeSaiyaTxType, or simply
+// GasPrice-baseFee otherwise. baseFee may be
+// nil, in which case the full GasPrice/MaxFeePerGas is returned.
+func (t *SaiyaTx) EffectiveGasTip(baseFee *big.Int) *big.Int {
+	if t.Type != DynamicFeeSaiyaTxType {
+		if baseFee == nil {
+			return new(big.Int).Set(t.GasPrice)
+		}
+		return new(big.Int).Sub(t.GasPrice, baseFee)
+	}
+	if baseFee == nil {
+		return new(big.Int).Set(t.MaxFeePerGas)
+	}
+	fromFeeCap := new(big.Int).Sub(t.MaxFeePerGas, baseFee)
+	if fromFeeCap.Cmp(t.MaxPriorityFeePerGas) > 0 {
+		return new(big.Int).Set(t.MaxPriorityFeePerGas)
+	}
+	return fromFeeCap
+}
+
 // NewTrimmedTX returns a trimmed transaction with only its hash
 // and Trimmed to true.
 
@@ -74,9 +168,22 @@ func (t *SaiyaTx) decodeHashableFields(br *io.BinReader, buf []byte) {
 	if buf != nil {
 		start = len(buf) - br.Len()
 	}
+	t.Type = SaiyaTxType(br.ReadB())
+	if t.Type != LegacySaiyaTxType {
+		chainbs := br.ReadVarBytes()
+		t.ChainID = big.NewInt(0).SetBytes(chainbs)
+	}
 	t.Nonce = br.ReadU64LE()
-	pricebs := br.ReadVarBytes()
-	t.GasPrice = big.NewInt(0).SetBytes(pricebs)
+	switch t.Type {
+	case DynamicFeeSaiyaTxType:
+		tipbs := br.ReadVarBytes()
+		t.MaxPriorityFeePerGas = big.NewInt(0).SetBytes(tipbs)
+		capbs := br.ReadVarBytes()
+		t.MaxFeePerGas = big.NewInt(0).SetBytes(capbs)
+	default:
+		pricebs := br.ReadVarBytes()
+		t.GasPrice = big.NewInt(0).SetBytes(pricebs)
+	}
 	t.Gas = br.ReadU64LE()
 	br.ReadBytes(t.From[:])
 	tob := br.ReadVarBytes(common.AddressLength)
@@ -89,6 +196,11 @@ func (t *SaiyaTx) decodeHashableFields(br *io.BinReader, buf []byte) {
 	valuebs := br.ReadVarBytes()
 	t.Value = big.NewInt(0).SetBytes(valuebs)
 	t.Data = br.ReadVarBytes(MaxScriptLength)
+	decodeHashes(br, &t.Conflicts)
+	t.NotValidBefore = br.ReadU32LE()
+	if t.Type != LegacySaiyaTxType {
+		decodeAccessList(br, &t.AccessList)
+	}
 	if br.Err == nil {
 		br.Err = t.isValid()
 	}
@@ -99,6 +211,47 @@ func (t *SaiyaTx) decodeHashableFields(br *io.BinReader, buf []byte) {
 	}
 }
 
+func decodeHashes(br *io.BinReader, list *[]common.Hash) {
+	n := br.ReadVarUint()
+	*list = make([]common.Hash, n)
+	for i := range *list {
+		br.ReadBytes((*list)[i][:])
+	}
+}
+
+func encodeHashes(bw *io.BinWriter, list []common.Hash) {
+	bw.WriteVarUint(uint64(len(list)))
+	for _, h := range list {
+		bw.WriteBytes(h.Bytes())
+	}
+}
+
+func decodeAccessList(br *io.BinReader, list *[]AccessTuple) {
+	n := br.ReadVarUint()
+	*list = make([]AccessTuple, n)
+	for i := range *list {
+		var addr common.Address
+		br.ReadBytes(addr[:])
+		m := br.ReadVarUint()
+		keys := make([]common.Hash, m)
+		for j := range keys {
+			br.ReadBytes(keys[j][:])
+		}
+		(*list)[i] = AccessTuple{Address: addr, StorageKeys: keys}
+	}
+}
+
+func encodeAccessList(bw *io.BinWriter, list []AccessTuple) {
+	bw.WriteVarUint(uint64(len(list)))
+	for _, a := range list {
+		bw.WriteBytes(a.Address.Bytes())
+		bw.WriteVarUint(uint64(len(a.StorageKeys)))
+		for _, k := range a.StorageKeys {
+			bw.WriteBytes(k.Bytes())
+		}
+	}
+}
+
 func (t *SaiyaTx) decodeBinaryNoSize(br *io.BinReader, buf []byte) {
 	t.decodeHashableFields(br, buf)
 	if br.Err != nil {
@@ -131,11 +284,33 @@ func (t *SaiyaTx) EncodeBinary(bw *io.BinWriter) {
 // encodeHashableFields encodes the fields that are not used for
 // signing the transaction, which are all fields except the scripts.
 func (t *SaiyaTx) encodeHashableFields(bw *io.BinWriter) {
+	bw.WriteB(byte(t.Type))
+	if t.Type != LegacySaiyaTxType {
+		if t.ChainID == nil {
+			bw.WriteVarUint(0)
+		} else {
+			bw.WriteVarBytes(t.ChainID.Bytes())
+		}
+	}
 	bw.WriteU64LE(t.Nonce)
-	if t.GasPrice == nil {
-		bw.WriteVarUint(0)
-	} else {
-		bw.WriteVarBytes(t.GasPrice.Bytes())
+	switch t.Type {
+	case DynamicFeeSaiyaTxType:
+		if t.MaxPriorityFeePerGas == nil {
+			bw.WriteVarUint(0)
+		} else {
+			bw.WriteVarBytes(t.MaxPriorityFeePerGas.Bytes())
+		}
+		if t.MaxFeePerGas == nil {
+			bw.WriteVarUint(0)
+		} else {
+			bw.WriteVarBytes(t.MaxFeePerGas.Bytes())
+		}
+	default:
+		if t.GasPrice == nil {
+			bw.WriteVarUint(0)
+		} else {
+			bw.WriteVarBytes(t.GasPrice.Bytes())
+		}
 	}
 	bw.WriteU64LE(t.Gas)
 	bw.WriteBytes(t.From.Bytes())
@@ -150,6 +325,11 @@ func (t *SaiyaTx) encodeHashableFields(bw *io.BinWriter) {
 		bw.WriteVarBytes(t.Value.Bytes())
 	}
 	bw.WriteVarBytes(t.Data)
+	encodeHashes(bw, t.Conflicts)
+	bw.WriteU32LE(t.NotValidBefore)
+	if t.Type != LegacySaiyaTxType {
+		encodeAccessList(bw, t.AccessList)
+	}
 }
 
 // EncodeHashableFields returns serialized transaction's fields which are hashed.
@@ -211,31 +391,53 @@ func (t *SaiyaTx) Size() int {
 // transactionJSON is a wrapper for SaiyaTx and
 // used for correct marhalling of transaction.Data.
 type saiyaTxJson struct {
-	TxID     common.Hash     `json:"hash"`
-	Size     hexutil.Uint    `json:"size"`
-	Nonce    hexutil.Uint64  `json:"nonce"`
-	GasPrice hexutil.Big     `json:"gasPrice"`
-	Gas      hexutil.Uint64  `json:"gas"`
-	From     common.Address  `json:"from"`
-	To       *common.Address `json:"to,omitempty"`
-	Value    hexutil.Big     `json:"value"`
-	Data     hexutil.Bytes   `json:"data"`
-	Witness  Witness         `json:"witness"`
+	Type                 hexutil.Uint64  `json:"type"`
+	TxID                 common.Hash     `json:"hash"`
+	Size                 hexutil.Uint    `json:"size"`
+	ChainID              *hexutil.Big    `json:"chainId,omitempty"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Value                hexutil.Big     `json:"value"`
+	Data                 hexutil.Bytes   `json:"data"`
+	AccessList           []AccessTuple   `json:"accessList,omitempty"`
+	Conflicts            []common.Hash   `json:"conflicts,omitempty"`
+	NotValidBefore       hexutil.Uint64  `json:"notValidBefore,omitempty"`
+	Witness              Witness         `json:"witness"`
 }
 
 // MarshalJSON implements json.Marshaler interface.
 func (t *SaiyaTx) MarshalJSON() ([]byte, error) {
 	tx := saiyaTxJson{
-		TxID:     t.Hash(),
-		Size:     hexutil.Uint(t.Size()),
-		Nonce:    hexutil.Uint64(t.Nonce),
-		GasPrice: hexutil.Big(*t.GasPrice),
-		Gas:      hexutil.Uint64(t.Gas),
-		From:     t.From,
-		To:       t.To,
-		Value:    hexutil.Big(*t.Value),
-		Data:     t.Data,
-		Witness:  t.Witness,
+		Type:           hexutil.Uint64(t.Type),
+		TxID:           t.Hash(),
+		Size:           hexutil.Uint(t.Size()),
+		Gas:            hexutil.Uint64(t.Gas),
+		From:           t.From,
+		To:             t.To,
+		Value:          hexutil.Big(*t.Value),
+		Data:           t.Data,
+		AccessList:     t.AccessList,
+		Conflicts:      t.Conflicts,
+		NotValidBefore: hexutil.Uint64(t.NotValidBefore),
+		Witness:        t.Witness,
+	}
+	if t.Type != LegacySaiyaTxType {
+		chainID := hexutil.Big(*t.ChainID)
+		tx.ChainID = &chainID
+	}
+	if t.Type == DynamicFeeSaiyaTxType {
+		tip := hexutil.Big(*t.MaxPriorityFeePerGas)
+		cap := hexutil.Big(*t.MaxFeePerGas)
+		tx.MaxPriorityFeePerGas = &tip
+		tx.MaxFeePerGas = &cap
+	} else {
+		price := hexutil.Big(*t.GasPrice)
+		tx.GasPrice = &price
 	}
 	return json.Marshal(tx)
 }
@@ -246,21 +448,45 @@ func (t *SaiyaTx) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, tx); err != nil {
 		return err
 	}
+	t.Type = SaiyaTxType(tx.Type)
 	t.Nonce = uint64(tx.Nonce)
-	t.GasPrice = (*big.Int)(&tx.GasPrice)
 	t.Gas = uint64(tx.Gas)
 	t.From = tx.From
 	t.To = tx.To
 	t.Value = (*big.Int)(&tx.Value)
 	t.Data = tx.Data
+	t.AccessList = tx.AccessList
+	t.Conflicts = tx.Conflicts
+	t.NotValidBefore = uint32(tx.NotValidBefore)
 	t.Witness = tx.Witness
+	if t.Type != LegacySaiyaTxType {
+		if tx.ChainID == nil {
+			return ErrMissingChainID
+		}
+		t.ChainID = (*big.Int)(tx.ChainID)
+	}
+	if t.Type == DynamicFeeSaiyaTxType {
+		if tx.MaxPriorityFeePerGas == nil || tx.MaxFeePerGas == nil {
+			return ErrMissingFeeCap
+		}
+		t.MaxPriorityFeePerGas = (*big.Int)(tx.MaxPriorityFeePerGas)
+		t.MaxFeePerGas = (*big.Int)(tx.MaxFeePerGas)
+	} else {
+		if tx.GasPrice == nil {
+			return ErrMissingGasPrice
+		}
+		t.GasPrice = (*big.Int)(tx.GasPrice)
+	}
 	return t.isValid()
 }
 
 // Various errors for transaction validation.
 var (
-	ErrNegativeValue  = errors.New("negative value")
-	ErrWitnessUnmatch = errors.New("witness not match from")
+	ErrNegativeValue   = errors.New("negative value")
+	ErrWitnessUnmatch  = errors.New("witness not match from")
+	ErrMissingChainID  = errors.New("missing chainId for typed transaction")
+	ErrMissingFeeCap   = errors.New("missing maxPriorityFeePerGas/maxFeePerGas for dynamic-fee transaction")
+	ErrMissingGasPrice = errors.New("missing gasPrice")
 )
 
 // isValid checks whether decoded/unmarshalled transaction has all fields valid.
@@ -268,5 +494,14 @@ func (t *SaiyaTx) isValid() error {
 	if t.Value.Sign() < 0 {
 		return ErrNegativeValue
 	}
+	if t.Value.Cmp(maxUint256) > 0 {
+		return ErrValueVeryHigh
+	}
+	if t.Type == DynamicFeeSaiyaTxType {
+		return validateFeeCap(t.MaxPriorityFeePerGas, t.MaxFeePerGas)
+	}
+	if t.GasPrice.Cmp(maxUint256) > 0 {
+		return ErrGasPriceVeryHigh
+	}
 	return nil
 }
@@ -11,11 +11,13 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/multiverse-dev/saiya/pkg/crypto/hash"
 	"github.com/multiverse-dev/saiya/pkg/io"
+	"github.com/multiverse-dev/saiya/pkg/io/rlpstruct"
 )
 
 const (
 	EthTxType           = byte(0)
 	SaiTxType           = byte(1)
+	BlobTxType          = byte(2)
 	SignatureLength     = 64
 	MaxScriptLength     = math.MaxUint16
 	MaxTransactionSize  = 102400
@@ -27,9 +29,10 @@ var (
 )
 
 type Transaction struct {
-	Type  byte
-	EthTx *EthTx
-	SaiTx *SaiTx
+	Type   byte
+	EthTx  *EthTx
+	SaiTx  *SaiTx
+	BlobTx *BlobTx
 
 	Trimmed bool
 	hash    atomic.Value
@@ -53,6 +56,9 @@ func NewTx(t interface{}) *Transaction {
 	case *EthTx:
 		tx.Type = EthTxType
 		tx.EthTx = v
+	case *BlobTx:
+		tx.Type = BlobTxType
+		tx.BlobTx = v
 	default:
 		panic("unsupport tx")
 	}
@@ -74,6 +80,8 @@ func (t *Transaction) Nonce() uint64 {
 		return t.EthTx.Nonce()
 	case SaiTxType:
 		return t.SaiTx.Nonce
+	case BlobTxType:
+		return t.BlobTx.Nonce
 	default:
 		panic(ErrUnsupportType)
 	}
@@ -85,6 +93,8 @@ func (t *Transaction) To() *common.Address {
 		return t.EthTx.To()
 	case SaiTxType:
 		return t.SaiTx.To
+	case BlobTxType:
+		return &t.BlobTx.To
 	default:
 		panic(ErrUnsupportType)
 	}
@@ -96,6 +106,8 @@ func (t *Transaction) Gas() uint64 {
 		return t.EthTx.Gas()
 	case SaiTxType:
 		return t.SaiTx.Gas
+	case BlobTxType:
+		return t.BlobTx.Gas
 	default:
 		panic(ErrUnsupportType)
 	}
@@ -107,14 +119,96 @@ func (t *Transaction) GasPrice() *big.Int {
 		return t.EthTx.GasPrice()
 	case SaiTxType:
 		return t.SaiTx.GasPrice
+	case BlobTxType:
+		return t.BlobTx.GasFeeCap
 	default:
 		panic(ErrUnsupportType)
 	}
 }
 
+// GasFeeCap returns the maximum fee per gas this transaction is willing to
+// pay, as opposed to EffectiveGasPrice/EffectiveGasTip which discount for
+// a base fee: MaxFeePerGas for a DynamicFeeSaiyaTxType SaiTx, GasFeeCap for a
+// BlobTx, or simply GasPrice otherwise.
+func (t *Transaction) GasFeeCap() *big.Int {
+	switch t.Type {
+	case SaiTxType:
+		if t.SaiTx.Type == DynamicFeeSaiyaTxType {
+			return t.SaiTx.MaxFeePerGas
+		}
+		return t.SaiTx.GasPrice
+	case BlobTxType:
+		return t.BlobTx.GasFeeCap
+	default:
+		return t.GasPrice()
+	}
+}
+
+// GasTipCap returns the per-gas tip this transaction offers a block
+// proposer before any base-fee discount: MaxPriorityFeePerGas for a
+// DynamicFeeSaiyaTxType SaiTx, GasTipCap for a BlobTx, or simply GasPrice
+// otherwise.
+func (t *Transaction) GasTipCap() *big.Int {
+	switch t.Type {
+	case SaiTxType:
+		if t.SaiTx.Type == DynamicFeeSaiyaTxType {
+			return t.SaiTx.MaxPriorityFeePerGas
+		}
+		return t.SaiTx.GasPrice
+	case BlobTxType:
+		return t.BlobTx.GasTipCap
+	default:
+		return t.GasPrice()
+	}
+}
+
+// EffectiveGasPrice returns the per-gas price this transaction actually
+// pays given baseFee: min(GasFeeCap(), GasTipCap()+baseFee). baseFee may
+// be nil, in which case GasPrice() is returned unchanged.
+func (t *Transaction) EffectiveGasPrice(baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return t.GasPrice()
+	}
+	price := new(big.Int).Add(t.GasTipCap(), baseFee)
+	if feeCap := t.GasFeeCap(); price.Cmp(feeCap) > 0 {
+		return feeCap
+	}
+	return price
+}
+
 func (t Transaction) Cost() *big.Int {
 	cost := big.NewInt(0).Mul(big.NewInt(int64(t.Gas())), t.GasPrice())
-	return big.NewInt(0).Add(t.Value(), cost)
+	cost.Add(cost, t.Value())
+	if t.Type == BlobTxType {
+		blobCost := big.NewInt(0).Mul(big.NewInt(int64(t.BlobGas())), t.BlobGasFeeCap())
+		cost.Add(cost, blobCost)
+	}
+	return cost
+}
+
+// EffectiveGasTip returns the per-gas tip this transaction actually pays a
+// block's proposer given baseFee (nil meaning no base fee, e.g. before
+// EIP-1559 activation): min(maxFee-baseFee, maxPriorityFee) for an
+// EIP-1559-style EthTx (0 if maxFee is below baseFee), a
+// DynamicFeeSaiyaTxType SaiTx or a BlobTx, or GasPrice-baseFee for the
+// other SaiTx types.
+func (t *Transaction) EffectiveGasTip(baseFee *big.Int) *big.Int {
+	switch t.Type {
+	case EthTxType:
+		tip, err := t.EthTx.EffectiveGasTip(baseFee)
+		if err != nil {
+			// GasFeeCap < baseFee: the transaction isn't payable at all,
+			// callers comparing priority should treat it as worthless.
+			return big.NewInt(0)
+		}
+		return tip
+	case SaiTxType:
+		return t.SaiTx.EffectiveGasTip(baseFee)
+	case BlobTxType:
+		return t.BlobTx.EffectiveGasTip(baseFee)
+	default:
+		panic(ErrUnsupportType)
+	}
 }
 
 func (t *Transaction) Value() *big.Int {
@@ -123,6 +217,8 @@ func (t *Transaction) Value() *big.Int {
 		return t.EthTx.Value()
 	case SaiTxType:
 		return t.SaiTx.Value
+	case BlobTxType:
+		return t.BlobTx.Value
 	default:
 		panic(ErrUnsupportType)
 	}
@@ -134,11 +230,34 @@ func (t *Transaction) Data() []byte {
 		return t.EthTx.Data()
 	case SaiTxType:
 		return t.SaiTx.Data
+	case BlobTxType:
+		return t.BlobTx.Data
 	default:
 		panic(ErrUnsupportType)
 	}
 }
 
+// Conflicts returns the hashes of other transactions this one refuses to
+// coexist with, or nil if it doesn't declare any (EthTxType transactions
+// never do, as the attribute is saiya-specific).
+func (t *Transaction) Conflicts() []common.Hash {
+	if t.Type == SaiTxType {
+		return t.SaiTx.Conflicts
+	}
+	return nil
+}
+
+// NotValidBefore returns the block height before which this transaction
+// must not be admitted to the mempool or a block, or 0 if it doesn't
+// declare one (EthTxType transactions never do, as the attribute is
+// saiya-specific).
+func (t *Transaction) NotValidBefore() uint32 {
+	if t.Type == SaiTxType {
+		return t.SaiTx.NotValidBefore
+	}
+	return 0
+}
+
 func (t *Transaction) Size() int {
 	if size := t.size.Load(); size != nil {
 		return size.(int)
@@ -149,6 +268,8 @@ func (t *Transaction) Size() int {
 		size = int(t.EthTx.Size())
 	case SaiTxType:
 		size = t.SaiTx.Size()
+	case BlobTxType:
+		size = t.BlobTx.Size()
 	default:
 		panic(ErrUnsupportType)
 	}
@@ -162,6 +283,8 @@ func (t *Transaction) From() common.Address {
 		return t.EthTx.Sender
 	case SaiTxType:
 		return t.SaiTx.From
+	case BlobTxType:
+		return t.BlobTx.From
 	default:
 		panic(ErrUnsupportType)
 	}
@@ -171,19 +294,69 @@ func (t *Transaction) AccessList() types.AccessList {
 	switch t.Type {
 	case EthTxType:
 		return t.EthTx.AccessList()
+	case SaiTxType:
+		return ToEthAccessList(t.SaiTx.AccessList)
 	default:
 		return nil
 	}
 }
 
+// BlobVersionedHashes returns the hashes of the blobs this transaction
+// commits to, or nil if it's not a BlobTxType transaction.
+func (t *Transaction) BlobVersionedHashes() []common.Hash {
+	if t.Type == BlobTxType {
+		return t.BlobTx.BlobVersionedHashes
+	}
+	return nil
+}
+
+// BlobHashes is an alias for BlobVersionedHashes, matching the name used by
+// go-ethereum's own EIP-4844 tx pool.
+func (t *Transaction) BlobHashes() []common.Hash {
+	return t.BlobVersionedHashes()
+}
+
+// BlobGas returns the blob gas this transaction consumes (GasPerBlob for
+// every blob it commits to), or 0 if it's not a BlobTxType transaction.
+func (t *Transaction) BlobGas() uint64 {
+	if t.Type != BlobTxType {
+		return 0
+	}
+	return uint64(len(t.BlobTx.BlobVersionedHashes)) * GasPerBlob
+}
+
+// BlobGasFeeCap returns the maximum fee per unit of blob gas this
+// transaction is willing to pay, or nil if it's not a BlobTxType
+// transaction.
+func (t *Transaction) BlobGasFeeCap() *big.Int {
+	if t.Type != BlobTxType {
+		return nil
+	}
+	return t.BlobTx.MaxFeePerBlobGas
+}
+
+// Sidecar returns the blob payload and KZG commitments/proofs this
+// transaction's BlobVersionedHashes commit to, or nil if it's not a
+// BlobTxType transaction or was received without one (e.g. over the wire,
+// where the sidecar is always stripped).
+func (t *Transaction) Sidecar() *BlobSidecar {
+	if t.Type != BlobTxType {
+		return nil
+	}
+	return t.BlobTx.Sidecar
+}
+
 func (t *Transaction) Hash() common.Hash {
 	if hash := t.hash.Load(); hash != nil {
 		return hash.(common.Hash)
 	}
 	var h common.Hash
-	if t.Type == EthTxType {
+	switch t.Type {
+	case EthTxType:
 		h = hash.RlpHash(t.EthTx)
-	} else {
+	case BlobTxType:
+		h = t.BlobTx.Hash()
+	default:
 		h = t.SaiTx.Hash()
 	}
 	t.hash.Store(h)
@@ -207,31 +380,47 @@ func (t Transaction) FeePerByte() uint64 {
 	return t.Gas() / uint64(t.Size())
 }
 
+// txVariants lists the typed payloads a Transaction's leading type byte can
+// select, letting EncodeBinary/DecodeBinary share their discriminator
+// handling with every other typed envelope via rlpstruct.
+var txVariants = []rlpstruct.Variant{
+	{Type: EthTxType, New: func() rlpstruct.Codec { return new(EthTx) }},
+	{Type: SaiTxType, New: func() rlpstruct.Codec { return new(SaiTx) }},
+	{Type: BlobTxType, New: func() rlpstruct.Codec { return new(BlobTx) }},
+}
+
 func (t *Transaction) EncodeBinary(w *io.BinWriter) {
-	w.WriteB(t.Type)
+	var payload rlpstruct.Codec
 	switch t.Type {
 	case EthTxType:
-		t.EthTx.EncodeBinary(w)
+		payload = t.EthTx
 	case SaiTxType:
-		t.SaiTx.EncodeBinary(w)
+		payload = t.SaiTx
+	case BlobTxType:
+		payload = t.BlobTx
 	default:
 		w.Err = ErrUnsupportType
+		return
 	}
+	rlpstruct.EncodeTyped(w, t.Type, payload)
 }
 
 func (t *Transaction) DecodeBinary(r *io.BinReader) {
-	t.Type = r.ReadB()
-	switch t.Type {
-	case EthTxType:
-		inner := new(EthTx)
-		inner.DecodeBinary(r)
+	typ, payload, err := rlpstruct.DecodeTyped(r, txVariants)
+	if err != nil {
+		if _, ok := err.(rlpstruct.ErrUnknownVariant); ok {
+			r.Err = ErrUnsupportType
+		}
+		return
+	}
+	t.Type = typ
+	switch inner := payload.(type) {
+	case *EthTx:
 		t.EthTx = inner
-	case SaiTxType:
-		inner := new(SaiTx)
-		inner.DecodeBinary(r)
+	case *SaiTx:
 		t.SaiTx = inner
-	default:
-		r.Err = ErrUnsupportType
+	case *BlobTx:
+		t.BlobTx = inner
 	}
 }
 
@@ -244,6 +433,11 @@ func (t *Transaction) Verify(chainId uint64) error {
 			return ErrWitnessUnmatch
 		}
 		return t.SaiTx.Witness.VerifyHashable(chainId, t.SaiTx)
+	case BlobTxType:
+		if t.BlobTx.From != t.BlobTx.Witness.Address() {
+			return ErrWitnessUnmatch
+		}
+		return t.BlobTx.Witness.VerifyHashable(chainId, t.BlobTx)
 	default:
 		return ErrUnsupportType
 	}
@@ -259,10 +453,14 @@ func (t *Transaction) WithSignature(chainId uint64, sig []byte) error {
 }
 
 func (t *Transaction) WithWitness(witness Witness) error {
-	if t.Type != SaiTxType {
+	switch t.Type {
+	case SaiTxType:
+		t.SaiTx.Witness = witness
+	case BlobTxType:
+		t.BlobTx.Witness = witness
+	default:
 		return ErrUnsupportType
 	}
-	t.SaiTx.Witness = witness
 	return nil
 }
 
@@ -272,6 +470,16 @@ func (t *Transaction) UnmarshalJSON(b []byte) error {
 	if err != nil {
 		return err
 	}
+	if _, ok := tmp["sidecar"]; ok {
+		tx := new(BlobTx)
+		err = json.Unmarshal(b, tx)
+		if err != nil {
+			return err
+		}
+		t.Type = BlobTxType
+		t.BlobTx = tx
+		return nil
+	}
 	if _, ok := tmp["witness"]; ok {
 		tx := new(SaiTx)
 		err = json.Unmarshal(b, tx)
@@ -304,6 +512,8 @@ func (t Transaction) MarshalJSON() ([]byte, error) {
 		return json.Marshal(t.EthTx)
 	case SaiTxType:
 		return json.Marshal(t.SaiTx)
+	case BlobTxType:
+		return json.Marshal(t.BlobTx)
 	default:
 		return nil, ErrUnsupportType
 	}
@@ -318,12 +528,36 @@ var (
 	ErrGasPriceVeryHigh = errors.New("gas price higher than 2^256-1")
 )
 
+// maxUint256 is the largest value a fee or value field may hold: every
+// gas/fee/value parameter is ultimately bound by the EVM's 256-bit word
+// size.
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// validateFeeCap checks a dynamic-fee transaction's tip/fee-cap pair
+// against the bounds EIP-1559 requires: both must fit in a uint256, and
+// the tip may never exceed the fee cap, since a tip above the cap could
+// never actually be paid.
+func validateFeeCap(tip, feeCap *big.Int) error {
+	if tip.Cmp(maxUint256) > 0 {
+		return ErrTipVeryHigh
+	}
+	if feeCap.Cmp(maxUint256) > 0 {
+		return ErrFeeCapVeryHigh
+	}
+	if tip.Cmp(feeCap) > 0 {
+		return ErrTipAboveFeeCap
+	}
+	return nil
+}
+
 func (t Transaction) IsValid() error {
 	switch t.Type {
 	case EthTxType:
 		return t.EthTx.IsValid()
 	case SaiTxType:
 		return t.SaiTx.isValid()
+	case BlobTxType:
+		return t.BlobTx.isValid()
 	default:
 		return ErrInvalidTxType
 	}
@@ -2,7 +2,6 @@ package core
 
 import (
 	"math/big"
-	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/multiverse-dev/saiya/pkg/core/block"
@@ -12,85 +11,73 @@ import (
 	"github.com/multiverse-dev/saiya/pkg/crypto/keys"
 )
 
-// createGenesisBlock creates a genesis block based on the given configuration.
-func createGenesisBlock() (*block.Block, error) {
+// createGenesisBlock creates a genesis block from g: one initialize()
+// transaction per entry in g.Contracts, except for native.SAIAddress,
+// whose initialize call instead carries g.Alloc packed as the
+// addresses/amounts SAI.ContractCall_initialize pre-funds balances from.
+func createGenesisBlock(g *Genesis) (*block.Block, error) {
 	base := block.Header{
 		Version:   0,
 		PrevHash:  common.Hash{},
-		Timestamp: uint64(time.Date(2016, 7, 15, 15, 8, 21, 0, time.UTC).Unix()) * 1000, // Milliseconds.
-		Nonce:     2083236893,
+		Timestamp: g.Timestamp,
+		Nonce:     g.Nonce,
 		Index:     0,
 		Witness: transaction.Witness{
 			VerificationScript: []byte{},
 			InvocationScript:   []byte{},
 		},
 	}
-	h := hash.Keccak256([]byte("initialize()"))
-	initData := h[:4]
-	gas := (transaction.EthLegacyBaseLength + 4) * native.DefaultFeePerByte
 	gasPrice := big.NewInt(int64(native.DefaultGasPrice))
 	from := common.HexToAddress("01")
+
+	txs := make([]*transaction.Transaction, 0, len(g.Contracts))
+	for i, c := range g.Contracts {
+		data, err := genesisInitData(g, c.Address)
+		if err != nil {
+			return nil, err
+		}
+		gas, err := IntrinsicGas(data, nil, false)
+		if err != nil {
+			return nil, err
+		}
+		addr := c.Address
+		txs = append(txs, transaction.NewTx(&transaction.SaiTx{
+			Nonce:    uint64(i),
+			GasPrice: gasPrice,
+			Gas:      gas,
+			From:     from,
+			To:       &addr,
+			Data:     data,
+			Value:    big.NewInt(0),
+			Witness: transaction.Witness{
+				InvocationScript:   []byte{0},
+				VerificationScript: []byte{0},
+			},
+		}))
+	}
 	b := &block.Block{
-		Header: base,
-		Transactions: []*transaction.Transaction{
-			transaction.NewTx(&transaction.SaiTx{
-				Nonce:    0,
-				GasPrice: gasPrice,
-				Gas:      gas,
-				From:     from,
-				To:       &native.DesignationAddress,
-				Data:     initData,
-				Value:    big.NewInt(0),
-				Witness: transaction.Witness{
-					InvocationScript:   []byte{0},
-					VerificationScript: []byte{0},
-				},
-			}),
-			transaction.NewTx(&transaction.SaiTx{
-				Nonce:    0,
-				GasPrice: gasPrice,
-				Gas:      gas,
-				From:     from,
-				To:       &native.PolicyAddress,
-				Data:     initData,
-				Value:    big.NewInt(0),
-				Witness: transaction.Witness{
-					InvocationScript:   []byte{0},
-					VerificationScript: []byte{0},
-				},
-			}),
-			transaction.NewTx(&transaction.SaiTx{
-				Nonce:    0,
-				GasPrice: gasPrice,
-				Gas:      gas,
-				From:     from,
-				To:       &native.SaiAddress,
-				Data:     initData,
-				Value:    big.NewInt(0),
-				Witness: transaction.Witness{
-					InvocationScript:   []byte{0},
-					VerificationScript: []byte{0},
-				},
-			}),
-			transaction.NewTx(&transaction.SaiTx{
-				GasPrice: gasPrice,
-				Gas:      gas,
-				From:     from,
-				To:       &native.ManagementAddress,
-				Data:     initData,
-				Value:    big.NewInt(0),
-				Witness: transaction.Witness{
-					InvocationScript:   []byte{0},
-					VerificationScript: []byte{0},
-				},
-			}),
-		},
+		Header:       base,
+		Transactions: txs,
 	}
 	b.RebuildMerkleRoot()
 
 	return b, nil
 }
 
+// genesisInitData returns the Data field for contractAddr's initialize
+// transaction: a plain initialize() selector for every contract except
+// native.SAIAddress, whose allocations need packing against the SAI ABI.
+func genesisInitData(g *Genesis, contractAddr common.Address) ([]byte, error) {
+	h := hash.Keccak256([]byte("initialize()"))
+	selector := h[:4]
+	if contractAddr != native.SAIAddress {
+		return selector, nil
+	}
+	addrs, amounts := g.allocAddressesAndAmounts()
+	sai := native.NewSAI(nil, nil)
+	return sai.Abi.Pack("initialize", addrs, amounts)
+}
+
 func getConsensusAddress(validators []*keys.PublicKey) (val common.Address, err error) {
 	raw, err := keys.PublicKeys(validators).CreateDefaultMultiSigRedeemScript()
 	if err != nil {
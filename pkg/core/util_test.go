@@ -1,15 +1,32 @@
 package core
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/multiverse-dev/saiya/pkg/core/block"
+	"github.com/multiverse-dev/saiya/pkg/core/native"
 	"github.com/multiverse-dev/saiya/pkg/io"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGenesisBlock(t *testing.T) {
-	b, err := createGenesisBlock()
+	alloc := common.HexToAddress("0x01")
+	g := &Genesis{
+		Timestamp: uint64(1468594101) * 1000,
+		Nonce:     2083236893,
+		Alloc: map[common.Address]GenesisAccount{
+			alloc: {Balance: big.NewInt(1000000)},
+		},
+		Contracts: []GenesisContract{
+			{Address: native.DesignationAddress},
+			{Address: native.PolicyAddress},
+			{Address: native.SAIAddress},
+			{Address: native.ManagementAddress},
+		},
+	}
+	b, err := createGenesisBlock(g)
 	assert.NoError(t, err)
 	bs, err := io.ToByteArray(b)
 	assert.NoError(t, err)
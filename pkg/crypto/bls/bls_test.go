@@ -0,0 +1,144 @@
+package bls
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto/bn256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify(t *testing.T) {
+	sk, pk, err := GenerateKey()
+	require.NoError(t, err)
+
+	msg := []byte("block hash goes here")
+	sig := sk.Sign(msg)
+
+	require.NoError(t, VerifyAggregate(sig, []*PublicKey{pk}, msg))
+}
+
+func TestAggregateAndVerify(t *testing.T) {
+	const n = 5
+	msg := []byte("aggregated commit over this block")
+
+	sigs := make([]*Signature, n)
+	pubs := make([]*PublicKey, n)
+	for i := 0; i < n; i++ {
+		sk, pk, err := GenerateKey()
+		require.NoError(t, err)
+		sigs[i] = sk.Sign(msg)
+		pubs[i] = pk
+	}
+
+	agg, err := Aggregate(sigs)
+	require.NoError(t, err)
+
+	require.NoError(t, VerifyAggregate(agg, pubs, msg))
+}
+
+func TestAggregate_WrongMessage(t *testing.T) {
+	sk1, pk1, err := GenerateKey()
+	require.NoError(t, err)
+	sk2, pk2, err := GenerateKey()
+	require.NoError(t, err)
+
+	agg, err := Aggregate([]*Signature{sk1.Sign([]byte("a")), sk2.Sign([]byte("a"))})
+	require.NoError(t, err)
+
+	err = VerifyAggregate(agg, []*PublicKey{pk1, pk2}, []byte("b"))
+	assert.ErrorIs(t, err, ErrVerifyFailed)
+}
+
+func TestAggregate_MissingSigner(t *testing.T) {
+	msg := []byte("same message")
+	sk1, pk1, err := GenerateKey()
+	require.NoError(t, err)
+	sk2, pk2, err := GenerateKey()
+	require.NoError(t, err)
+	_, pk3, err := GenerateKey()
+	require.NoError(t, err)
+
+	// Aggregate only signs from sk1 and sk2, but verification is attempted
+	// against all three public keys (pk3 never contributed a signature).
+	agg, err := Aggregate([]*Signature{sk1.Sign(msg), sk2.Sign(msg)})
+	require.NoError(t, err)
+
+	err = VerifyAggregate(agg, []*PublicKey{pk1, pk2, pk3}, msg)
+	assert.ErrorIs(t, err, ErrVerifyFailed)
+}
+
+func TestAggregate_NoSignatures(t *testing.T) {
+	_, err := Aggregate(nil)
+	assert.ErrorIs(t, err, ErrNoSignatures)
+
+	_, err = AggregatePublicKeys(nil)
+	assert.ErrorIs(t, err, ErrNoSignatures)
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	sk, pk, err := GenerateKey()
+	require.NoError(t, err)
+	msg := []byte("round trip this")
+	sig := sk.Sign(msg)
+
+	sigBytes := sig.Marshal()
+	sig2, err := Unmarshal(sigBytes)
+	require.NoError(t, err)
+
+	pubBytes := pk.Marshal()
+	pk2, err := UnmarshalPublicKey(pubBytes)
+	require.NoError(t, err)
+
+	require.NoError(t, VerifyAggregate(sig2, []*PublicKey{pk2}, msg))
+}
+
+func TestProveAndVerifyPossession(t *testing.T) {
+	sk, pk, err := GenerateKey()
+	require.NoError(t, err)
+
+	proof := ProvePossession(sk, pk)
+	require.NoError(t, VerifyPossession(pk, proof))
+}
+
+// TestRogueKeyAttack_Unprotected shows why AggregatePublicKeys requires a
+// proof of possession per key: an attacker with no private key at all for
+// pubRogue can still make an aggregate over [pubHonest, pubRogue] verify, by
+// publishing pubRogue = pubTarget - pubHonest for some pubTarget it does
+// hold the private key for, then presenting skTarget.Sign(msg) as if it
+// were the honest pair's joint signature. Nothing in Aggregate/
+// AggregatePublicKeys/VerifyAggregate catches this on their own - that's
+// exactly why real callers must run VerifyPossession on every key first.
+func TestRogueKeyAttack_Unprotected(t *testing.T) {
+	_, pkHonest, err := GenerateKey()
+	require.NoError(t, err)
+	skTarget, pkTarget, err := GenerateKey()
+	require.NoError(t, err)
+
+	rogue := &PublicKey{point: new(bn256.G2).Add(pkTarget.point, new(bn256.G2).Neg(pkHonest.point))}
+
+	msg := []byte("forged aggregate commit")
+	forgedSig := skTarget.Sign(msg)
+
+	// Verifies even though pubHonest never signed anything and the rogue
+	// key's "owner" never held a matching private key.
+	require.NoError(t, VerifyAggregate(forgedSig, []*PublicKey{pkHonest, rogue}, msg))
+
+	// VerifyPossession rejects the rogue key outright, since nobody holds
+	// its discrete log.
+	err = VerifyPossession(rogue, ProvePossession(skTarget, pkHonest))
+	assert.ErrorIs(t, err, ErrVerifyFailed)
+}
+
+// TestVerifyPossession_WrongKey ensures a proof of possession doesn't
+// transfer to a different public key than the one it was made for.
+func TestVerifyPossession_WrongKey(t *testing.T) {
+	sk1, _, err := GenerateKey()
+	require.NoError(t, err)
+	_, pk2, err := GenerateKey()
+	require.NoError(t, err)
+
+	proof := sk1.Sign([]byte("not a possession proof"))
+	err = VerifyPossession(pk2, proof)
+	assert.ErrorIs(t, err, ErrVerifyFailed)
+}
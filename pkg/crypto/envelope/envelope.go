@@ -0,0 +1,54 @@
+// Package envelope implements a commit-reveal scheme for hiding transaction
+// contents until a block proposal has already been committed to, so that a
+// speaker (or anyone observing PrepareRequest gossip) can't reorder, front-run
+// or censor transactions based on their content before the round is final.
+package envelope
+
+import (
+	"crypto/rand"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/crypto/sha3"
+)
+
+// ErrMismatch is returned by Open when the revealed transaction and nonce
+// don't hash to the sealed commitment.
+var ErrMismatch = errors.New("envelope: revealed transaction does not match commitment")
+
+// NonceSize is the length, in bytes, of the random blinding nonce mixed into
+// every commitment.
+const NonceSize = 32
+
+// Envelope is the commitment published in place of a raw transaction during
+// the Prepare stage: just a hash, revealing nothing about the transaction it
+// stands for.
+type Envelope struct {
+	Commitment common.Hash
+}
+
+// Seal produces the Envelope for txBytes along with the random nonce that
+// must be kept secret until reveal time and supplied back to Open.
+func Seal(txBytes []byte) (Envelope, []byte, error) {
+	nonce := make([]byte, NonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return Envelope{}, nil, err
+	}
+	return Envelope{Commitment: commit(txBytes, nonce)}, nonce, nil
+}
+
+// Open verifies that txBytes and nonce reveal env, returning ErrMismatch if
+// they don't.
+func Open(env Envelope, txBytes, nonce []byte) error {
+	if commit(txBytes, nonce) != env.Commitment {
+		return ErrMismatch
+	}
+	return nil
+}
+
+func commit(txBytes, nonce []byte) common.Hash {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(txBytes)
+	h.Write(nonce)
+	return common.BytesToHash(h.Sum(nil))
+}
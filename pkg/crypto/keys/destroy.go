@@ -0,0 +1,15 @@
+package keys
+
+// Destroy wipes the words backing p.D so the key no longer lingers in
+// process memory once it's no longer needed. It's safe to call on a nil
+// receiver or a key whose D hasn't been set.
+func (p *PrivateKey) Destroy() {
+	if p == nil || p.D == nil {
+		return
+	}
+	words := p.D.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	p.D.SetInt64(0)
+}
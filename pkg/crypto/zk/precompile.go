@@ -0,0 +1,98 @@
+package zk
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PrecompileAddress is an address not used by any other native contract or
+// precompile, reserved for the Groth16 verifyProof entry point.
+var PrecompileAddress = common.BytesToAddress([]byte{0x09})
+
+// VerifyProofGas is the flat gas cost charged for the verifyProof
+// precompile, in the same spirit as the fixed-cost pairing-check
+// precompiles (0x08) it piggybacks on.
+const VerifyProofGas = 150000
+
+// Precompile exposes Verify as an EVM precompiled contract so Solidity
+// contracts can call verifyProof(bytes proof, bytes vk, uint256[] input)
+// directly instead of trusting an off-chain verifier.
+type Precompile struct{}
+
+// RequiredGas implements vm.PrecompiledContract.
+func (Precompile) RequiredGas(_ []byte) uint64 {
+	return VerifyProofGas
+}
+
+// Run implements vm.PrecompiledContract. input is ABI-encoded as
+// (bytes proof, bytes vk, uint256[] publicInputs); it returns a single
+// big-endian word, 1 for a valid proof and 0 otherwise.
+func (p Precompile) Run(input []byte) ([]byte, error) {
+	proof, vk, pubInputs, err := decodeVerifyProofInput(input)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := Verify(proof, vk, pubInputs)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 32)
+	if ok {
+		out[31] = 1
+	}
+	return out, nil
+}
+
+// decodeVerifyProofInput performs a minimal ABI decode of
+// (bytes, bytes, uint256[]) without depending on go-ethereum/accounts/abi,
+// since the precompile path needs to stay allocation-light and dependency
+// free from the contract-binding tooling.
+func decodeVerifyProofInput(input []byte) (proof, vk []byte, pubInputs []*big.Int, err error) {
+	readUint256 := func(off int) *big.Int {
+		return new(big.Int).SetBytes(input[off : off+32])
+	}
+	readBytes := func(headOff int) ([]byte, error) {
+		if headOff+32 > len(input) {
+			return nil, ErrInvalidLength
+		}
+		dataOff := int(readUint256(headOff).Int64())
+		if dataOff+32 > len(input) {
+			return nil, ErrInvalidLength
+		}
+		length := int(readUint256(dataOff).Int64())
+		start := dataOff + 32
+		if start+length > len(input) {
+			return nil, ErrInvalidLength
+		}
+		return input[start : start+length], nil
+	}
+
+	if len(input) < 3*32 {
+		return nil, nil, nil, ErrInvalidLength
+	}
+	proof, err = readBytes(0)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	vk, err = readBytes(32)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	arrHeadOff := int(readUint256(64).Int64())
+	if arrHeadOff+32 > len(input) {
+		return nil, nil, nil, ErrInvalidLength
+	}
+	count := int(readUint256(arrHeadOff).Int64())
+	pubInputs = make([]*big.Int, count)
+	base := arrHeadOff + 32
+	for i := 0; i < count; i++ {
+		off := base + i*32
+		if off+32 > len(input) {
+			return nil, nil, nil, ErrInvalidLength
+		}
+		pubInputs[i] = readUint256(off)
+	}
+	return proof, vk, pubInputs, nil
+}
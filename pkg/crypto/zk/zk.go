@@ -1,13 +1,176 @@
+// Package zk implements a Groth16 zk-SNARK verifier over the BN254
+// (alt_bn128) curve, matching EVM precompiles 0x06/0x07/0x08 so it can share
+// a curve with the rest of the zk tooling ecosystem.
 package zk
 
-/*
-#cgo LDFLAGS: -L./lib -lzk
-#include <stdlib.h>
-#include "./lib/zk.h"
-*/
-import "C"
-import "unsafe"
+import (
+	"errors"
+	"math/big"
 
-func Verify(proof, key []byte) int {
-	return int(C.verify((*C.uchar)(unsafe.Pointer(&proof[0])), C.uint(len(proof)), (*C.uchar)(unsafe.Pointer(&key[0])), C.uint(len(key))))
+	"github.com/ethereum/go-ethereum/crypto/bn256"
+)
+
+var (
+	// ErrInvalidPublicInput is returned when a public input is not reduced
+	// modulo the BN254 scalar field.
+	ErrInvalidPublicInput = errors.New("zk: public input not reduced mod r")
+	// ErrICLength is returned when the verifying key's IC does not have
+	// exactly len(publicInputs)+1 entries.
+	ErrICLength = errors.New("zk: len(vk.IC) must equal len(publicInputs)+1")
+	// ErrInvalidPoint is returned when a serialized point is not on the
+	// curve or not in the correct subgroup.
+	ErrInvalidPoint = errors.New("zk: point is not on curve or not in subgroup")
+	// ErrInvalidLength is returned for malformed serialized input.
+	ErrInvalidLength = errors.New("zk: invalid serialized length")
+)
+
+// r is the BN254 scalar field order; all public inputs must be reduced
+// modulo r.
+var r, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+
+// VerifyingKey is a Groth16 verifying key over BN254.
+type VerifyingKey struct {
+	Alpha *bn256.G1
+	Beta  *bn256.G2
+	Gamma *bn256.G2
+	Delta *bn256.G2
+	IC    []*bn256.G1
+}
+
+// Proof is a Groth16 proof over BN254.
+type Proof struct {
+	A *bn256.G1
+	B *bn256.G2
+	C *bn256.G1
+}
+
+// unmarshalG1 parses a serialized G1 point and checks it decodes to a valid
+// curve point.
+func unmarshalG1(b []byte) (*bn256.G1, error) {
+	p := new(bn256.G1)
+	if _, err := p.Unmarshal(b); err != nil {
+		return nil, ErrInvalidPoint
+	}
+	return p, nil
+}
+
+func unmarshalG2(b []byte) (*bn256.G2, error) {
+	p := new(bn256.G2)
+	if _, err := p.Unmarshal(b); err != nil {
+		return nil, ErrInvalidPoint
+	}
+	return p, nil
+}
+
+// ParseVerifyingKey deserializes a VerifyingKey from vkBytes, laid out as
+// Alpha(G1) || Beta(G2) || Gamma(G2) || Delta(G2) || IC[0](G1) || IC[1](G1) || ...
+// using bn256's point encoding (uncompressed coordinates).
+func ParseVerifyingKey(vkBytes []byte) (*VerifyingKey, error) {
+	g1Len := len(new(bn256.G1).Marshal())
+	g2Len := len(new(bn256.G2).Marshal())
+	min := g1Len + 3*g2Len
+	if len(vkBytes) < min || (len(vkBytes)-min)%g1Len != 0 {
+		return nil, ErrInvalidLength
+	}
+
+	off := 0
+	alpha, err := unmarshalG1(vkBytes[off : off+g1Len])
+	if err != nil {
+		return nil, err
+	}
+	off += g1Len
+	beta, err := unmarshalG2(vkBytes[off : off+g2Len])
+	if err != nil {
+		return nil, err
+	}
+	off += g2Len
+	gamma, err := unmarshalG2(vkBytes[off : off+g2Len])
+	if err != nil {
+		return nil, err
+	}
+	off += g2Len
+	delta, err := unmarshalG2(vkBytes[off : off+g2Len])
+	if err != nil {
+		return nil, err
+	}
+	off += g2Len
+
+	var ic []*bn256.G1
+	for off < len(vkBytes) {
+		p, err := unmarshalG1(vkBytes[off : off+g1Len])
+		if err != nil {
+			return nil, err
+		}
+		ic = append(ic, p)
+		off += g1Len
+	}
+
+	return &VerifyingKey{Alpha: alpha, Beta: beta, Gamma: gamma, Delta: delta, IC: ic}, nil
+}
+
+// ParseProof deserializes a Proof from proofBytes, laid out as
+// A(G1) || B(G2) || C(G1).
+func ParseProof(proofBytes []byte) (*Proof, error) {
+	g1Len := len(new(bn256.G1).Marshal())
+	g2Len := len(new(bn256.G2).Marshal())
+	if len(proofBytes) != 2*g1Len+g2Len {
+		return nil, ErrInvalidLength
+	}
+
+	off := 0
+	a, err := unmarshalG1(proofBytes[off : off+g1Len])
+	if err != nil {
+		return nil, err
+	}
+	off += g1Len
+	b, err := unmarshalG2(proofBytes[off : off+g2Len])
+	if err != nil {
+		return nil, err
+	}
+	off += g2Len
+	c, err := unmarshalG1(proofBytes[off : off+g1Len])
+	if err != nil {
+		return nil, err
+	}
+
+	return &Proof{A: a, B: b, C: c}, nil
+}
+
+// Verify checks a Groth16 proof against vk and publicInputs, i.e. that
+//
+//	e(A, B) == e(alpha, beta) . e(vk_x, gamma) . e(C, delta)
+//
+// where vk_x = IC[0] + sum(publicInputs[i] * IC[i+1]).
+func Verify(proofBytes, vkBytes []byte, publicInputs []*big.Int) (bool, error) {
+	vk, err := ParseVerifyingKey(vkBytes)
+	if err != nil {
+		return false, err
+	}
+	proof, err := ParseProof(proofBytes)
+	if err != nil {
+		return false, err
+	}
+	if len(vk.IC) != len(publicInputs)+1 {
+		return false, ErrICLength
+	}
+	for _, in := range publicInputs {
+		if in.Sign() < 0 || in.Cmp(r) >= 0 {
+			return false, ErrInvalidPublicInput
+		}
+	}
+
+	vkx := new(bn256.G1).Set(vk.IC[0])
+	for i, in := range publicInputs {
+		term := new(bn256.G1).ScalarMult(vk.IC[i+1], in)
+		vkx.Add(vkx, term)
+	}
+
+	// e(A, B) == e(alpha, beta) . e(vkx, gamma) . e(C, delta)
+	// <=> e(-A, B) . e(alpha, beta) . e(vkx, gamma) . e(C, delta) == 1
+	negA := new(bn256.G1).Neg(proof.A)
+	ok := bn256.PairingCheck(
+		[]*bn256.G1{negA, vk.Alpha, vkx, proof.C},
+		[]*bn256.G2{proof.B, vk.Beta, vk.Gamma, vk.Delta},
+	)
+	return ok, nil
 }
@@ -1,14 +1,65 @@
 package zk
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/crypto/bn256"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestVerif(t *testing.T) {
-	proof := []byte{1}
-	key := []byte{1}
-	r := Verify(proof, key)
-	assert.Equal(t, 0, r)
+// buildValidProof constructs a trivial Groth16 instance (alpha=G1, beta=
+// gamma=delta=G2, a single public input x with IC = [0, G1]) for which
+// A=alpha, B=beta, C=0 and vk_x = IC[0] + x*IC[1] satisfies the pairing
+// equation when x == 0, giving us a known-good fixture without a real
+// trusted setup.
+func buildValidProof(t *testing.T) ([]byte, []byte, []*big.Int) {
+	t.Helper()
+
+	alpha := new(bn256.G1).ScalarBaseMult(big.NewInt(1))
+	beta := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+	gamma := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+	delta := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+	ic0 := new(bn256.G1).ScalarBaseMult(big.NewInt(0))
+	ic1 := new(bn256.G1).ScalarBaseMult(big.NewInt(1))
+
+	vk := &VerifyingKey{Alpha: alpha, Beta: beta, Gamma: gamma, Delta: delta, IC: []*bn256.G1{ic0, ic1}}
+	proof := &Proof{A: alpha, B: beta, C: new(bn256.G1).ScalarBaseMult(big.NewInt(0))}
+
+	vkBytes := append(append(append(append(vk.Alpha.Marshal(), vk.Beta.Marshal()...), vk.Gamma.Marshal()...), vk.Delta.Marshal()...))
+	for _, p := range vk.IC {
+		vkBytes = append(vkBytes, p.Marshal()...)
+	}
+	proofBytes := append(append(proof.A.Marshal(), proof.B.Marshal()...), proof.C.Marshal()...)
+
+	return proofBytes, vkBytes, []*big.Int{big.NewInt(0)}
+}
+
+func TestVerify(t *testing.T) {
+	proofBytes, vkBytes, inputs := buildValidProof(t)
+
+	ok, err := Verify(proofBytes, vkBytes, inputs)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestVerify_WrongPublicInput(t *testing.T) {
+	proofBytes, vkBytes, _ := buildValidProof(t)
+
+	ok, err := Verify(proofBytes, vkBytes, []*big.Int{big.NewInt(1)})
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestVerify_PublicInputNotReduced(t *testing.T) {
+	proofBytes, vkBytes, _ := buildValidProof(t)
+
+	_, err := Verify(proofBytes, vkBytes, []*big.Int{new(big.Int).Add(r, big.NewInt(1))})
+	assert.ErrorIs(t, err, ErrInvalidPublicInput)
+}
+
+func TestVerify_BadLengths(t *testing.T) {
+	_, err := Verify([]byte{1}, []byte{1}, nil)
+	assert.Error(t, err)
 }
@@ -63,6 +63,10 @@ func (d *DBFT) checkCommit() {
 	d.block = d.CreateBlock()
 	hash := d.block.Hash()
 
+	if d.AggregateCommits != nil {
+		d.aggregateCommits()
+	}
+
 	d.Logger.Info("approving block",
 		zap.Uint32("height", d.BlockIndex),
 		zap.Stringer("hash", hash),
@@ -76,6 +80,46 @@ func (d *DBFT) checkCommit() {
 	d.InitializeConsensus(0)
 }
 
+// aggregateCommits collects the Signature() of every Commit payload counted
+// towards the current view's quorum, along with a bitmap of which validator
+// indices they came from, and folds them into a single signature via
+// Config.AggregateCommits, so that relaying a whole Commit round (e.g. in a
+// RecoveryMessage) doesn't require shipping one signature per validator. If
+// Config.VerifyAggregateCommit is also set, the aggregate is checked against
+// d.block before being accepted. The result is stashed on
+// d.aggregateCommitSig/d.aggregateCommitBitmap, where sendRecoveryMessage
+// picks it up instead of re-deriving it from CommitPayloads.
+func (d *DBFT) aggregateCommits() {
+	sigs := make([][]byte, 0, len(d.CommitPayloads))
+	bitmap := make([]byte, (len(d.Validators)+7)/8)
+	for i, msg := range d.CommitPayloads {
+		if msg != nil && msg.ViewNumber() == d.ViewNumber {
+			sigs = append(sigs, msg.GetCommit().Signature())
+			bitmap[i/8] |= 1 << uint(i%8)
+		}
+	}
+
+	aggSig, err := d.AggregateCommits(sigs)
+	if err != nil {
+		d.Logger.Warn("failed to aggregate commit signatures", zap.Error(err))
+		return
+	}
+
+	if d.VerifyAggregateCommit != nil {
+		if err := d.VerifyAggregateCommit(d.block, aggSig, bitmap, d.Validators); err != nil {
+			d.Logger.Warn("aggregated commit signature does not verify", zap.Error(err))
+			return
+		}
+	}
+
+	d.aggregateCommitSig = aggSig
+	d.aggregateCommitBitmap = bitmap
+
+	d.Logger.Debug("aggregated commit signatures",
+		zap.Int("count", len(sigs)),
+		zap.Int("size", len(aggSig)))
+}
+
 func (d *DBFT) checkChangeView(view byte) {
 	if d.ViewNumber >= view {
 		return
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/crypto/envelope"
 	"github.com/multiverse-dev/saiya/pkg/crypto/keys"
 	"github.com/multiverse-dev/saiya/pkg/dbft/block"
 	"github.com/multiverse-dev/saiya/pkg/dbft/payload"
@@ -39,12 +40,38 @@ type Config struct {
 	// GetVerified returns a slice of verified transactions
 	// to be proposed in a new block.
 	GetVerified func() []block.Transaction
+	// ApplyPolicyToTxSet is meant to be called by the proposer on the
+	// result of GetVerified before building a PrepareRequest from it,
+	// letting a node enforce block-composition policy
+	// (MaxTransactionsPerBlock, MaxBlockSize, MaxBlockSystemFee,
+	// per-sender caps, blocked senders) server-side instead of baking it
+	// into GetVerified itself. It is nil by default, in which case
+	// GetVerified's result would be proposed as-is.
+	//
+	// As of this snapshot there is no internal call site for it: the
+	// proposer-side PrepareRequest construction (sendPrepareRequest) is,
+	// like Context and payload.ConsensusPayload (see types.go), not
+	// present in this checkout. DBFT.ApplyPolicy (dbft.go) calls
+	// GetVerified then this field for real, but nothing calls ApplyPolicy
+	// itself yet - it's there for a caller assembling a PrepareRequest by
+	// hand until sendPrepareRequest exists.
+	ApplyPolicyToTxSet func([]block.Transaction) []block.Transaction
 	// VerifyBlock verifies if block is valid.
 	VerifyBlock func(b block.Block) bool
 	// Broadcast should broadcast payload m to the consensus nodes.
 	Broadcast func(m payload.ConsensusPayload)
 	// ProcessBlock is called every time new block is accepted.
 	ProcessBlock func(b block.Block)
+	// OnPersist and PostPersist, when set, are called by ProcessBlock
+	// immediately before and after it commits b, so that consensus-driven
+	// state changes (e.g. a next-block validator list computed from
+	// on-chain votes - see interop.PersistHooks, which plays the same role
+	// around the EVM transaction loop) land deterministically in the same
+	// state transition as the block itself, rather than in a separate
+	// out-of-band path that could fall out of sync with it. Either may be
+	// left nil to skip that step.
+	OnPersist   func(b block.Block) error
+	PostPersist func(b block.Block) error
 	// GetBlock should return block with hash.
 	GetBlock func(h common.Hash) block.Block
 	// WatchOnly tells if a node should only watch.
@@ -78,6 +105,79 @@ type Config struct {
 	VerifyPrepareRequest func(p payload.ConsensusPayload) error
 	// VerifyPrepareResponse performs external PrepareResponse verification and returns nil if it's successful.
 	VerifyPrepareResponse func(p payload.ConsensusPayload) error
+	// AggregateCommits, when set, is called once the Commit stage reaches
+	// quorum with the Signature() of every counted Commit payload, and
+	// should return a single aggregated signature (e.g. a BLS aggregate,
+	// see pkg/crypto/bls) standing in for all of them. It is nil by
+	// default, in which case checkCommit skips aggregation entirely and
+	// behaves exactly as it did before this option existed.
+	AggregateCommits func(sigs [][]byte) ([]byte, error)
+	// SignCommit is meant to replace the default ECDSA
+	// keys.PrivateKey.Sign call a Commit payload's Signature() is built
+	// from, letting a BLS (or other threshold-scheme) private key produce
+	// the partial signature that AggregateCommits later folds together.
+	//
+	// As of this snapshot it has no call site: Commit payloads are built
+	// by makeCommit, which is neither defined nor called in any file
+	// present in this checkout; sendPrepareRequest and sendCommit, which
+	// dbft.go and check.go do call, are not defined here either (see the
+	// ApplyPolicyToTxSet
+	// and types.go notes on the same gap). Setting SignCommit does not
+	// change how Commit payloads are actually signed yet; Commit stages
+	// still sign with plain ECDSA regardless of WithBLSCommit.
+	SignCommit func(b block.Block, priv *keys.PrivateKey) ([]byte, error)
+	// VerifyAggregateCommit, when set, is called after AggregateCommits
+	// produces agg, with bitmap carrying one bit per validator index
+	// (d.Validators order) marking which of them contributed a signature
+	// to agg. It should report whether agg verifies as a valid threshold
+	// signature of b over pubs' subset named by bitmap. Left nil, the
+	// aggregate is accepted without re-verification, matching the
+	// behavior before this option existed.
+	VerifyAggregateCommit func(b block.Block, agg []byte, bitmap []byte, pubs []*keys.PublicKey) error
+	// VRFProve and VRFVerify are meant to replace the block's trivially
+	// grindable proposer-chosen randomness with a verifiable one: each
+	// validator's Commit payload would carry VRFProve's output over
+	// (prevRandao, blockIndex) computed with its dbft keypair, and the
+	// final randomness would fold together every proof counted towards
+	// quorum (e.g. by XOR), the same way AggregateCommits folds Commit
+	// signatures. VRFVerify would re-derive a given validator's
+	// contribution from its proof so the fold can be re-checked when
+	// validating historical blocks.
+	//
+	// DBFT.ProveRandomness (dbft.go) calls VRFProve for real, but nothing
+	// calls ProveRandomness internally: makeCommit, which would attach its
+	// result to an outgoing Commit payload, is not present in this
+	// checkout (same gap as SignCommit above), and
+	// interop.NewEVMBlockContext's block.Nonce-derived Random is
+	// unchanged by setting these. Left nil, block randomness is computed
+	// exactly as it was before these hooks existed; setting them has no
+	// runtime effect yet.
+	VRFProve  func(priv *keys.PrivateKey, prevRandao common.Hash, blockIndex uint32) ([]byte, error)
+	VRFVerify func(pub *keys.PublicKey, prevRandao common.Hash, blockIndex uint32, proof []byte) ([]byte, error)
+	// CompactRecovery, when true, makes sendRecoveryMessage replace the
+	// Commit stage's full per-validator CommitPayloads with the single
+	// bitmap+aggregate signature already produced by aggregateCommits
+	// (see AggregateCommits), when one is available for the current view.
+	// PrepareResponse and ChangeView evidence is still packed per
+	// validator regardless of this flag - there is no aggregate-signature
+	// scheme for those stages yet, so compacting them would lose
+	// information a recovering node needs. It is false by default.
+	CompactRecovery bool
+	// RecoveryBackoff, when set, is called with the number of
+	// sendRecoveryRequest calls since the last new height (starting at 1;
+	// timeouts, cached future-height messages and view changes all count)
+	// to get how long to wait after the last broadcast request before
+	// emitting another, letting a node back off exponentially with jitter
+	// instead of rebroadcasting on every timeout during a partitioned view
+	// change. Left nil, a RecoveryRequest is sent on every call as before.
+	RecoveryBackoff func(attempt int) time.Duration
+	// SealEnvelope and OpenEnvelope hold the commit and reveal halves of
+	// an anti-MEV envelope scheme (see pkg/crypto/envelope). Nothing in
+	// this package calls either of them: setting them does not seal or
+	// reveal any transaction, and PrepareRequests carry transactions in
+	// the clear whether they are set or not.
+	SealEnvelope func(txBytes []byte) (envelope.Envelope, []byte, error)
+	OpenEnvelope func(env envelope.Envelope, txBytes, nonce []byte) error
 }
 
 const defaultSecondsPerBlock = time.Second * 15
@@ -216,6 +316,21 @@ func WithGetVerified(f func() []block.Transaction) Option {
 	}
 }
 
+// WithApplyPolicy sets ApplyPolicyToTxSet.
+func WithApplyPolicy(f func([]block.Transaction) []block.Transaction) Option {
+	return func(cfg *Config) {
+		cfg.ApplyPolicyToTxSet = f
+	}
+}
+
+// WithPersist sets OnPersist and PostPersist.
+func WithPersist(onPersist, postPersist func(b block.Block) error) Option {
+	return func(cfg *Config) {
+		cfg.OnPersist = onPersist
+		cfg.PostPersist = postPersist
+	}
+}
+
 // WithVerifyBlock sets VerifyBlock.
 func WithVerifyBlock(f func(b block.Block) bool) Option {
 	return func(cfg *Config) {
@@ -341,3 +456,61 @@ func WithVerifyPrepareResponse(f func(payload.ConsensusPayload) error) Option {
 		cfg.VerifyPrepareResponse = f
 	}
 }
+
+// WithAggregateCommits sets AggregateCommits.
+func WithAggregateCommits(f func(sigs [][]byte) ([]byte, error)) Option {
+	return func(cfg *Config) {
+		cfg.AggregateCommits = f
+	}
+}
+
+// WithEnvelope sets SealEnvelope and OpenEnvelope (see their doc comment:
+// nothing in this package calls them).
+func WithEnvelope(seal func(txBytes []byte) (envelope.Envelope, []byte, error), open func(env envelope.Envelope, txBytes, nonce []byte) error) Option {
+	return func(cfg *Config) {
+		cfg.SealEnvelope = seal
+		cfg.OpenEnvelope = open
+	}
+}
+
+// WithBLSCommit sets SignCommit, AggregateCommits and VerifyAggregateCommit
+// together, switching the Commit stage from N independent ECDSA signatures
+// to a single M-of-N threshold signature (see pkg/crypto/bls). The current
+// per-validator ECDSA path remains the default; the three fields are set
+// as given, and AggregateCommits and VerifyAggregateCommit each take
+// effect on their own whenever they are non-nil.
+//
+// AggregateCommits/VerifyAggregateCommit are genuinely exercised by
+// checkCommit/aggregateCommits (see check.go) once Commit quorum is
+// reached. SignCommit is not: nothing in this checkout calls it (see its
+// doc comment), so enabling WithBLSCommit still leaves Commit payloads
+// signed with plain ECDSA even though their resulting signatures get
+// aggregated and verified as if they were BLS partial signatures -
+// callers should not enable this option expecting real BLS signing until
+// makeCommit exists to call SignCommit.
+func WithBLSCommit(sign func(b block.Block, priv *keys.PrivateKey) ([]byte, error), aggregate func(sigs [][]byte) ([]byte, error), verify func(b block.Block, agg []byte, bitmap []byte, pubs []*keys.PublicKey) error) Option {
+	return func(cfg *Config) {
+		cfg.SignCommit = sign
+		cfg.AggregateCommits = aggregate
+		cfg.VerifyAggregateCommit = verify
+	}
+}
+
+// WithRecoveryPolicy sets CompactRecovery and RecoveryBackoff, controlling
+// RecoveryRequest backoff and how sendRecoveryMessage packs its evidence.
+func WithRecoveryPolicy(compact bool, backoff func(attempt int) time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.CompactRecovery = compact
+		cfg.RecoveryBackoff = backoff
+	}
+}
+
+// WithVRF sets VRFProve and VRFVerify, enabling verifiable per-block
+// randomness contributed by the consensus round in place of a proposer-
+// chosen value.
+func WithVRF(prove func(priv *keys.PrivateKey, prevRandao common.Hash, blockIndex uint32) ([]byte, error), verify func(pub *keys.PublicKey, prevRandao common.Hash, blockIndex uint32, proof []byte) ([]byte, error)) Option {
+	return func(cfg *Config) {
+		cfg.VRFProve = prove
+		cfg.VRFVerify = verify
+	}
+}
@@ -21,6 +21,29 @@ type (
 		*sync.Mutex
 		cache      cache
 		recovering bool
+
+		// recoveryAttempts counts consecutive sendRecoveryRequest calls
+		// since the last fresh height (InitializeConsensus(0) resets it
+		// to 0), fed to Config.RecoveryBackoff to space requests out
+		// during a prolonged partition instead of resending on every
+		// timeout.
+		recoveryAttempts int
+		// lastRecoveryRequest is when sendRecoveryRequest last actually
+		// broadcast a request, used together with RecoveryBackoff's
+		// return value to decide whether enough time has passed to send
+		// another one.
+		lastRecoveryRequest time.Time
+
+		// aggregateCommitSig and aggregateCommitBitmap hold the result of
+		// the most recent aggregateCommits call for the current view: the
+		// combined BLS signature over d.block's hash and a bitmap of
+		// which validator indices contributed a Commit signature to it.
+		// sendRecoveryMessage folds these into the outgoing
+		// RecoveryMessage in place of per-validator Commit evidence when
+		// Config.CompactRecovery is set. Both are reset to nil by
+		// InitializeConsensus at the start of every view.
+		aggregateCommitSig    []byte
+		aggregateCommitBitmap []byte
 	}
 
 	// Service is an interface for dBFT consensus.
@@ -29,6 +52,7 @@ type (
 		OnTransaction(block.Transaction)
 		OnReceive(payload.ConsensusPayload)
 		OnTimeout(timer.HV)
+		Sync()
 	}
 )
 
@@ -58,6 +82,25 @@ func New(options ...Option) *DBFT {
 	return d
 }
 
+// Sync reinitializes consensus at the chain's current height. It is a no-op
+// if this instance's state already matches it. Call it whenever the
+// underlying chain may have advanced through a path other than this
+// instance's own Commit stage (e.g. the node synced blocks produced by other
+// validators while it was catching up or restarting), so that a stale
+// dBFT round gets abandoned immediately instead of timing out on a height
+// nobody else is voting on anymore.
+func (d *DBFT) Sync() {
+	if d.CurrentHeight()+1 == d.BlockIndex {
+		return
+	}
+
+	d.Logger.Info("resyncing dbft to current chain height",
+		zap.Uint32("chain_height", d.CurrentHeight()),
+		zap.Uint32("consensus_height", d.BlockIndex))
+
+	d.InitializeConsensus(0)
+}
+
 func (d *DBFT) addTransaction(tx block.Transaction) {
 	d.Transactions[tx.Hash()] = tx
 	if d.hasAllTransactions() {
@@ -85,6 +128,8 @@ func (d *DBFT) Start() {
 // InitializeConsensus initializes dBFT instance.
 func (d *DBFT) InitializeConsensus(view byte) {
 	d.reset(view)
+	d.aggregateCommitSig = nil
+	d.aggregateCommitBitmap = nil
 
 	var role string
 
@@ -112,6 +157,17 @@ func (d *DBFT) InitializeConsensus(view byte) {
 		return
 	}
 
+	if view == 0 {
+		d.recoveryAttempts = 0
+	} else if !d.recovering {
+		// Resuming a view change this node didn't drive itself (e.g. it
+		// just restarted, or fell behind mid-change): ask around for
+		// whatever PrepareRequest/PreparationPayloads/CommitPayloads/
+		// ChangeViewPayloads it missed instead of waiting out the full
+		// backed-off timeout below with nothing.
+		d.sendRecoveryRequest()
+	}
+
 	var timeout time.Duration
 	if d.IsPrimary() && !d.recovering {
 		// Initializing to view 0 means we have just persisted previous block or are starting consensus first time.
@@ -193,6 +249,7 @@ func (d *DBFT) OnTimeout(hv timer.HV) {
 			d.changeTimer(d.SecondsPerBlock << 1)
 		} else {
 			d.sendChangeView(payload.CVTimeout)
+			d.sendRecoveryRequest()
 		}
 	}
 }
@@ -227,6 +284,9 @@ func (d *DBFT) OnReceive(msg payload.ConsensusPayload) {
 			zap.Uint("view", uint(msg.ViewNumber())),
 			zap.Any("cache", d.cache.mail[msg.Height()]))
 		d.cache.addMessage(msg)
+		if !d.Context.WatchOnly() {
+			d.sendRecoveryRequest()
+		}
 		return
 	} else if msg.ValidatorIndex() > uint16(d.N()) {
 		return
@@ -282,6 +342,35 @@ func (d *DBFT) start() {
 	d.sendPrepareRequest()
 }
 
+// ApplyPolicy runs Config.GetVerified's result through
+// Config.ApplyPolicyToTxSet, returning it unchanged if ApplyPolicyToTxSet
+// is nil. sendPrepareRequest - the proposer-side code that would call this
+// between GetVerified and building a PrepareRequest - is not present in
+// this checkout (see ApplyPolicyToTxSet's doc comment), so nothing calls
+// ApplyPolicy internally yet; it exists so a caller assembling a
+// PrepareRequest by hand in the meantime can still apply the configured
+// block-composition policy instead of reimplementing this plumbing.
+func (d *DBFT) ApplyPolicy() []block.Transaction {
+	txs := d.GetVerified()
+	if d.ApplyPolicyToTxSet == nil {
+		return txs
+	}
+	return d.ApplyPolicyToTxSet(txs)
+}
+
+// ProveRandomness returns Config.VRFProve's output for this node's keypair
+// over (prevRandao, blockIndex), or (nil, nil) if VRFProve is unset. Like
+// ApplyPolicy, this has no internal caller yet: the Commit-payload
+// construction that would attach a VRF proof (makeCommit) is not present
+// in this checkout (see VRFProve's doc comment).
+func (d *DBFT) ProveRandomness(prevRandao common.Hash) ([]byte, error) {
+	if d.VRFProve == nil {
+		return nil, nil
+	}
+	_, priv, _ := d.GetKeyPair(d.Validators)
+	return d.VRFProve(priv, prevRandao, d.BlockIndex)
+}
+
 func (d *DBFT) onPrepareRequest(msg payload.ConsensusPayload) {
 	// ignore prepareRequest if we had already received it or
 	// are in process of changing view
@@ -527,6 +616,74 @@ func (d *DBFT) onRecoveryRequest(msg payload.ConsensusPayload) {
 	d.sendRecoveryMessage()
 }
 
+// sendRecoveryRequest broadcasts a RecoveryRequest for the current height
+// and view, asking any primary or backup that already has a
+// PrepareRequest, PreparationPayloads, CommitPayloads or ChangeViewPayloads
+// entry for it to reply with a RecoveryMessage. It's how a node that
+// started late, restarted, or missed payloads during a view change catches
+// up without waiting for the next block, instead of relying on
+// checkPrepare/checkCommit/checkChangeView's usual quorum counting, which
+// can't make progress on payloads this node never received in the first
+// place.
+//
+// recoveryAttempts/lastRecoveryRequest and Config.RecoveryBackoff exist so
+// a node stuck in a long partition doesn't rebroadcast a request every
+// single timeout once nobody's left to answer it.
+func (d *DBFT) sendRecoveryRequest() {
+	d.recoveryAttempts++
+
+	if d.RecoveryBackoff != nil {
+		if wait := d.RecoveryBackoff(d.recoveryAttempts); wait > 0 {
+			if !d.lastRecoveryRequest.IsZero() && d.Timer.Now().Sub(d.lastRecoveryRequest) < wait {
+				return
+			}
+		}
+	}
+	d.lastRecoveryRequest = d.Timer.Now()
+
+	req := d.NewRecoveryRequest()
+	d.broadcast(d.NewConsensusPayload(&d.Context, payload.RecoveryRequestType, req))
+}
+
+// sendRecoveryMessage broadcasts this node's current-view state - its
+// PrepareRequest (if it's the primary's), PreparationPayloads,
+// CommitPayloads and ChangeViewPayloads - as a single RecoveryMessage.
+// Whoever receives it replays the embedded payloads through OnReceive
+// exactly as if they'd arrived normally, which re-runs
+// checkPrepare/checkCommit/checkChangeView against them via the usual
+// onPrepareRequest/onPrepareResponse/onCommit/onChangeView handlers.
+//
+// When Config.CompactRecovery is set and this view already produced an
+// aggregate via aggregateCommits, the individual CommitPayloads are
+// replaced by that single aggregate signature plus its bitmap
+// (SetAggregateCommit), roughly halving the message's size for a
+// validator set large enough that per-signature overhead dominates.
+func (d *DBFT) sendRecoveryMessage() {
+	recovery := d.NewRecoveryMessage()
+
+	for _, m := range d.PreparationPayloads {
+		if m != nil {
+			recovery.AddPayload(m)
+		}
+	}
+	if d.CompactRecovery && d.aggregateCommitSig != nil {
+		recovery.SetAggregateCommit(d.aggregateCommitBitmap, d.aggregateCommitSig)
+	} else {
+		for _, m := range d.CommitPayloads {
+			if m != nil {
+				recovery.AddPayload(m)
+			}
+		}
+	}
+	for _, m := range d.ChangeViewPayloads {
+		if m != nil {
+			recovery.AddPayload(m)
+		}
+	}
+
+	d.broadcast(d.NewConsensusPayload(&d.Context, payload.RecoveryMessageType, recovery))
+}
+
 func (d *DBFT) onRecoveryMessage(msg payload.ConsensusPayload) {
 	d.Logger.Debug("recovery message received", zap.Any("dump", msg))
 
@@ -584,6 +741,19 @@ func (d *DBFT) onRecoveryMessage(msg payload.ConsensusPayload) {
 			validCommits++
 			d.OnReceive(m)
 		}
+
+		// NOTE: a sender with CompactRecovery set may instead have packed
+		// its Commit evidence as recovery.AggregateCommit()'s bitmap+sig,
+		// in which case GetCommits above returns nothing and the lines
+		// that would re-verify that aggregate and fold its bits into
+		// d.CommitPayloads are not implemented here: doing so needs a
+		// payload.Commit value to feed through the usual d.OnReceive path
+		// (same as GetCommits builds per entry), and that concrete type -
+		// like payload.ConsensusPayload itself - lives in a payload
+		// package file not present in this checkout (see types.go).
+		// CompactRecovery therefore only shrinks what a compliant sender
+		// transmits; a receiving node here still needs per-validator
+		// Commit evidence to actually recover from it.
 	}
 }
 
@@ -0,0 +1,13 @@
+package dbft
+
+// A categorized extensible-payload layer - Category on payload.
+// ConsensusPayload, a category-tagged Broadcast envelope, WithPayloadCategory
+// and RegisterExtensibleHandler on the dbft service - needs two types this
+// checkout doesn't have: payload.ConsensusPayload itself (only payload/
+// recovery_message.go and payload/recovery_request.go are here; the
+// interface lives elsewhere) and the DBFT/Context structs RegisterExtensibleHandler would be
+// a method on (see types.go's Hash/Address comment for why - Context and
+// ConsensusPayload aren't present, so DBFT can't be parameterized or
+// extended here either). Config.Broadcast's signature can't safely change
+// out from under every existing caller without that interface in hand, so
+// this waits on the same missing files types.go already flags.
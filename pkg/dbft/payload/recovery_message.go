@@ -22,6 +22,19 @@ type (
 		// GetCommits returns a slice of Commit in any order.
 		GetCommits(p ConsensusPayload, validators []*keys.PublicKey) []ConsensusPayload
 
+		// SetAggregateCommit records bitmap (one bit per validator index,
+		// set for every validator whose Commit signature is folded into
+		// sig) and sig (e.g. a BLS aggregate, see pkg/crypto/bls) in place
+		// of the per-validator commitPayloads AddPayload would otherwise
+		// accumulate, for a sender that has Config.CompactRecovery set and
+		// an aggregate available for the view being recovered.
+		SetAggregateCommit(bitmap, sig []byte)
+		// AggregateCommit returns the bitmap and signature set by
+		// SetAggregateCommit, or (nil, nil) if none was set - i.e. this
+		// RecoveryMessage still carries per-validator Commit evidence via
+		// GetCommits instead.
+		AggregateCommit() (bitmap, sig []byte)
+
 		// PreparationHash returns has of PrepareRequest payload for this epoch.
 		// It can be useful in case only PrepareResponse payloads were received.
 		PreparationHash() *common.Hash
@@ -35,11 +48,23 @@ type (
 		commitPayloads      []commitCompact
 		changeViewPayloads  []changeViewCompact
 		prepareRequest      PrepareRequest
+
+		// aggregateCommitBitmap and aggregateCommitSig hold the
+		// CompactRecovery replacement for commitPayloads, set via
+		// SetAggregateCommit instead of one AddPayload call per Commit.
+		aggregateCommitBitmap []byte
+		aggregateCommitSig    []byte
 	}
 )
 
 var _ RecoveryMessage = (*recoveryMessage)(nil)
 
+// NewRecoveryMessage returns an empty RecoveryMessage ready for
+// AddPayload calls, for use as Config.NewRecoveryMessage.
+func NewRecoveryMessage() RecoveryMessage {
+	return &recoveryMessage{}
+}
+
 // PreparationHash implements RecoveryMessage interface.
 func (m *recoveryMessage) PreparationHash() *common.Hash {
 	return m.preparationHash
@@ -77,6 +102,17 @@ func (m *recoveryMessage) AddPayload(p ConsensusPayload) {
 	}
 }
 
+// SetAggregateCommit implements RecoveryMessage interface.
+func (m *recoveryMessage) SetAggregateCommit(bitmap, sig []byte) {
+	m.aggregateCommitBitmap = bitmap
+	m.aggregateCommitSig = sig
+}
+
+// AggregateCommit implements RecoveryMessage interface.
+func (m *recoveryMessage) AggregateCommit() (bitmap, sig []byte) {
+	return m.aggregateCommitBitmap, m.aggregateCommitSig
+}
+
 func fromPayload(t MessageType, recovery ConsensusPayload, p interface{}) *Payload {
 	return &Payload{
 		message: message{
@@ -171,6 +207,15 @@ func (m recoveryMessage) EncodeBinary(w *io.BinWriter) {
 
 	w.WriteArray(m.preparationPayloads)
 	w.WriteArray(m.commitPayloads)
+
+	hasAgg := m.aggregateCommitSig != nil
+	w.WriteBool(hasAgg)
+	if hasAgg {
+		w.WriteVarUint(uint64(len(m.aggregateCommitBitmap)))
+		w.WriteBytes(m.aggregateCommitBitmap)
+		w.WriteVarUint(uint64(len(m.aggregateCommitSig)))
+		w.WriteBytes(m.aggregateCommitSig)
+	}
 }
 
 // DecodeBinary implements io.Serializable interface.
@@ -196,4 +241,14 @@ func (m *recoveryMessage) DecodeBinary(r *io.BinReader) {
 
 	r.ReadArray(&m.preparationPayloads)
 	r.ReadArray(&m.commitPayloads)
+
+	if hasAgg := r.ReadBool(); hasAgg {
+		bitmapLen := r.ReadVarUint()
+		m.aggregateCommitBitmap = make([]byte, bitmapLen)
+		r.ReadBytes(m.aggregateCommitBitmap)
+
+		sigLen := r.ReadVarUint()
+		m.aggregateCommitSig = make([]byte, sigLen)
+		r.ReadBytes(m.aggregateCommitSig)
+	}
 }
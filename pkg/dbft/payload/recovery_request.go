@@ -0,0 +1,46 @@
+package payload
+
+import "github.com/multiverse-dev/saiya/pkg/io"
+
+type (
+	// RecoveryRequest represents dBFT RecoveryRequest message. It carries
+	// no state of its own beyond when it was sent: receiving one just
+	// tells a primary/backup that the sender wants whatever it has for
+	// the current height/view (PrepareRequest, PreparationPayloads,
+	// CommitPayloads, ChangeViewPayloads) back as a RecoveryMessage.
+	RecoveryRequest interface {
+		// Timestamp returns a nanosecond-precision timestamp of when the
+		// request was made, the same convention PrepareRequest.Timestamp
+		// uses.
+		Timestamp() uint64
+	}
+
+	recoveryRequest struct {
+		timestamp uint64
+	}
+)
+
+var _ RecoveryRequest = (*recoveryRequest)(nil)
+
+// NewRecoveryRequest returns an empty RecoveryRequest, for use as
+// Config.NewRecoveryRequest. Its Timestamp is left zero; like every other
+// payload's timestamp, it's filled in by whatever wraps it into a
+// ConsensusPayload (see Config.NewConsensusPayload).
+func NewRecoveryRequest() RecoveryRequest {
+	return &recoveryRequest{}
+}
+
+// Timestamp implements RecoveryRequest interface.
+func (m *recoveryRequest) Timestamp() uint64 {
+	return m.timestamp
+}
+
+// EncodeBinary implements io.Serializable interface.
+func (m recoveryRequest) EncodeBinary(w *io.BinWriter) {
+	w.WriteU64LE(m.timestamp)
+}
+
+// DecodeBinary implements io.Serializable interface.
+func (m *recoveryRequest) DecodeBinary(r *io.BinReader) {
+	m.timestamp = r.ReadU64LE()
+}
@@ -0,0 +1,21 @@
+package dbft
+
+import "github.com/ethereum/go-ethereum/common"
+
+// Hash and Address name the concrete types dBFT currently hard-codes
+// everywhere (block hashes, validator addresses, ...). They're aliases
+// rather than independent types so existing call sites keep compiling
+// unchanged.
+//
+// They exist as a first, low-risk step towards making this package generic
+// over its hash/address/transaction types: once Hash and Address are used in
+// place of common.Hash/common.Address throughout dbft's public API, swapping
+// these aliases for real type parameters becomes a localized change instead
+// of a rewrite of every call site. That swap can't happen yet: Context and
+// payload.ConsensusPayload, which dbft.DBFT embeds and which carry the same
+// concrete types through the wire format, live in files not present in this
+// checkout, so they can't be parameterized in step.
+type (
+	Hash    = common.Hash
+	Address = common.Address
+)
@@ -0,0 +1,169 @@
+package rlpstruct
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/multiverse-dev/saiya/pkg/io"
+)
+
+// Codec is implemented by field types that know how to serialize
+// themselves (e.g. a Witness), letting Encode/Decode recurse into them
+// instead of walking their fields directly.
+type Codec interface {
+	EncodeBinary(bw *io.BinWriter)
+	DecodeBinary(br *io.BinReader)
+}
+
+// Encode writes *v, a pointer to a struct resolved via PlanOf, to bw one
+// field at a time in declaration order. It's meant to replace a type's
+// hand-written EncodeBinary body once that type's fields carry rlp tags.
+func Encode(bw *io.BinWriter, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("rlpstruct: Encode wants a struct pointer, got %T", v)
+	}
+	rv = rv.Elem()
+	plan, err := PlanOf(rv.Type())
+	if err != nil {
+		return err
+	}
+	for _, f := range plan.Fields {
+		fv := rv.Field(f.Index)
+		if f.Tags.Tail {
+			encodeTail(bw, fv)
+		} else {
+			encodeField(bw, fv, f.Tags)
+		}
+		if bw.Err != nil {
+			return bw.Err
+		}
+	}
+	return nil
+}
+
+// Decode is the inverse of Encode: it reads fields off br into *v (a
+// pointer to a struct resolved via PlanOf) in declaration order. Once br
+// runs out of input, any remaining optional fields are left at their zero
+// value instead of raising an error.
+func Decode(br *io.BinReader, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("rlpstruct: Decode wants a struct pointer, got %T", v)
+	}
+	rv = rv.Elem()
+	plan, err := PlanOf(rv.Type())
+	if err != nil {
+		return err
+	}
+	for _, f := range plan.Fields {
+		if f.Tags.Optional && br.Len() == 0 {
+			break
+		}
+		fv := rv.Field(f.Index)
+		if f.Tags.Tail {
+			decodeTail(br, fv)
+		} else {
+			decodeField(br, fv, f.Tags)
+		}
+		if br.Err != nil {
+			return br.Err
+		}
+	}
+	return nil
+}
+
+func isNilable(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	default:
+		return false
+	}
+}
+
+func encodeField(bw *io.BinWriter, fv reflect.Value, tags Tags) {
+	if tags.NilOK && isNilable(fv) && fv.IsNil() {
+		bw.WriteVarUint(0)
+		return
+	}
+	switch x := fv.Interface().(type) {
+	case Codec:
+		x.EncodeBinary(bw)
+	case *big.Int:
+		if x == nil {
+			bw.WriteVarUint(0)
+		} else {
+			bw.WriteVarBytes(x.Bytes())
+		}
+	case []byte:
+		bw.WriteVarBytes(x)
+	case uint64:
+		bw.WriteU64LE(x)
+	case uint32:
+		bw.WriteU32LE(x)
+	case bool:
+		bw.WriteBool(x)
+	default:
+		bw.Err = fmt.Errorf("rlpstruct: unsupported field type %s", fv.Type())
+	}
+}
+
+func decodeField(br *io.BinReader, fv reflect.Value, tags Tags) {
+	switch fv.Interface().(type) {
+	case Codec:
+		fv.Addr().Interface().(Codec).DecodeBinary(br)
+	case *big.Int:
+		bs := br.ReadVarBytes()
+		if br.Err == nil {
+			fv.Set(reflect.ValueOf(new(big.Int).SetBytes(bs)))
+		}
+	case []byte:
+		fv.SetBytes(br.ReadVarBytes())
+	case uint64:
+		fv.SetUint(br.ReadU64LE())
+	case uint32:
+		fv.SetUint(uint64(br.ReadU32LE()))
+	case bool:
+		fv.SetBool(br.ReadBool())
+	default:
+		br.Err = fmt.Errorf("rlpstruct: unsupported field type %s", fv.Type())
+	}
+}
+
+// encodeTail writes a tail field's elements one after another, each
+// implementing Codec, preceded by a var-length element count.
+func encodeTail(bw *io.BinWriter, fv reflect.Value) {
+	bw.WriteVarUint(uint64(fv.Len()))
+	for i := 0; i < fv.Len(); i++ {
+		ev := fv.Index(i).Addr().Interface()
+		c, ok := ev.(Codec)
+		if !ok {
+			bw.Err = fmt.Errorf("rlpstruct: unsupported tail element type %s", fv.Type().Elem())
+			return
+		}
+		c.EncodeBinary(bw)
+	}
+}
+
+func decodeTail(br *io.BinReader, fv reflect.Value) {
+	n := br.ReadVarUint()
+	if br.Err != nil {
+		return
+	}
+	slice := reflect.MakeSlice(fv.Type(), int(n), int(n))
+	for i := 0; i < int(n); i++ {
+		ev := slice.Index(i).Addr().Interface()
+		c, ok := ev.(Codec)
+		if !ok {
+			br.Err = fmt.Errorf("rlpstruct: unsupported tail element type %s", fv.Type().Elem())
+			return
+		}
+		c.DecodeBinary(br)
+		if br.Err != nil {
+			return
+		}
+	}
+	fv.Set(slice)
+}
@@ -0,0 +1,134 @@
+// Package rlpstruct resolves the struct tags that drive saiya's tagged
+// transaction codecs, borrowing the tag vocabulary go-ethereum's own RLP
+// encoder uses (optional/tail/nil/-) but applying it to this project's own
+// io.BinWriter/io.BinReader wire format instead of true RLP. A type's field
+// list is walked once via reflection and cached, so repeated Encode/Decode
+// calls for the same type pay the reflection cost only on their first use.
+package rlpstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Tags are the resolved `rlp:"..."` tag options for a single struct field.
+type Tags struct {
+	// Ignored is set by `rlp:"-"`: the field is skipped entirely, neither
+	// encoded nor decoded.
+	Ignored bool
+	// Optional is set by `rlp:"optional"`: the field may be absent from the
+	// wire form. Only trailing fields may be optional, and decoding stops
+	// as soon as the input is exhausted, leaving any remaining optional
+	// fields at their zero value.
+	Optional bool
+	// Tail is set by `rlp:"tail"`: the one field, if any, that absorbs
+	// every remaining wire element as a slice instead of occupying a
+	// single element itself. It must be the struct's last field.
+	Tail bool
+	// NilOK is set by `rlp:"nil"`: a nil pointer/slice encodes as an empty
+	// string/list rather than being treated as a programmer error.
+	NilOK bool
+}
+
+// parseTag parses the comma-separated options of a single `rlp:"..."` tag.
+func parseTag(tag string) (Tags, error) {
+	var t Tags
+	if tag == "" {
+		return t, nil
+	}
+	for _, part := range strings.Split(tag, ",") {
+		switch part {
+		case "-":
+			t.Ignored = true
+		case "optional":
+			t.Optional = true
+		case "tail":
+			t.Tail = true
+		case "nil":
+			t.NilOK = true
+		default:
+			return t, fmt.Errorf("rlpstruct: unknown tag option %q", part)
+		}
+	}
+	return t, nil
+}
+
+// Field is one resolved, wire-visible field of a struct type.
+type Field struct {
+	Name  string
+	Index int
+	Type  reflect.Type
+	Tags  Tags
+}
+
+// Plan is the resolved, declaration-ordered field list for a struct type.
+type Plan struct {
+	Fields []Field
+}
+
+var planCache sync.Map // reflect.Type -> *Plan
+
+// PlanOf returns the resolved Plan for struct type typ, building and
+// validating it on first use and reusing the cached Plan on every call
+// after that.
+func PlanOf(typ reflect.Type) (*Plan, error) {
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("rlpstruct: %s is not a struct", typ)
+	}
+	if cached, ok := planCache.Load(typ); ok {
+		return cached.(*Plan), nil
+	}
+	plan, err := buildPlan(typ)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := planCache.LoadOrStore(typ, plan)
+	return actual.(*Plan), nil
+}
+
+func buildPlan(typ reflect.Type) (*Plan, error) {
+	var fields []Field
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if sf.PkgPath != "" { // unexported, can't be reached via reflection
+			continue
+		}
+		tags, err := parseTag(sf.Tag.Get("rlp"))
+		if err != nil {
+			return nil, fmt.Errorf("rlpstruct: %s.%s: %w", typ, sf.Name, err)
+		}
+		if tags.Ignored {
+			continue
+		}
+		fields = append(fields, Field{Name: sf.Name, Index: i, Type: sf.Type, Tags: tags})
+	}
+	if err := validate(typ, fields); err != nil {
+		return nil, err
+	}
+	return &Plan{Fields: fields}, nil
+}
+
+// validate enforces the two structural rules Encode/Decode rely on: every
+// optional field must come after every required one, and a tail field (at
+// most one) must be both the struct's last field and a slice.
+func validate(typ reflect.Type, fields []Field) error {
+	seenOptional := false
+	for i, f := range fields {
+		if f.Tags.Tail {
+			if i != len(fields)-1 {
+				return fmt.Errorf("rlpstruct: %s.%s: tail field must be the struct's last field", typ, f.Name)
+			}
+			if f.Type.Kind() != reflect.Slice {
+				return fmt.Errorf("rlpstruct: %s.%s: tail field must be a slice", typ, f.Name)
+			}
+		}
+		if f.Tags.Optional {
+			seenOptional = true
+		} else if seenOptional {
+			return fmt.Errorf("rlpstruct: %s.%s: required field follows an optional one", typ, f.Name)
+		}
+	}
+	return nil
+}
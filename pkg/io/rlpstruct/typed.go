@@ -0,0 +1,50 @@
+package rlpstruct
+
+import (
+	"fmt"
+
+	"github.com/multiverse-dev/saiya/pkg/io"
+)
+
+// Variant is one typed payload a discriminator byte can select, paired with
+// the constructor DecodeTyped uses to build a fresh Codec to decode into.
+type Variant struct {
+	Type byte
+	New  func() Codec
+}
+
+// ErrUnknownVariant is returned by DecodeTyped when a discriminator byte doesn't
+// match any of the given variants.
+type ErrUnknownVariant byte
+
+func (e ErrUnknownVariant) Error() string {
+	return fmt.Sprintf("rlpstruct: unknown typed variant %#x", byte(e))
+}
+
+// EncodeTyped writes typ followed by payload.EncodeBinary(bw), the shape
+// every typed envelope built on this package (such as the Transaction
+// envelope in pkg/core/transaction) uses to self-describe which of its
+// variants follows.
+func EncodeTyped(bw *io.BinWriter, typ byte, payload Codec) {
+	bw.WriteB(typ)
+	payload.EncodeBinary(bw)
+}
+
+// DecodeTyped reads a discriminator byte off br and decodes the rest into
+// the matching variant's payload, returning its type byte and the decoded
+// Codec. It's the inverse of EncodeTyped.
+func DecodeTyped(br *io.BinReader, variants []Variant) (byte, Codec, error) {
+	typ := br.ReadB()
+	if br.Err != nil {
+		return 0, nil, br.Err
+	}
+	for _, v := range variants {
+		if v.Type == typ {
+			payload := v.New()
+			payload.DecodeBinary(br)
+			return typ, payload, br.Err
+		}
+	}
+	br.Err = ErrUnknownVariant(typ)
+	return typ, nil, br.Err
+}
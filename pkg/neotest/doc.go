@@ -0,0 +1,15 @@
+// Package neotest would normally provide an Executor/ContractInvoker harness
+// for driving a *core.Blockchain through compiled NEF/manifest contracts
+// (DeployContract, Invoke, InvokeChecked, NewUnsignedTx, SignTx, AddNewBlock,
+// PrepareInvocation) in place of hand-rolled test fixtures.
+//
+// It isn't implemented here: this chain has no Neo VM, no NEF/manifest
+// contract format and no testchain-style fixture builder to replace, so
+// there's nothing for an Executor to deploy or invoke. The tests this
+// package would rebase (TestClient_NEP17, TestSignAndPushInvocationTx,
+// TestCreateNEP17TransferTx, and friends in pkg/rpc/server/client_test.go)
+// are themselves the vendored github.com/nspcc-dev/neo-go test suite
+// exercising that external module's own RPC client, not this repo's
+// Ethereum-style RPC server, so rebasing them onto an in-repo harness
+// wouldn't change anything this chain actually runs.
+package neotest
@@ -9,6 +9,33 @@ import (
 // MaxCapabilities is the maximum number of capabilities per payload.
 const MaxCapabilities = 32
 
+// Type represents node capability type.
+type Type byte
+
+const (
+	// FullNode represents full node capability, a peer that advertises
+	// this type serves full blocks and maintains the whole chain state.
+	FullNode Type = iota
+	// TCPServer represents TCP node capability, a peer that advertises
+	// this type can accept plain TCP connections on the given port.
+	TCPServer
+	// WSServer represents WS node capability, a peer that advertises
+	// this type can accept WebSocket connections on the given port.
+	WSServer
+	// ArchiveNode represents archive node capability, a peer that
+	// advertises this type retains historical state beyond the pruning
+	// window and can serve data starting from EarliestHeight.
+	ArchiveNode
+	// StateSyncServer represents state sync server capability, a peer
+	// that advertises this type is willing to serve MPT range proofs
+	// for snap-style sync.
+	StateSyncServer
+	// LightClientServer represents light client server capability, a
+	// peer that advertises this type serves headers and proofs to
+	// light clients without full block data.
+	LightClientServer
+)
+
 // Capabilities is a list of Capability.
 type Capabilities []Capability
 
@@ -26,7 +53,7 @@ func (cs *Capabilities) EncodeBinary(br *io.BinWriter) {
 // checkUniqueCapabilities checks whether payload capabilities have unique type.
 func (cs Capabilities) checkUniqueCapabilities() error {
 	err := errors.New("capabilities with the same type are not allowed")
-	var isFullNode, isTCP, isWS bool
+	var isFullNode, isTCP, isWS, isArchive, isStateSync, isLightClient bool
 	for _, cap := range cs {
 		switch cap.Type {
 		case FullNode:
@@ -44,6 +71,21 @@ func (cs Capabilities) checkUniqueCapabilities() error {
 				return err
 			}
 			isWS = true
+		case ArchiveNode:
+			if isArchive {
+				return err
+			}
+			isArchive = true
+		case StateSyncServer:
+			if isStateSync {
+				return err
+			}
+			isStateSync = true
+		case LightClientServer:
+			if isLightClient {
+				return err
+			}
+			isLightClient = true
 		}
 	}
 	return nil
@@ -63,6 +105,12 @@ func (c *Capability) DecodeBinary(br *io.BinReader) {
 		c.Data = &Node{}
 	case TCPServer, WSServer:
 		c.Data = &Server{}
+	case ArchiveNode:
+		c.Data = &Archive{}
+	case StateSyncServer:
+		c.Data = &StateSync{}
+	case LightClientServer:
+		c.Data = &LightClient{}
 	default:
 		br.Err = errors.New("unknown node capability type")
 		return
@@ -110,3 +158,71 @@ func (s *Server) DecodeBinary(br *io.BinReader) {
 func (s *Server) EncodeBinary(bw *io.BinWriter) {
 	bw.WriteU16LE(s.Port)
 }
+
+// Archive represents archive node capability with the earliest height
+// for which the node still retains full state.
+type Archive struct {
+	// EarliestHeight is the lowest block index this node can still
+	// serve full historical state for.
+	EarliestHeight uint32
+}
+
+// DecodeBinary implements Serializable interface.
+func (a *Archive) DecodeBinary(br *io.BinReader) {
+	a.EarliestHeight = br.ReadU32LE()
+}
+
+// EncodeBinary implements Serializable interface.
+func (a *Archive) EncodeBinary(bw *io.BinWriter) {
+	bw.WriteU32LE(a.EarliestHeight)
+}
+
+// StateSync represents state sync server capability with the maximum
+// size of a single MPT range proof response this node is willing to serve.
+type StateSync struct {
+	// MaxProofSize is the maximum size, in bytes, of a single proof
+	// response this node will return.
+	MaxProofSize uint32
+}
+
+// DecodeBinary implements Serializable interface.
+func (s *StateSync) DecodeBinary(br *io.BinReader) {
+	s.MaxProofSize = br.ReadU32LE()
+}
+
+// EncodeBinary implements Serializable interface.
+func (s *StateSync) EncodeBinary(bw *io.BinWriter) {
+	bw.WriteU32LE(s.MaxProofSize)
+}
+
+// LightClient represents light client server capability with the
+// maximum number of headers or proofs returned per response.
+type LightClient struct {
+	// MaxResponseCount is the maximum number of headers or proofs this
+	// node will return in a single response.
+	MaxResponseCount uint32
+}
+
+// DecodeBinary implements Serializable interface.
+func (l *LightClient) DecodeBinary(br *io.BinReader) {
+	l.MaxResponseCount = br.ReadU32LE()
+}
+
+// EncodeBinary implements Serializable interface.
+func (l *LightClient) EncodeBinary(bw *io.BinWriter) {
+	bw.WriteU32LE(l.MaxResponseCount)
+}
+
+// PreferSyncSource reports whether cs advertises a capability a pruned
+// node's sync manager should prefer when bootstrapping via snap-style
+// state sync or serving getStateRoot-style requests, namely archival
+// state retention or willingness to serve MPT range proofs.
+func (cs Capabilities) PreferSyncSource() bool {
+	for _, cap := range cs {
+		switch cap.Type {
+		case ArchiveNode, StateSyncServer:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package payload
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/io"
+)
+
+// MaxSnapshotRangeAccounts caps how many flat key/value pairs a single
+// SnapshotRange response may carry, the snapshot-layer counterpart of
+// MaxTrieNodeHashes.
+const MaxSnapshotRangeAccounts = 4096
+
+// GetSnapshotRange requests a contiguous slice of the flat snapshot layer
+// rooted at Root (see pkg/core/snapshot), starting at Start and running
+// until End or Bytes of response payload is reached, whichever comes
+// first. It's the snap-sync half of bootstrapping a new node: answered
+// directly out of a peer's disk layer instead of walking an MPT, it's
+// meant to be far cheaper to serve than GetAccountRange for a peer that
+// has a snapshot built.
+type GetSnapshotRange struct {
+	Root  common.Hash
+	Start []byte
+	End   []byte
+	Bytes uint64
+}
+
+// DecodeBinary implements Serializable interface.
+func (p *GetSnapshotRange) DecodeBinary(br *io.BinReader) {
+	br.ReadBytes(p.Root[:])
+	p.Start = br.ReadVarBytes()
+	p.End = br.ReadVarBytes()
+	p.Bytes = br.ReadU64LE()
+}
+
+// EncodeBinary implements Serializable interface.
+func (p *GetSnapshotRange) EncodeBinary(bw *io.BinWriter) {
+	bw.WriteBytes(p.Root[:])
+	bw.WriteVarBytes(p.Start)
+	bw.WriteVarBytes(p.End)
+	bw.WriteU64LE(p.Bytes)
+}
+
+// SnapshotRange is the response to GetSnapshotRange: every requested
+// key/value pair the peer's disk layer held, together with a Merkle range
+// proof authenticating them against Root (generated the same way
+// stateroot.Module.ServeAccountRange's is, by re-deriving the relevant MPT
+// path for the returned keys rather than requiring a full trie walk), and
+// whether keys remain beyond the last one returned.
+type SnapshotRange struct {
+	Accounts []AccountRangeItem
+	Proof    [][]byte
+	More     bool
+}
+
+// DecodeBinary implements Serializable interface.
+func (p *SnapshotRange) DecodeBinary(br *io.BinReader) {
+	u := br.ReadVarUint()
+	if u > MaxSnapshotRangeAccounts {
+		br.Err = errors.New("too many accounts in snapshot range")
+		return
+	}
+	p.Accounts = make([]AccountRangeItem, u)
+	for i := range p.Accounts {
+		p.Accounts[i].Key = br.ReadVarBytes()
+		p.Accounts[i].Value = br.ReadVarBytes()
+	}
+	u = br.ReadVarUint()
+	p.Proof = make([][]byte, u)
+	for i := range p.Proof {
+		p.Proof[i] = br.ReadVarBytes()
+	}
+	p.More = br.ReadBool()
+}
+
+// EncodeBinary implements Serializable interface.
+func (p *SnapshotRange) EncodeBinary(bw *io.BinWriter) {
+	bw.WriteVarUint(uint64(len(p.Accounts)))
+	for _, a := range p.Accounts {
+		bw.WriteVarBytes(a.Key)
+		bw.WriteVarBytes(a.Value)
+	}
+	bw.WriteVarUint(uint64(len(p.Proof)))
+	for _, n := range p.Proof {
+		bw.WriteVarBytes(n)
+	}
+	bw.WriteBool(p.More)
+}
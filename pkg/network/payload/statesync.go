@@ -0,0 +1,147 @@
+package payload
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/io"
+)
+
+// MaxTrieNodeHashes is the maximum number of node hashes a single
+// GetTrieNodes request may carry, and the maximum number of nodes a single
+// TrieNodes response may carry.
+const MaxTrieNodeHashes = 384
+
+// GetTrieNodes requests the raw encoded MPT nodes for Hashes as they exist
+// under Root, for a state-sync client filling in individual nodes it's
+// still missing (e.g. during the healing pass, once the pivot has moved).
+type GetTrieNodes struct {
+	Root   common.Hash
+	Hashes []common.Hash
+}
+
+// DecodeBinary implements Serializable interface.
+func (p *GetTrieNodes) DecodeBinary(br *io.BinReader) {
+	br.ReadBytes(p.Root[:])
+	u := br.ReadVarUint()
+	if u > MaxTrieNodeHashes {
+		br.Err = errors.New("too many trie node hashes requested")
+		return
+	}
+	p.Hashes = make([]common.Hash, u)
+	for i := range p.Hashes {
+		br.ReadBytes(p.Hashes[i][:])
+	}
+}
+
+// EncodeBinary implements Serializable interface.
+func (p *GetTrieNodes) EncodeBinary(bw *io.BinWriter) {
+	bw.WriteBytes(p.Root[:])
+	bw.WriteVarUint(uint64(len(p.Hashes)))
+	for i := range p.Hashes {
+		bw.WriteBytes(p.Hashes[i][:])
+	}
+}
+
+// TrieNodes is the response to GetTrieNodes: the raw encoded node data for
+// each hash that could be served, in the same order as requested, with
+// unavailable ones simply omitted (the requester reconciles by hash, not by
+// position).
+type TrieNodes struct {
+	Nodes [][]byte
+}
+
+// DecodeBinary implements Serializable interface.
+func (p *TrieNodes) DecodeBinary(br *io.BinReader) {
+	u := br.ReadVarUint()
+	if u > MaxTrieNodeHashes {
+		br.Err = errors.New("too many trie nodes in response")
+		return
+	}
+	p.Nodes = make([][]byte, u)
+	for i := range p.Nodes {
+		p.Nodes[i] = br.ReadVarBytes()
+	}
+}
+
+// EncodeBinary implements Serializable interface.
+func (p *TrieNodes) EncodeBinary(bw *io.BinWriter) {
+	bw.WriteVarUint(uint64(len(p.Nodes)))
+	for _, n := range p.Nodes {
+		bw.WriteVarBytes(n)
+	}
+}
+
+// GetAccountRange requests every leaf of the MPT rooted at Root whose key
+// falls within [Start, End] (an empty bound is open-ended), capped at
+// Bytes of response payload, the bulk-transfer half of snap-style sync
+// (paired with GetTrieNodes for filling in individual nodes on demand).
+type GetAccountRange struct {
+	Root  common.Hash
+	Start []byte
+	End   []byte
+	Bytes uint64
+}
+
+// DecodeBinary implements Serializable interface.
+func (p *GetAccountRange) DecodeBinary(br *io.BinReader) {
+	br.ReadBytes(p.Root[:])
+	p.Start = br.ReadVarBytes()
+	p.End = br.ReadVarBytes()
+	p.Bytes = br.ReadU64LE()
+}
+
+// EncodeBinary implements Serializable interface.
+func (p *GetAccountRange) EncodeBinary(bw *io.BinWriter) {
+	bw.WriteBytes(p.Root[:])
+	bw.WriteVarBytes(p.Start)
+	bw.WriteVarBytes(p.End)
+	bw.WriteU64LE(p.Bytes)
+}
+
+// AccountRangeItem is a single key/value leaf within an AccountRange
+// response.
+type AccountRangeItem struct {
+	Key   []byte
+	Value []byte
+}
+
+// AccountRange is the response to GetAccountRange: every requested leaf
+// together with the boundary proof needed to authenticate them against
+// Root via mpt.VerifyRangeProof, plus whether the trie holds further keys
+// beyond the requested range.
+type AccountRange struct {
+	Accounts []AccountRangeItem
+	Proof    [][]byte
+	More     bool
+}
+
+// DecodeBinary implements Serializable interface.
+func (p *AccountRange) DecodeBinary(br *io.BinReader) {
+	u := br.ReadVarUint()
+	p.Accounts = make([]AccountRangeItem, u)
+	for i := range p.Accounts {
+		p.Accounts[i].Key = br.ReadVarBytes()
+		p.Accounts[i].Value = br.ReadVarBytes()
+	}
+	u = br.ReadVarUint()
+	p.Proof = make([][]byte, u)
+	for i := range p.Proof {
+		p.Proof[i] = br.ReadVarBytes()
+	}
+	p.More = br.ReadBool()
+}
+
+// EncodeBinary implements Serializable interface.
+func (p *AccountRange) EncodeBinary(bw *io.BinWriter) {
+	bw.WriteVarUint(uint64(len(p.Accounts)))
+	for _, a := range p.Accounts {
+		bw.WriteVarBytes(a.Key)
+		bw.WriteVarBytes(a.Value)
+	}
+	bw.WriteVarUint(uint64(len(p.Proof)))
+	for _, n := range p.Proof {
+		bw.WriteVarBytes(n)
+	}
+	bw.WriteBool(p.More)
+}
@@ -8,6 +8,13 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/io"
 )
 
+// Transactions carries the Network magic alongside the decoded values because
+// transaction.Transaction.Network is set per-instance during DecodeBinary
+// below, not derived at hash/verify time. That type is owned by the vendored
+// github.com/nspcc-dev/neo-go module, not this repo, so it can't be changed
+// here to take the magic as an explicit parameter instead (the way this
+// repo's own pkg/core/transaction.Witness.VerifyHashable already does, via an
+// explicit chainID argument rather than a stored field).
 type Transactions struct {
 	Network netmode.Magic
 	Values  []*transaction.Transaction
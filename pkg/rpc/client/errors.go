@@ -0,0 +1,11 @@
+package client
+
+import "errors"
+
+// ErrWSConnLost is wrapped into the error returned by a WSClient request
+// method, and into what WSClient.GetError() reports, once the client has
+// determined its underlying websocket connection is gone - wrapped, not
+// just matched by string, so a reconnect loop (or a caller's own retry
+// logic) can test for it with errors.Is instead of matching strings like
+// "connection lost before registering response channel".
+var ErrWSConnLost = errors.New("websocket connection lost")
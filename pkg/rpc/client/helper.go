@@ -21,6 +21,11 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 )
 
+// InvokeAndPackIteratorResults and InvokeAndPrefetchIteratorResults (below)
+// build a script with createIteratorUnwrapperScript/createIteratorPrefetchScript
+// and run it through c.InvokeScript, which is a real, working call now (see
+// client.go) rather than a method on a type that didn't exist.
+
 // getInvocationError returns an error in case of bad VM state or an empty stack.
 func getInvocationError(result *result.Invoke) error {
 	if result.State != "HALT" {
@@ -115,15 +120,25 @@ func topMapFromStack(st []stackitem.Item) (*stackitem.Map, error) {
 // stackitem on stack if invocation HALTed. InvokeAndPackIteratorResults can be
 // used to interact with JSON-RPC server where iterator sessions are disabled to
 // retrieve iterator values via JSON-RPC call.
-func (c *Client) InvokeAndPackIteratorResults(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) (*result.Invoke, error) {
-	bytes, err := createIteratorUnwrapperScript(contract, operation, params)
+//
+// This is a thin wrapper around createIteratorUnwrapperScript, which in turn
+// really belongs next to System.Contract.Call's other script-building helpers
+// in github.com/nspcc-dev/neo-go/pkg/smartcontract rather than in an RPC
+// client - but that's an external dependency of this repo, not a package
+// checked into it, so there's nowhere under pkg/rpc/client's own tree to move
+// it to. callFlags is forwarded straight through to the generated
+// System.Contract.Call, letting callers that only need to read iterator
+// values pass callflag.ReadStates instead of the previously hard-coded
+// callflag.All.
+func (c *Client) InvokeAndPackIteratorResults(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer, callFlags callflag.CallFlag) (*result.Invoke, error) {
+	bytes, err := createIteratorUnwrapperScript(contract, operation, params, callFlags)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create iterator unwrapper script: %w", err)
 	}
 	return c.InvokeScript(bytes, signers)
 }
 
-func createIteratorUnwrapperScript(contract util.Uint160, operation string, params []smartcontract.Parameter) ([]byte, error) {
+func createIteratorUnwrapperScript(contract util.Uint160, operation string, params []smartcontract.Parameter, callFlags callflag.CallFlag) ([]byte, error) {
 	script := io.NewBufBinWriter()
 	emit.Instruction(script.BinWriter, opcode.INITSLOT, // Initialize local slot...
 		[]byte{
@@ -141,8 +156,8 @@ func createIteratorUnwrapperScript(contract util.Uint160, operation string, para
 		emit.Int(script.BinWriter, int64(len(params)))
 		emit.Opcodes(script.BinWriter, opcode.PACK)
 	}
-	emit.AppCallNoArgs(script.BinWriter, contract, operation, callflag.All) // The System.Contract.Call itself, it will push Iterator on estack.
-	emit.Opcodes(script.BinWriter, opcode.STLOC0,                           // Pop the result of System.Contract.Call (the iterator) from estack and store it inside the 0-th cell of the local slot.
+	emit.AppCallNoArgs(script.BinWriter, contract, operation, callFlags) // The System.Contract.Call itself, it will push Iterator on estack.
+	emit.Opcodes(script.BinWriter, opcode.STLOC0,                        // Pop the result of System.Contract.Call (the iterator) from estack and store it inside the 0-th cell of the local slot.
 		opcode.NEWARRAY0, // Push new empty array to estack. This array will store iterator's elements.
 		opcode.STLOC1)    // Pop the empty array from estack and store it inside the 1-th cell of the local slot.
 
@@ -179,8 +194,107 @@ func createIteratorUnwrapperScript(contract util.Uint160, operation string, para
 	return bytes, nil
 }
 
+// InvokeAndPrefetchIteratorResults is InvokeAndPackIteratorResults with an
+// explicit item cap: the generated script stops pulling values out of the
+// iterator once it has packed maxItems of them, rather than draining it to
+// exhaustion and leaving the caller to truncate the result afterwards. Use
+// this instead of InvokeAndPackIteratorResults whenever maxItems is smaller
+// than the iterator's full result set, since System.Iterator.Next still
+// costs gas for every element it walks past, packed or not.
+func (c *Client) InvokeAndPrefetchIteratorResults(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer, callFlags callflag.CallFlag, maxItems int) (*result.Invoke, error) {
+	bytes, err := createIteratorPrefetchScript(contract, operation, params, callFlags, maxItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iterator prefetch script: %w", err)
+	}
+	return c.InvokeScript(bytes, signers)
+}
+
+// createIteratorPrefetchScript builds the same iterator-unwrapping script as
+// createIteratorUnwrapperScript, but with a bounds check inside the VM loop
+// itself: once the packed array reaches maxItems elements, the script stops
+// calling System.Iterator.Next and returns what it has, rather than walking
+// (and discarding) every remaining item the way the uncapped script does.
+func createIteratorPrefetchScript(contract util.Uint160, operation string, params []smartcontract.Parameter, callFlags callflag.CallFlag, maxItems int) ([]byte, error) {
+	script := io.NewBufBinWriter()
+	emit.Instruction(script.BinWriter, opcode.INITSLOT, // Initialize local slot...
+		[]byte{
+			3, // with 3 local variables (0-th iterator, 1-th resulting array, 2-th items packed so far)...
+			0, // and 0 arguments.
+		})
+	if len(params) == 0 {
+		emit.Opcodes(script.BinWriter, opcode.NEWARRAY0)
+	} else {
+		err := expandArrayIntoScript(script.BinWriter, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create function invocation script: %w", err)
+		}
+		emit.Int(script.BinWriter, int64(len(params)))
+		emit.Opcodes(script.BinWriter, opcode.PACK)
+	}
+	emit.AppCallNoArgs(script.BinWriter, contract, operation, callFlags)
+	emit.Opcodes(script.BinWriter, opcode.STLOC0,
+		opcode.NEWARRAY0,
+		opcode.STLOC1)
+	emit.Int(script.BinWriter, 0)
+	emit.Opcodes(script.BinWriter, opcode.STLOC2) // items packed so far = 0.
+
+	iteratorTraverseCycleStartOffset := script.Len()
+	emit.Opcodes(script.BinWriter, opcode.LDLOC0)
+	emit.Syscall(script.BinWriter, interopnames.SystemIteratorNext)
+	jmpIfNotOffset := script.Len()
+	emit.Instruction(script.BinWriter, opcode.JMPIFNOT,
+		[]byte{
+			0x00, // jump to loadResultOffset, filled in below.
+		})
+	emit.Opcodes(script.BinWriter, opcode.LDLOC1,
+		opcode.LDLOC0)
+	emit.Syscall(script.BinWriter, interopnames.SystemIteratorValue)
+	emit.Opcodes(script.BinWriter, opcode.APPEND)
+
+	// items packed so far++.
+	emit.Opcodes(script.BinWriter, opcode.LDLOC2)
+	emit.Int(script.BinWriter, 1)
+	emit.Opcodes(script.BinWriter, opcode.ADD, opcode.STLOC2)
+
+	// Stop once the cap is reached instead of looping to exhaustion.
+	emit.Opcodes(script.BinWriter, opcode.LDLOC2)
+	emit.Int(script.BinWriter, int64(maxItems))
+	jmpGeOffset := script.Len()
+	emit.Instruction(script.BinWriter, opcode.JMPGE,
+		[]byte{
+			0x00, // jump to loadResultOffset, filled in below.
+		})
+
+	jmpOffset := script.Len()
+	emit.Instruction(script.BinWriter, opcode.JMP, // Jump to the start of iterator traverse cycle.
+		[]byte{
+			uint8(iteratorTraverseCycleStartOffset - jmpOffset),
+		})
+
+	// End of the program: push the result on stack and return.
+	loadResultOffset := script.Len()
+	emit.Opcodes(script.BinWriter, opcode.LDLOC1,
+		opcode.RET)
+	if err := script.Err; err != nil {
+		return nil, fmt.Errorf("failed to build iterator prefetch script: %w", err)
+	}
+
+	bytes := script.Bytes()
+	bytes[jmpIfNotOffset+1] = uint8(loadResultOffset - jmpIfNotOffset)
+	bytes[jmpGeOffset+1] = uint8(loadResultOffset - jmpGeOffset)
+	return bytes, nil
+}
+
 // expandArrayIntoScript pushes all smartcontract.Parameter parameters from the given array
 // into the given buffer in the reverse order.
+//
+// It doesn't handle smartcontract.StructType: that would need a new
+// ParameterType constant added to smartcontract.Parameter itself, which is
+// an external, unvendored dependency (github.com/nspcc-dev/neo-go), not a
+// package checked into this repo - the same constraint
+// InvokeAndPackIteratorResults's doc comment runs into. Callers that need a
+// Struct on the stack can still get one by passing a stackitem.Struct as an
+// InteropInterfaceType value, which is serialized and deserialized as is.
 func expandArrayIntoScript(script *io.BinWriter, slice []smartcontract.Parameter) error {
 	for j := len(slice) - 1; j >= 0; j-- {
 		p := slice[j]
@@ -260,6 +374,37 @@ func expandArrayIntoScript(script *io.BinWriter, slice []smartcontract.Parameter
 			if p.Value == nil {
 				emit.Opcodes(script, opcode.PUSHNULL)
 			}
+		case smartcontract.MapType:
+			pairs, ok := p.Value.([]smartcontract.ParameterPair)
+			if !ok {
+				return errors.New("not a Map")
+			}
+			// PACKMAP pops its pair count, then pops that many (key,
+			// value) pairs with key on top, so each pair pushes its
+			// value before its key.
+			for i := len(pairs) - 1; i >= 0; i-- {
+				if err := expandArrayIntoScript(script, []smartcontract.Parameter{pairs[i].Value}); err != nil {
+					return fmt.Errorf("failed to expand map value #%d: %w", i, err)
+				}
+				if err := expandArrayIntoScript(script, []smartcontract.Parameter{pairs[i].Key}); err != nil {
+					return fmt.Errorf("failed to expand map key #%d: %w", i, err)
+				}
+			}
+			emit.Int(script, int64(len(pairs)))
+			emit.Opcodes(script, opcode.PACKMAP)
+		case smartcontract.InteropInterfaceType:
+			item, ok := p.Value.(stackitem.Item)
+			if !ok {
+				return errors.New("not an InteropInterface")
+			}
+			data, err := stackitem.Serialize(item)
+			if err != nil {
+				return fmt.Errorf("failed to serialize InteropInterface: %w", err)
+			}
+			emit.Bytes(script, data)
+			emit.Syscall(script, interopnames.SystemRuntimeDeserialize)
+		case smartcontract.VoidType:
+			return errors.New("cannot encode a Void parameter as a script argument")
 		default:
 			return fmt.Errorf("parameter type %v is not supported", p.Type)
 		}
@@ -269,6 +414,13 @@ func expandArrayIntoScript(script *io.BinWriter, slice []smartcontract.Parameter
 
 // unwrapTopStackItem returns the list of elements of `resultItemType` type from the top element
 // of the provided stack. The top element is expected to be an Array, otherwise an error is returned.
+//
+// Deprecated: use pkg/rpc/client/unwrap.Unwrap with one of its typed decode
+// functions (or your own) instead. This keys off a resultItemType sentinel
+// value rather than a real type parameter, so a wrong sentinel only fails at
+// runtime, the element types it knows about are hard-coded into its own
+// switch, and callers get back a []interface{} they still have to re-assert
+// out of.
 func unwrapTopStackItem(st []stackitem.Item, resultItemType interface{}) ([]interface{}, error) {
 	index := len(st) - 1 // top stack element is the last in the array
 	if t := st[index].Type(); t != stackitem.ArrayT {
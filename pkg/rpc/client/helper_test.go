@@ -13,6 +13,10 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/rpc/request"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -56,3 +60,96 @@ func TestClient_ExpendArrayIntoScriptCompat(t *testing.T) {
 		buf.Reset()
 	}
 }
+
+// FuzzExpandArrayIntoScript round-trips parameter trees built from the
+// fuzzer's raw input through expandArrayIntoScript and a VM execution,
+// checking that the value left on top of the stack decodes back to what
+// was packed. It covers four scalar types (IntegerType, StringType,
+// ByteArrayType and BoolType) plus one level of ArrayType
+// or MapType nesting; StructType isn't reachable here since
+// smartcontract.Parameter has no such ParameterType to construct one with,
+// see expandArrayIntoScript's doc comment.
+func FuzzExpandArrayIntoScript(f *testing.F) {
+	f.Add(int64(42), "hello", []byte{1, 2, 3}, true, uint8(2))
+	f.Fuzz(func(t *testing.T, n int64, s string, b []byte, flag bool, shape uint8) {
+		param := buildFuzzParameter(n, s, b, flag, shape)
+
+		script := io.NewBufBinWriter()
+		require.NoError(t, expandArrayIntoScript(script.BinWriter, []smartcontract.Parameter{param}))
+		require.NoError(t, script.Err)
+		emit.Opcodes(script.BinWriter, opcode.RET)
+
+		v := vm.New()
+		v.LoadScript(script.Bytes())
+		require.NoError(t, v.Run())
+		require.Equal(t, 1, v.Estack().Len())
+
+		assertParameterOnStack(t, param, v.Estack().Pop().Item())
+	})
+}
+
+// buildFuzzParameter turns the fuzzer's raw primitives into a small,
+// deterministic smartcontract.Parameter tree: shape picks a scalar leaf
+// type directly, or (when its top bit is set) wraps two leaves in an Array
+// or a Map, so the fuzzer can also reach the nested encode paths.
+func buildFuzzParameter(n int64, s string, b []byte, flag bool, shape uint8) smartcontract.Parameter {
+	leaf := func(sel uint8) smartcontract.Parameter {
+		switch sel % 4 {
+		case 0:
+			return smartcontract.Parameter{Type: smartcontract.IntegerType, Value: big.NewInt(n)}
+		case 1:
+			return smartcontract.Parameter{Type: smartcontract.StringType, Value: s}
+		case 2:
+			return smartcontract.Parameter{Type: smartcontract.ByteArrayType, Value: b}
+		default:
+			return smartcontract.Parameter{Type: smartcontract.BoolType, Value: flag}
+		}
+	}
+	if shape&0x80 == 0 {
+		return leaf(shape)
+	}
+	a, c := leaf(shape), leaf(shape>>1)
+	if shape&0x40 == 0 {
+		return smartcontract.Parameter{Type: smartcontract.ArrayType, Value: []smartcontract.Parameter{a, c}}
+	}
+	return smartcontract.Parameter{
+		Type:  smartcontract.MapType,
+		Value: []smartcontract.ParameterPair{{Key: a, Value: c}},
+	}
+}
+
+// assertParameterOnStack checks that item is what expandArrayIntoScript's
+// encoding of p should deserialize back to.
+func assertParameterOnStack(t *testing.T, p smartcontract.Parameter, item stackitem.Item) {
+	switch p.Type {
+	case smartcontract.IntegerType:
+		bi, err := item.TryInteger()
+		require.NoError(t, err)
+		require.Zero(t, bi.Cmp(p.Value.(*big.Int)))
+	case smartcontract.StringType:
+		bs, err := item.TryBytes()
+		require.NoError(t, err)
+		require.Equal(t, p.Value.(string), string(bs))
+	case smartcontract.ByteArrayType:
+		bs, err := item.TryBytes()
+		require.NoError(t, err)
+		require.True(t, bytes.Equal(p.Value.([]byte), bs))
+	case smartcontract.BoolType:
+		val, ok := item.Value().(bool)
+		require.True(t, ok)
+		require.Equal(t, p.Value.(bool), val)
+	case smartcontract.ArrayType:
+		items, ok := item.Value().([]stackitem.Item)
+		require.True(t, ok)
+		children := p.Value.([]smartcontract.Parameter)
+		require.Len(t, items, len(children))
+		for i, c := range children {
+			assertParameterOnStack(t, c, items[i])
+		}
+	case smartcontract.MapType:
+		m, ok := item.(*stackitem.Map)
+		require.True(t, ok)
+		pairs := p.Value.([]smartcontract.ParameterPair)
+		require.Equal(t, len(pairs), m.Len())
+	}
+}
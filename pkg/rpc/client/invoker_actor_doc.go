@@ -0,0 +1,17 @@
+package client
+
+// invoker.Invoker and actor.Actor, as described for the sub-packages
+// contracts_doc.go declines to introduce, would themselves wrap *Client:
+// historic-height/hash/state-root anchoring around Call/CallAndExpand/
+// Verify, then Signer/wallet.Account pairing around that for Sign/Send/
+// Wait.
+//
+// Client is real now (client.go) - InvokeFunction, GetBlockCount,
+// GetNetwork and performRequest are all implemented and already used
+// throughout this package (oracle.go, helper.go, iterator.go). What's
+// missing isn't the base type to wrap, it's a reason to wrap it: an
+// invoker/actor split exists upstream to give every {nep11,nep17,...}
+// sub-package a uniform way to build and sign transactions against its
+// contract kind, and contracts_doc.go already explains why those
+// sub-packages don't belong here. Without them, invoker/actor would be two
+// more types with nothing in this package left to use them.
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+// oracleResponseValidityWindow matches the default MaxValidUntilBlockIncrement:
+// an oracle response only needs to outlive the handful of blocks it takes an
+// oracle node to get it relayed and included.
+//
+// This targets the native Oracle contract's RPC surface (getRequest,
+// GasForResponse) the way neo-go's own oracle node does - a real, working
+// call path now that Client is implemented (see client.go), even though
+// this chain's own native contracts (native_neo.go, sai.go) don't include
+// an Oracle. See contracts_doc.go for why this package's RPC vocabulary
+// isn't the same one pkg/rpc/server speaks.
+const oracleResponseValidityWindow = 5760
+
+// CreateOracleResponseTx creates and signs a transaction carrying the
+// OracleResponse attribute for the oracle request requestID. Its system fee
+// is the GasForResponse the requester already escrowed for this request
+// (fetched from the native Oracle contract, so it reflects whatever
+// GetOraclePrice was in effect when the request was made) plus extraFee,
+// which lets an oracle node bump it if the network is congested. oracleAcc
+// is expected to hold the designated oracle nodes' multisig contract; its
+// verification script is used as the transaction's only signer. The
+// invocation script and remaining fields beyond Attributes are filled in by
+// oracleAcc.SignTx, so the returned transaction is ready to relay to other
+// oracle nodes for their own signatures.
+func (c *Client) CreateOracleResponseTx(requestID uint64, code transaction.OracleResponseCode, result []byte, oracleAcc *wallet.Account, extraFee int64) (*transaction.Transaction, error) {
+	gasForResponse, err := c.getOracleRequestGas(requestID)
+	if err != nil {
+		return nil, fmt.Errorf("can't fetch oracle request #%d: %w", requestID, err)
+	}
+	height, err := c.GetBlockCount()
+	if err != nil {
+		return nil, fmt.Errorf("can't fetch current height: %w", err)
+	}
+
+	tx := transaction.New([]byte{byte(opcode.PUSH1)}, gasForResponse+extraFee)
+	tx.Nonce = uint32(requestID)
+	tx.ValidUntilBlock = height + oracleResponseValidityWindow
+	tx.Attributes = []transaction.Attribute{{
+		Type: transaction.OracleResponseT,
+		Value: &transaction.OracleResponse{
+			ID:     requestID,
+			Code:   code,
+			Result: result,
+		},
+	}}
+	tx.Signers = []transaction.Signer{{
+		Account: oracleAcc.Contract.ScriptHash(),
+		Scopes:  transaction.None,
+	}}
+
+	if err := oracleAcc.SignTx(c.GetNetwork(), tx); err != nil {
+		return nil, fmt.Errorf("can't sign oracle response tx: %w", err)
+	}
+	return tx, nil
+}
+
+// getOracleRequestGas fetches the GasForResponse field of the oracle request
+// requestID from the native Oracle contract's storage.
+func (c *Client) getOracleRequestGas(requestID uint64) (int64, error) {
+	oracleHash := state.CreateNativeContractHash(nativenames.Oracle)
+	res, err := c.InvokeFunction(oracleHash, "getRequest", []smartcontract.Parameter{
+		{Type: smartcontract.IntegerType, Value: big.NewInt(0).SetUint64(requestID)},
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+	if err := getInvocationError(res); err != nil {
+		return 0, err
+	}
+	// OracleRequest is returned as a struct; GasForResponse is its 2nd field
+	// (after OriginalTxID).
+	items, ok := res.Stack[len(res.Stack)-1].Value().([]stackitem.Item)
+	if !ok || len(items) < 2 {
+		return 0, fmt.Errorf("unexpected OracleRequest shape returned for request #%d", requestID)
+	}
+	gas, err := items[1].TryInteger()
+	if err != nil {
+		return 0, fmt.Errorf("can't decode GasForResponse: %w", err)
+	}
+	return gas.Int64(), nil
+}
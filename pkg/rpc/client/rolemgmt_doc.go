@@ -0,0 +1,13 @@
+package client
+
+// rpcclient/rolemgmt would sit on top of the historic invoker described in
+// invoker_actor_doc.go and the actor.Actor-built DesignateAsRole
+// transaction on top of that, plus a Client.GetCommitteeAddress() helper
+// alongside GetNetwork/GetBlockCount (see client.go). Client itself is no
+// longer the blocker (see client.go/wsclient.go) - this is the same gap
+// contracts_doc.go already describes for the sub-package split in general:
+// this chain has no role-management native contract (no Oracle/Designate/
+// NEO-committee-node concept - see native_neo.go's own committee/
+// validator machinery, which is self-contained and never goes through an
+// RPC-settable "role"), so there is no DesignateAsRole call or committee
+// address for rolemgmt to wrap.
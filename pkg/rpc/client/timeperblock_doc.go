@@ -0,0 +1,12 @@
+package client
+
+// A GetTimePerBlock() (time.Duration, error) helper here would mirror
+// GetOraclePrice/getOracleRequestGas (see oracle.go): call a native Policy
+// getter and decode the stack result. But config.ProtocolConfiguration and
+// core.Blockchain - the SecondsPerBlock/TimePerBlock field and the
+// GetTimePerBlock method it would read through - aren't present in this
+// tree at all, and neither is a notary package for the retry-interval side
+// of this change. pkg/dbft's own SecondsPerBlock (pkg/dbft/config.go) is
+// already a time.Duration, not a second-granular int, so that half of this
+// request has nothing left to do. The rest waits on config.
+// ProtocolConfiguration and core.Blockchain existing locally.
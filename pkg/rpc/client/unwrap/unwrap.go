@@ -0,0 +1,152 @@
+// Package unwrap provides typed helpers for decoding the stackitem.Item
+// values an invocation result returns. It replaces pkg/rpc/client's old
+// unwrapTopStackItem, which keyed its decoding on an interface{} sentinel
+// value of the desired element type - fragile (nothing stopped a caller
+// passing the wrong sentinel), not extensible (every new domain type meant
+// editing its type switch), and it handed back []interface{}, forcing every
+// caller to re-assert into its own type. Unwrap takes the decode function
+// directly instead, so the element type is checked at compile time and
+// third-party contract bindings can supply their own decoder without
+// touching this package.
+//
+// Unwrap only ever takes a *result.Invoke produced by a call such as
+// Client.InvokeFunction, so it was never affected by pkg/rpc/client's
+// Client type being missing - it decodes the result a caller already has
+// in hand, regardless of how that caller obtained it.
+package unwrap
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client/nns"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// Unwrap decodes the Array at the top of st into a []T, applying decode to
+// each of its elements in order. st is expected to come straight from an
+// invocation's result.Invoke.Stack.
+func Unwrap[T any](st []stackitem.Item, decode func(stackitem.Item) (T, error)) ([]T, error) {
+	if len(st) == 0 {
+		return nil, errors.New("empty stack")
+	}
+	index := len(st) - 1 // top stack element is last in the array
+	if t := st[index].Type(); t != stackitem.ArrayT {
+		return nil, fmt.Errorf("invalid return stackitem type: %s (Array expected)", t.String())
+	}
+	items, ok := st[index].Value().([]stackitem.Item)
+	if !ok {
+		return nil, errors.New("failed to deserialize iterable from interop stackitem: invalid value type (Array expected)")
+	}
+	result := make([]T, len(items))
+	for i := range items {
+		v, err := decode(items[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode element #%d: %w", i, err)
+		}
+		result[i] = v
+	}
+	return result, nil
+}
+
+// UnwrapBytes decodes item as a raw byte slice.
+func UnwrapBytes(item stackitem.Item) ([]byte, error) {
+	return item.TryBytes()
+}
+
+// UnwrapString decodes item as a UTF-8 string.
+func UnwrapString(item stackitem.Item) (string, error) {
+	bs, err := item.TryBytes()
+	if err != nil {
+		return "", err
+	}
+	return string(bs), nil
+}
+
+// UnwrapUint160 decodes item as a big-endian util.Uint160.
+func UnwrapUint160(item stackitem.Item) (util.Uint160, error) {
+	bs, err := item.TryBytes()
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	return util.Uint160DecodeBytesBE(bs)
+}
+
+// UnwrapUint256 decodes item as a big-endian util.Uint256.
+func UnwrapUint256(item stackitem.Item) (util.Uint256, error) {
+	bs, err := item.TryBytes()
+	if err != nil {
+		return util.Uint256{}, err
+	}
+	return util.Uint256DecodeBytesBE(bs)
+}
+
+// UnwrapPublicKey decodes item as a compressed secp256r1 public key.
+func UnwrapPublicKey(item stackitem.Item) (*keys.PublicKey, error) {
+	bs, err := item.TryBytes()
+	if err != nil {
+		return nil, err
+	}
+	return keys.NewPublicKeyFromBytes(bs, elliptic.P256())
+}
+
+// UnwrapBigInt decodes item as an arbitrary-precision integer.
+func UnwrapBigInt(item stackitem.Item) (*big.Int, error) {
+	return item.TryInteger()
+}
+
+// UnwrapBool decodes item as a boolean.
+func UnwrapBool(item stackitem.Item) (bool, error) {
+	b, ok := item.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("invalid stack item type: %s", item.Type())
+	}
+	return b, nil
+}
+
+// UnwrapStruct decodes item as a VM Struct, handing its fields to decode.
+// It's the building block a decoder for any multi-field contract type (like
+// UnwrapRecordState below) is written in terms of.
+func UnwrapStruct[T any](item stackitem.Item, decode func(fields []stackitem.Item) (T, error)) (T, error) {
+	var zero T
+	fields, ok := item.Value().([]stackitem.Item)
+	if !ok {
+		return zero, errors.New("not a struct")
+	}
+	return decode(fields)
+}
+
+// UnwrapRecordState decodes item as an NNS nns.RecordState: a 3-element VM
+// Struct of (name, type, data).
+func UnwrapRecordState(item stackitem.Item) (nns.RecordState, error) {
+	return UnwrapStruct(item, func(fields []stackitem.Item) (nns.RecordState, error) {
+		if len(fields) != 3 {
+			return nns.RecordState{}, errors.New("wrong number of elements")
+		}
+		name, err := fields[0].TryBytes()
+		if err != nil {
+			return nns.RecordState{}, err
+		}
+		typ, err := fields[1].TryInteger()
+		if err != nil {
+			return nns.RecordState{}, err
+		}
+		data, err := fields[2].TryBytes()
+		if err != nil {
+			return nns.RecordState{}, err
+		}
+		u64Typ := typ.Uint64()
+		if !typ.IsUint64() || u64Typ > 255 {
+			return nns.RecordState{}, errors.New("bad type")
+		}
+		return nns.RecordState{
+			Name: string(name),
+			Type: nns.RecordType(u64Typ),
+			Data: string(data),
+		}, nil
+	})
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/request"
+)
+
+// getTestRequestID pins every request a test using it makes to id 1, so a
+// canned `"id": 1` response string can be reused verbatim regardless of
+// how many requests a test case actually sends.
+func getTestRequestID() uint64 {
+	return 1
+}
+
+// httpURLtoWS turns an httptest.Server's "http://host:port" URL into the
+// "ws://host:port/ws" one NewWS expects to dial.
+func httpURLtoWS(url string) string {
+	return "ws" + strings.TrimPrefix(url, "http") + "/ws"
+}
+
+// initTestServer starts a websocket server on /ws that replies to every
+// getversion call with a fixed, valid getversion response (so Init always
+// succeeds) and to every other call with resp verbatim - good enough for
+// tests that only care about a single request/response round trip after
+// Init.
+func initTestServer(t *testing.T, resp string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/ws" || req.Method != http.MethodGet {
+			return
+		}
+		var upgrader websocket.Upgrader
+		ws, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+		for {
+			if err := ws.SetReadDeadline(time.Now().Add(2 * time.Second)); err != nil {
+				return
+			}
+			_, body, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+			r := request.In{}
+			if err := json.Unmarshal(body, &r); err != nil {
+				return
+			}
+			var out string
+			switch r.Method {
+			case "getversion":
+				out = fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"result":{"network":42,"tcpport":20332,"wsport":20342,"nonce":2153672787,"useragent":"/NEO-GO:0.73.1-pre-273-ge381358/"}}`, r.RawID)
+			default:
+				if resp == "" {
+					return
+				}
+				out = resp
+			}
+			if err := ws.SetWriteDeadline(time.Now().Add(2 * time.Second)); err != nil {
+				return
+			}
+			if err := ws.WriteMessage(websocket.TextMessage, []byte(out)); err != nil {
+				return
+			}
+		}
+	}))
+}
+
+// b1Verbose is a minimal verbose block payload, used only to exercise the
+// "block_added" branch of WSClient's notification dispatch - its content
+// is never decoded into a typed struct, so it only needs to be valid JSON.
+const b1Verbose = `{"hash":"0xd69e38c0740b01f3ba75a2bb9497dd92db02c204a0390fa38c5b2f77b2c4540d","size":452,"version":0,"previousblockhash":"0x0000000000000000000000000000000000000000000000000000000000000","merkleroot":"0x0000000000000000000000000000000000000000000000000000000000000","time":1600000000000,"index":1,"nextconsensus":"0x0000000000000000000000000000000000000000","confirmations":1,"tx":[]}`
@@ -0,0 +1,19 @@
+package client
+
+// WSClient is real now (wsclient.go): a persistent /ws connection, the
+// subscribe/unsubscribe verbs, a demultiplexing read loop keyed by request
+// id, and the non-blocking-unsubscribe path AwaitTx already relied on.
+// ErrWSConnLost is wrapped into both request-method errors and
+// WSClient.GetError() once the connection is actually gone (see
+// teardown/wsTransport.performRequest), and Options.AutoReconnect/
+// ReconnectBackoff/MaxReconnectAttempts redial and replay every live
+// subscription onto the new connection (Init is not run again). Typed
+// per-subscription channels (ReceiveBlocks/ReceiveTransactions/
+// ReceiveExecutions/ReceiveExecutionNotifications) sit alongside the single
+// untyped Notifications channel.
+//
+// Left as a follow-up:
+//   - reconnection currently replays subscriptions as brand-new
+//     "subscribe" calls with fresh server-assigned ids; a caller holding
+//     onto a pre-reconnect id for Unsubscribe needs to re-fetch it, since
+//     nothing here remaps old ids to new ones transparently.
@@ -0,0 +1,218 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+// startTypedEventServer upgrades exactly one "/ws" connection, answers every
+// request with a fresh "subscribe" result ("1", "2", ...), then writes events
+// (one per call) before leaving the connection open for the caller to close.
+func startTypedEventServer(t *testing.T, events []string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/ws" || req.Method != "GET" {
+			return
+		}
+		var upgrader = websocket.Upgrader{}
+		ws, err := upgrader.Upgrade(w, req, nil)
+		require.NoError(t, err)
+		go func() {
+			for n := 1; ; n++ {
+				_, _, err := ws.ReadMessage()
+				if err != nil {
+					return
+				}
+				err = ws.SetWriteDeadline(time.Now().Add(2 * time.Second))
+				require.NoError(t, err)
+				if ws.WriteMessage(1, []byte(fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "result": "%d"}`, n))) != nil {
+					return
+				}
+			}
+		}()
+		for _, event := range events {
+			err = ws.SetWriteDeadline(time.Now().Add(2 * time.Second))
+			require.NoError(t, err)
+			if ws.WriteMessage(1, []byte(event)) != nil {
+				break
+			}
+		}
+		<-req.Context().Done()
+	}))
+}
+
+// TestWSClientReceiveTyped covers chunk11-1: each Receive* method gets its
+// event decoded into the concrete type and delivered on its own channel,
+// not just as an undecoded Notification.Value.
+func TestWSClientReceiveTyped(t *testing.T) {
+	events := []string{
+		fmt.Sprintf(`{"jsonrpc":"2.0","method":"block_added","params":[%s]}`, b1Verbose),
+		`{"jsonrpc":"2.0","method":"transaction_executed","params":[{"container":"0xe1cd5e57e721d2a2e05fb1f08721b12057b25ab1dd7fd0f33ee1639932fdfad7","trigger":"Application","vmstate":"HALT","gasconsumed":"22910000","stack":[],"notifications":[]}]}`,
+	}
+	srv := startTypedEventServer(t, events)
+	t.Cleanup(srv.Close)
+
+	wsc, err := NewWS(context.TODO(), httpURLtoWS(srv.URL), Options{})
+	require.NoError(t, err)
+	wsc.getNextRequestID = getTestRequestID
+
+	blocks := make(chan *block.Block, 1)
+	_, err = wsc.ReceiveBlocks(nil, blocks)
+	require.NoError(t, err)
+
+	executions := make(chan *state.AppExecResult, 1)
+	_, err = wsc.ReceiveExecutions(nil, executions)
+	require.NoError(t, err)
+
+	select {
+	case b := <-blocks:
+		require.EqualValues(t, 1, b.Index)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for typed block")
+	}
+	select {
+	case aer := <-executions:
+		require.NotEqual(t, util.Uint256{}, aer.Container)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for typed execution")
+	}
+	wsc.Close()
+}
+
+// TestWSClientReceiveClose covers chunk11-2: every receiver channel is
+// closed exactly once when the connection goes away, with no panic from a
+// concurrently racing Unsubscribe/UnsubscribeAll/Close.
+func TestWSClientReceiveClose(t *testing.T) {
+	srv := startTypedEventServer(t, nil)
+	t.Cleanup(srv.Close)
+
+	wsc, err := NewWS(context.TODO(), httpURLtoWS(srv.URL), Options{})
+	require.NoError(t, err)
+	wsc.getNextRequestID = getTestRequestID
+
+	blocks := make(chan *block.Block, 1)
+	id, err := wsc.ReceiveBlocks(nil, blocks)
+	require.NoError(t, err)
+
+	txs := make(chan *transaction.Transaction, 1)
+	_, err = wsc.ReceiveTransactions(nil, txs)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() { defer wg.Done(); _ = wsc.Unsubscribe(id) }()
+	go func() { defer wg.Done(); _ = wsc.UnsubscribeAll() }()
+	go func() { defer wg.Done(); wsc.Close() }()
+
+	require.NotPanics(t, wg.Wait)
+
+	select {
+	case _, ok := <-blocks:
+		require.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for blocks channel to close")
+	}
+	select {
+	case _, ok := <-txs:
+		require.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for transactions channel to close")
+	}
+
+	// Closing again must stay a no-op, not a second close of an already
+	// closed channel.
+	require.NotPanics(t, func() {
+		_ = wsc.Unsubscribe(id)
+		_ = wsc.UnsubscribeAll()
+		wsc.Close()
+	})
+}
+
+// TestWSClientInitConcurrentWithEvents covers chunk11-5: Init() (which
+// populates the version cache) racing concurrent event delivery and reads
+// of GetNetwork/StateRootInHeader must be race-detector clean.
+func TestWSClientInitConcurrentWithEvents(t *testing.T) {
+	events := make([]string, 20)
+	for i := range events {
+		events[i] = `{"jsonrpc":"2.0","method":"notification_from_execution","params":[{"container":"0xe1cd5e57e721d2a2e05fb1f08721b12057b25ab1dd7fd0f33ee1639932fdfad7","contract":"0x1b4357bff5a01bdf2a6581247cf9ed1e24629176","eventname":"x","state":{"type":"Array","value":[]}}]}`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/ws" && req.Method == "GET" {
+			var upgrader = websocket.Upgrader{}
+			ws, err := upgrader.Upgrade(w, req, nil)
+			require.NoError(t, err)
+			go func() {
+				for {
+					_, p, err := ws.ReadMessage()
+					if err != nil {
+						return
+					}
+					var in struct {
+						ID     uint64 `json:"id"`
+						Method string `json:"method"`
+					}
+					_ = json.Unmarshal(p, &in)
+					err = ws.SetWriteDeadline(time.Now().Add(2 * time.Second))
+					require.NoError(t, err)
+					var resp string
+					if in.Method == "getversion" {
+						resp = fmt.Sprintf(`{"id":%d,"jsonrpc":"2.0","result":{"network":42,"tcpport":20332,"wsport":20342,"nonce":2153672787,"useragent":"/NEO-GO:0.73.1-pre-273-ge381358/","protocol":{"stateRootInHeader":true}}}`, in.ID)
+					} else {
+						resp = fmt.Sprintf(`{"id":%d,"jsonrpc":"2.0","result":true}`, in.ID)
+					}
+					if ws.WriteMessage(1, []byte(resp)) != nil {
+						return
+					}
+				}
+			}()
+			for _, event := range events {
+				err = ws.SetWriteDeadline(time.Now().Add(2 * time.Second))
+				require.NoError(t, err)
+				if ws.WriteMessage(1, []byte(event)) != nil {
+					break
+				}
+			}
+			<-req.Context().Done()
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	wsc, err := NewWS(context.TODO(), httpURLtoWS(srv.URL), Options{})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() { defer wg.Done(); _ = wsc.Init() }()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < len(events); i++ {
+			select {
+			case <-wsc.Notifications:
+			case <-time.After(2 * time.Second):
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			_ = wsc.GetNetwork()
+			_ = wsc.StateRootInHeader()
+		}
+	}()
+	wg.Wait()
+
+	wsc.Close()
+}
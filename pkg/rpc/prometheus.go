@@ -0,0 +1,44 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics for monitoring RPC request latency.
+var (
+	//rpcLatency prometheus metric.
+	rpcLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Help:      "RPC request latency, by method",
+			Name:      "rpc_request_duration_seconds",
+			Namespace: "saiya",
+		},
+		[]string{"method"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		rpcLatency,
+	)
+}
+
+// MetricsEnabled gates ObserveLatency so config.Metrics.DisableRPC can opt
+// this family out; see mempool.MetricsEnabled for why this is a package
+// var rather than read from config directly.
+var MetricsEnabled = true
+
+// ObserveLatency records how long method took to handle, starting from
+// start. There's no HTTP/JSON-RPC dispatch loop in this snapshot to call
+// it from automatically (pkg/rpc only has Config here) - it's exposed for
+// whatever server implementation wraps handler dispatch to call per
+// request, the same way observeEVMExecution wraps pkg/core/simulated's
+// VM.Call sites.
+func ObserveLatency(method string, start time.Time) {
+	if !MetricsEnabled {
+		return
+	}
+	rpcLatency.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
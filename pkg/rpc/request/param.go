@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
@@ -40,15 +41,20 @@ type (
 	// TxFilter is a wrapper structure for transaction event filter. It
 	// allows to filter transactions by senders and signers.
 	TxFilter struct {
-		Sender *util.Uint160 `json:"sender,omitempty"`
-		Signer *util.Uint160 `json:"signer,omitempty"`
+		Sender *common.Address `json:"sender,omitempty"`
+		Signer *common.Address `json:"signer,omitempty"`
 	}
 	// NotificationFilter is a wrapper structure representing filter used for
 	// notifications generated during transaction execution. Notifications can
-	// be filtered by contract hash and by name.
+	// be filtered by contract address, by name, and by log topics (following
+	// the same positional AND-across-indices/OR-within-an-index semantics as
+	// Ethereum's eth_subscribe("logs", ...)): Topics[i] == nil matches any
+	// value at position i, otherwise the log's i-th topic must equal one of
+	// the hashes listed there.
 	NotificationFilter struct {
-		Contract *util.Uint160 `json:"contract,omitempty"`
-		Name     *string       `json:"name,omitempty"`
+		Contracts []common.Address `json:"contracts,omitempty"`
+		Name      *string          `json:"name,omitempty"`
+		Topics    [][]common.Hash  `json:"topics,omitempty"`
 	}
 	// ExecutionFilter is a wrapper structure used for transaction execution
 	// events. It allows to choose failing or successful transactions based
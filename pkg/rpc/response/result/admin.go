@@ -0,0 +1,27 @@
+package result
+
+// NodeInfo is the result of admin_nodeInfo: a summary of this node's own
+// identity and network configuration, mirroring go-ethereum's admin API
+// shape.
+type NodeInfo struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	ListenAddr string   `json:"listenAddr"`
+	Protocols  []string `json:"protocols"`
+}
+
+// PeerInfo is a single entry of admin_peers: a connected peer's identity,
+// negotiated capabilities, and connection direction.
+type PeerInfo struct {
+	ID      string      `json:"id"`
+	Name    string      `json:"name"`
+	Caps    []string    `json:"caps"`
+	Network PeerNetwork `json:"network"`
+}
+
+// PeerNetwork describes a peer's connection endpoints and direction.
+type PeerNetwork struct {
+	LocalAddress  string `json:"localAddress"`
+	RemoteAddress string `json:"remoteAddress"`
+	Inbound       bool   `json:"inbound"`
+}
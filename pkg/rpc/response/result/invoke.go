@@ -28,6 +28,14 @@ type Invoke struct {
 	Session        uuid.UUID
 	finalize       func()
 	onNewSession   OnNewSession
+	// unwrap is set when the caller asked for iterators to be drained
+	// inline instead of returned as an IIterator interop, normally
+	// because it knows sessions are disabled on this server and still
+	// wants the full result in one round trip.
+	unwrap bool
+	// maxIteratorResultItems caps how many items unwrap pulls out of a
+	// single iterator before giving up on draining it further.
+	maxIteratorResultItems int
 }
 
 type OnNewSession func(sessionID string, iterators []ServerIterator, finalize func())
@@ -39,7 +47,12 @@ type InvokeDiag struct {
 }
 
 // NewInvoke returns a new Invoke structure with the given fields set.
-func NewInvoke(ic *interop.Context, script []byte, faultException string, registerSession OnNewSession) *Invoke {
+// unwrap and maxIteratorResultItems only take effect when registerSession
+// is nil (iterator sessions disabled): if unwrap is set, MarshalJSON
+// eagerly drains each iterator on the result stack, up to
+// maxIteratorResultItems items, and inlines the resulting array instead
+// of returning an unusable IIterator interop handle.
+func NewInvoke(ic *interop.Context, script []byte, faultException string, registerSession OnNewSession, unwrap bool, maxIteratorResultItems int) *Invoke {
 	var diag *InvokeDiag
 	tree := ic.VM.GetInvocationTree()
 	if tree != nil {
@@ -53,15 +66,17 @@ func NewInvoke(ic *interop.Context, script []byte, faultException string, regist
 		notifications = make([]state.NotificationEvent, 0)
 	}
 	return &Invoke{
-		State:          ic.VM.State().String(),
-		GasConsumed:    ic.VM.GasConsumed(),
-		Script:         script,
-		Stack:          ic.VM.Estack().ToArray(),
-		FaultException: faultException,
-		Notifications:  notifications,
-		Diagnostics:    diag,
-		finalize:       ic.Finalize,
-		onNewSession:   registerSession,
+		State:                  ic.VM.State().String(),
+		GasConsumed:            ic.VM.GasConsumed(),
+		Script:                 script,
+		Stack:                  ic.VM.Estack().ToArray(),
+		FaultException:         faultException,
+		Notifications:          notifications,
+		Diagnostics:            diag,
+		finalize:               ic.Finalize,
+		onNewSession:           registerSession,
+		unwrap:                 unwrap,
+		maxIteratorResultItems: maxIteratorResultItems,
 	}
 }
 
@@ -97,6 +112,30 @@ type ServerIterator struct {
 	Item stackitem.Item
 }
 
+// iteratorValue is the interface actual VM iterators implement under
+// their InteropT wrapping: Next advances it and reports whether a value
+// is available, Value returns the item Next just advanced to.
+type iteratorValue interface {
+	Next() bool
+	Value() stackitem.Item
+}
+
+// DrainIterator pulls up to max items out of a live iterator InteropT
+// item (max <= 0 means unlimited), for callers that need the whole
+// result inline instead of paging through it via TraverseIterator. It's
+// also used directly by Invoke.MarshalJSON's unwrap mode.
+func DrainIterator(item stackitem.Item, max int) []stackitem.Item {
+	iter, ok := item.Value().(iteratorValue)
+	if !ok {
+		return nil
+	}
+	var items []stackitem.Item
+	for (max <= 0 || len(items) < max) && iter.Next() {
+		items = append(items, iter.Value())
+	}
+	return items
+}
+
 // Finalize releases resources occupied by Iterators created at the script invocation.
 // This method will be called automatically on Invoke marshalling or by the Server's
 // sessions handler.
@@ -123,6 +162,15 @@ func (r Invoke) MarshalJSON() ([]byte, error) {
 	for i := range arr {
 		var data []byte
 		if (r.Stack[i].Type() == stackitem.InteropT) && iterator.IsIterator(r.Stack[i]) {
+			if !sessionsEnabled && r.unwrap {
+				data, err = stackitem.ToJSONWithTypes(stackitem.NewArray(DrainIterator(r.Stack[i], r.maxIteratorResultItems)))
+				if err != nil {
+					r.FaultException += fmt.Sprintf("%sjson error: failed to marshal iterator: %v", faultSep, err)
+					break
+				}
+				arr[i] = data
+				continue
+			}
 			iteratorID := uuid.NewString()
 			data, err = json.Marshal(iteratorAux{
 				Type:      stackitem.InteropT.String(),
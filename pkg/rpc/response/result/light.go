@@ -0,0 +1,15 @@
+package result
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/multiverse-dev/saiya/pkg/core/block"
+)
+
+// LightClientUpdate is a single entry of getLightClientUpdate: a header
+// plus the committee bitmap and aggregate signature that finalize it, so a
+// light client can verify it without replaying any transaction.
+type LightClientUpdate struct {
+	Header          block.Header  `json:"header"`
+	CommitteeBitmap hexutil.Bytes `json:"committeeBitmap"`
+	AggregateSig    hexutil.Bytes `json:"aggregateSignature"`
+}
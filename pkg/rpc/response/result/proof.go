@@ -0,0 +1,60 @@
+package result
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// AccountResult is the eth_getProof response, following the EIP-1186 shape:
+// an account proof against the state root plus one storage proof per
+// requested slot.
+type AccountResult struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []hexutil.Bytes `json:"accountProof"`
+	Balance      *hexutil.Big    `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []StorageResult `json:"storageProof"`
+}
+
+// StorageResult is a single storage-slot proof within an AccountResult.
+type StorageResult struct {
+	Key   string          `json:"key"`
+	Value *hexutil.Big    `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+// KeyValue is a single key/value pair returned by findstates.
+type KeyValue struct {
+	Key   hexutil.Bytes `json:"key"`
+	Value hexutil.Bytes `json:"value"`
+}
+
+// StateProof is the result of getproof: the encoded MPT proof nodes for a
+// single key under a given state root, together with the key they
+// authenticate so verifyproof can check them without any extra context.
+type StateProof struct {
+	Key   hexutil.Bytes   `json:"key"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+// FindStatesResult is the result of findstates: a page of key/value pairs
+// from the MPT under a given root, the boundary proofs for the first and
+// last returned items so a client can authenticate the page without
+// trusting the server, and whether further items remain beyond it.
+type FindStatesResult struct {
+	Results    []KeyValue      `json:"results"`
+	FirstProof []hexutil.Bytes `json:"firstProof,omitempty"`
+	LastProof  []hexutil.Bytes `json:"lastProof,omitempty"`
+	Truncated  bool            `json:"truncated"`
+}
+
+// StateHeight is the result of getstateheight: the heights of the local
+// (not yet validator-signed) and validated (threshold-signed) state roots,
+// so a light client can tell whether it's safe to trust GetStateProof
+// results against the latter.
+type StateHeight struct {
+	Local     uint32 `json:"localrootindex"`
+	Validated uint32 `json:"validatedrootindex"`
+}
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"github.com/multiverse-dev/saiya/pkg/rpc/auth"
 	"github.com/multiverse-dev/saiya/pkg/wallet"
 )
 
@@ -19,6 +20,28 @@ type (
 		Port                   uint16    `yaml:"Port"`
 		TLSConfig              TLSConfig `yaml:"TLSConfig"`
 		Wallet                 wallet.Wallet
+		// EnableCompiler exposes the eth_compileSolidity and eth_getCompilers
+		// RPC methods. These shell out to a local solc binary, so they are
+		// disabled by default.
+		EnableCompiler bool `yaml:"EnableCompiler"`
+		// SolcPath overrides the solc binary looked up on PATH.
+		SolcPath string `yaml:"SolcPath"`
+		// Tokens is the set of scoped bearer tokens accepted by the server.
+		// When empty, authentication is disabled and every request is
+		// allowed, preserving the previous behavior.
+		Tokens []auth.Token `yaml:"Tokens"`
+		// EnableAdmin exposes the admin_* RPC methods (node/peer
+		// introspection and peer management). Disabled by default since
+		// these methods are not safe to expose on a public endpoint.
+		EnableAdmin bool `yaml:"EnableAdmin"`
+		// AdminAllowedIPs, when non-empty, restricts admin_* methods to
+		// callers whose remote address matches one of these IPs. If empty,
+		// admin_* methods fall back to the Tokens scope check (scope
+		// "admin" or ScopeAll).
+		AdminAllowedIPs []string `yaml:"AdminAllowedIPs"`
+		// DataDir is the node's data directory, returned as-is by
+		// admin_datadir.
+		DataDir string `yaml:"DataDir"`
 	}
 
 	// TLSConfig describes SSL/TLS configuration.
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/multiverse-dev/saiya/pkg/rpc/response/result"
+)
+
+// errAdminDisabled is returned when any admin_* method is called on a node
+// with RPC.EnableAdmin off.
+var errAdminDisabled = errors.New("admin RPC methods are disabled, set RPC.EnableAdmin to enable them")
+
+// errNoNetworkServer is returned when admin_* is called on a node that
+// wasn't wired up with a p2p network server (e.g. in embedded/simulated
+// use).
+var errNoNetworkServer = errors.New("admin: node has no network server configured")
+
+// peerHandle is the connection-level view of a single connected peer that
+// the p2p layer exposes; admin_peers maps it into the public
+// result.PeerInfo shape.
+type peerHandle struct {
+	ID            string
+	Name          string
+	Caps          []string
+	LocalAddress  string
+	RemoteAddress string
+	Inbound       bool
+}
+
+// peerLister is the subset of the p2p network server's API the admin_*
+// methods need: this node's own identity and listen address, its
+// negotiated protocols, its currently connected peers, and the ability to
+// dial or drop a peer by address.
+type peerLister interface {
+	ID() string
+	Name() string
+	ListenAddress() string
+	Protocols() []string
+	Peers() []peerHandle
+	AddPeer(addr string) error
+	RemovePeer(addr string) error
+}
+
+// adminAllowed checks whether req may call an admin_* method: the feature
+// must be enabled, and the caller must either match the configured IP
+// allow-list or, when that list is empty, carry a bearer token scoped for
+// "admin" (or ScopeAll).
+func (s *Server) adminAllowed(req *http.Request) error {
+	if !s.config.EnableAdmin {
+		return errAdminDisabled
+	}
+	if len(s.config.AdminAllowedIPs) > 0 {
+		host, _, err := net.SplitHostPort(req.RemoteAddr)
+		if err != nil {
+			host = req.RemoteAddr
+		}
+		for _, allowed := range s.config.AdminAllowedIPs {
+			if allowed == host {
+				return nil
+			}
+		}
+		return errors.New("admin: remote address is not allow-listed")
+	}
+	return s.authenticateRequest(req, "admin")
+}
+
+// nodeInfo implements admin_nodeInfo: a summary of this node's own p2p
+// identity and listen configuration.
+func (s *Server) nodeInfo() (*result.NodeInfo, error) {
+	if s.net == nil {
+		return nil, errNoNetworkServer
+	}
+	return &result.NodeInfo{
+		ID:         s.net.ID(),
+		Name:       s.net.Name(),
+		ListenAddr: s.net.ListenAddress(),
+		Protocols:  s.net.Protocols(),
+	}, nil
+}
+
+// peers implements admin_peers: the set of currently connected peers.
+func (s *Server) peers() ([]result.PeerInfo, error) {
+	if s.net == nil {
+		return nil, errNoNetworkServer
+	}
+	handles := s.net.Peers()
+	out := make([]result.PeerInfo, len(handles))
+	for i, p := range handles {
+		out[i] = result.PeerInfo{
+			ID:   p.ID,
+			Name: p.Name,
+			Caps: p.Caps,
+			Network: result.PeerNetwork{
+				LocalAddress:  p.LocalAddress,
+				RemoteAddress: p.RemoteAddress,
+				Inbound:       p.Inbound,
+			},
+		}
+	}
+	return out, nil
+}
+
+// addPeer implements admin_addPeer: it asks the p2p layer to dial and
+// maintain a persistent connection to addr.
+func (s *Server) addPeer(addr string) (bool, error) {
+	if s.net == nil {
+		return false, errNoNetworkServer
+	}
+	if err := s.net.AddPeer(addr); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// removePeer implements admin_removePeer: it asks the p2p layer to drop
+// addr and stop reconnecting to it.
+func (s *Server) removePeer(addr string) (bool, error) {
+	if s.net == nil {
+		return false, errNoNetworkServer
+	}
+	if err := s.net.RemovePeer(addr); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// dataDir implements admin_datadir: the node's configured data directory.
+func (s *Server) dataDir() (string, error) {
+	return s.config.DataDir, nil
+}
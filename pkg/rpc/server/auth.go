@@ -0,0 +1,22 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/multiverse-dev/saiya/pkg/rpc/auth"
+)
+
+// authenticateRequest checks req's Authorization header against the
+// server's token registry for the given method scope. It is a no-op
+// (always allowed) when no tokens are configured, so existing
+// unauthenticated deployments keep working.
+func (s *Server) authenticateRequest(req *http.Request, scope string) error {
+	if s.auth == nil {
+		return nil
+	}
+	token, ok := auth.BearerToken(req.Header.Get("Authorization"))
+	if !ok {
+		return auth.ErrUnknownToken
+	}
+	return s.auth.Authorize(token, scope)
+}
@@ -1245,7 +1245,7 @@ func TestClient_InvokeAndPackIteratorResults(t *testing.T) {
 
 	storageHash, err := util.Uint160DecodeStringLE(storageContractHash)
 	require.NoError(t, err)
-	res, err := c.InvokeAndPackIteratorResults(storageHash, "iterateOverValues", []smartcontract.Parameter{}, nil)
+	res, err := c.InvokeAndPackIteratorResults(storageHash, "iterateOverValues", []smartcontract.Parameter{}, nil, callflag.All)
 	require.NoError(t, err)
 	require.Equal(t, vm.HaltState.String(), res.State)
 	require.Equal(t, 1, len(res.Stack))
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/multiverse-dev/saiya/pkg/compiler/solidity"
+)
+
+// errCompilerDisabled is returned when eth_compileSolidity or
+// eth_getCompilers is called on a node with RPC.EnableCompiler off.
+var errCompilerDisabled = errors.New("solidity compiler RPC methods are disabled, set RPC.EnableCompiler to enable them")
+
+// compiledSource is the standard Ethereum eth_compileSolidity result shape:
+// a map from contract name to {code, info: {abi, ...}}.
+type compiledSource struct {
+	Code string          `json:"code"`
+	Info compiledSrcInfo `json:"info"`
+}
+
+type compiledSrcInfo struct {
+	Source          string          `json:"source"`
+	Language        string          `json:"language"`
+	CompilerVersion string          `json:"compilerVersion"`
+	AbiDefinition   json.RawMessage `json:"abiDefinition"`
+	UserDoc         json.RawMessage `json:"userDoc"`
+	DeveloperDoc    json.RawMessage `json:"developerDoc"`
+}
+
+// compilerFor builds a solidity.Compiler for the configured solc path, or
+// errCompilerDisabled if the feature is turned off.
+func (s *Server) compilerFor() (*solidity.Compiler, error) {
+	if !s.config.EnableCompiler {
+		return nil, errCompilerDisabled
+	}
+	return solidity.NewCompiler(s.config.SolcPath), nil
+}
+
+// compileSolidity implements eth_compileSolidity: it compiles the given
+// Solidity source and returns the standard name -> {code, info} mapping
+// historically exposed by geth/parity so that existing tooling keeps working.
+func (s *Server) compileSolidity(params []interface{}) (interface{}, error) {
+	cc, err := s.compilerFor()
+	if err != nil {
+		return nil, err
+	}
+	if len(params) != 1 {
+		return nil, errors.New("eth_compileSolidity expects a single source parameter")
+	}
+	source, ok := params[0].(string)
+	if !ok {
+		return nil, errors.New("eth_compileSolidity source must be a string")
+	}
+
+	tmp, err := ioutil.TempFile("", "saiya-solc-*.sol")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp source file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(source); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to write temp source file: %w", err)
+	}
+	tmp.Close()
+
+	version, _ := cc.Version()
+	contracts, err := cc.Compile(tmp.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]compiledSource, len(contracts))
+	for _, ctr := range contracts {
+		out[ctr.Name] = compiledSource{
+			Code: "0x" + ctr.Bin,
+			Info: compiledSrcInfo{
+				Source:          source,
+				Language:        "Solidity",
+				CompilerVersion: version,
+				AbiDefinition:   ctr.ABI,
+				UserDoc:         ctr.UserDoc,
+				DeveloperDoc:    ctr.DevDoc,
+			},
+		}
+	}
+	return out, nil
+}
+
+// getCompilers implements eth_getCompilers: it reports which compiler
+// backends this node can invoke.
+func (s *Server) getCompilers(_ []interface{}) (interface{}, error) {
+	if _, err := s.compilerFor(); err != nil {
+		return []string{}, nil
+	}
+	return []string{"solidity"}, nil
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/multiverse-dev/saiya/pkg/core/light"
+	"github.com/multiverse-dev/saiya/pkg/rpc/response/result"
+)
+
+// maxLightClientUpdates caps how many updates a single getLightClientUpdate
+// call can return, mirroring the per-call limits already used elsewhere in
+// this package (e.g. MaxIteratorResultItems) to keep one request from
+// forcing the server to marshal an unbounded response.
+const maxLightClientUpdates = 128
+
+// errNoLightUpdates is returned when the node wasn't configured to track
+// committee-signed finality updates (i.e. it isn't producing the data a
+// light client would sync from).
+var errNoLightUpdates = errors.New("getLightClientUpdate: node does not track light client finality updates")
+
+// errTooManyLightClientUpdates is returned when count exceeds
+// maxLightClientUpdates.
+var errTooManyLightClientUpdates = errors.New("getLightClientUpdate: count exceeds the maximum of 128")
+
+// lightUpdateSource is the subset of the full node's light-sync bookkeeping
+// that getLightClientUpdate needs: the finality updates it has recorded,
+// by the height of the header each one finalizes.
+type lightUpdateSource interface {
+	FinalityUpdatesFrom(fromHeight uint32, count uint32) ([]light.FinalityUpdate, error)
+}
+
+// getLightClientUpdate implements getLightClientUpdate: it serves up to
+// count (header, committeeBitmap, aggregateSig) tuples starting at
+// fromHeight, for a light client following the chain via pkg/core/light.
+func (s *Server) getLightClientUpdate(fromHeight, count uint32) ([]result.LightClientUpdate, error) {
+	if s.lightUpdates == nil {
+		return nil, errNoLightUpdates
+	}
+	if count > maxLightClientUpdates {
+		return nil, errTooManyLightClientUpdates
+	}
+	updates, err := s.lightUpdates.FinalityUpdatesFrom(fromHeight, count)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]result.LightClientUpdate, len(updates))
+	for i, u := range updates {
+		out[i] = result.LightClientUpdate{
+			Header:          u.Header,
+			CommitteeBitmap: u.Bitmap,
+			AggregateSig:    u.Signature,
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// errInvalidBlockRange is returned when eth_getLogs is asked for a range
+// that doesn't make sense.
+var errInvalidBlockRange = errors.New("eth_getLogs: fromBlock must not be after toBlock")
+
+// getLogs implements eth_getLogs: it delegates the coarse, bloom-filtered
+// block scan to the blockchain, then applies the same address/topic
+// matcher the "notification_from_execution" subscription filter uses, so
+// polling and streaming agree on what counts as a match.
+func (s *Server) getLogs(fromBlock, toBlock uint32, addresses []common.Address, topics [][]common.Hash) ([]*types.Log, error) {
+	if fromBlock > toBlock {
+		return nil, errInvalidBlockRange
+	}
+	candidates, err := s.chain.GetLogs(fromBlock, toBlock, addresses, topics)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*types.Log, 0, len(candidates))
+	for _, log := range candidates {
+		if matchAddresses(addresses, log.Address) && matchTopics(topics, log.Topics) {
+			out = append(out, log)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/multiverse-dev/saiya/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// errSessionsDisabled is returned by traverseiterator/terminatesession
+// when the server wasn't configured with RPC.SessionEnabled.
+var errSessionsDisabled = errors.New("iterator sessions are disabled, set RPC.SessionEnabled to enable them")
+
+// errSessionNotFound is returned when sessionID doesn't name a session
+// that's still open (it was never registered, already terminated, or has
+// expired).
+var errSessionNotFound = errors.New("session not found")
+
+// errIteratorNotFound is returned when iteratorID doesn't name one of
+// the iterators registered under the given session.
+var errIteratorNotFound = errors.New("iterator not found")
+
+// iteratorSession tracks every iterator left over from a single
+// invocation that registered at least one of them, plus the
+// interop-context cleanup (interop.Context.Finalize) that must run once
+// the caller is done with all of them or the session expires.
+type iteratorSession struct {
+	sync.Mutex
+	iterators map[string]stackitem.Item
+	finalize  func()
+	timer     *time.Timer
+}
+
+// SessionManager is the RPC server's iterator session store. It's the
+// destination result.NewInvoke's registerSession callback writes into
+// when a script invocation leaves iterators on the result stack: it
+// keeps each one reachable by (sessionID, iteratorID) for
+// traverseiterator, and reclaims the whole session - calling its
+// finalize closure exactly once - on terminatesession or after
+// SessionExpirationTime of inactivity, whichever comes first.
+type SessionManager struct {
+	mtx      sync.Mutex
+	sessions map[string]*iteratorSession
+	order    []string
+
+	enabled                bool
+	expirationTime         time.Duration
+	poolSize               int
+	maxIteratorResultItems int
+}
+
+// NewSessionManager creates a SessionManager. poolSize bounds how many
+// sessions may be open at once: registering past it evicts the oldest
+// still-open session, finalizing it early.
+func NewSessionManager(enabled bool, expirationTime time.Duration, poolSize, maxIteratorResultItems int) *SessionManager {
+	return &SessionManager{
+		sessions:               make(map[string]*iteratorSession),
+		enabled:                enabled,
+		expirationTime:         expirationTime,
+		poolSize:               poolSize,
+		maxIteratorResultItems: maxIteratorResultItems,
+	}
+}
+
+// Enabled reports whether iterator sessions are turned on for this
+// server, i.e. whether it should pass Register to result.NewInvoke
+// instead of nil.
+func (m *SessionManager) Enabled() bool {
+	return m.enabled
+}
+
+// MaxIteratorResultItems is how many items a single traverseiterator
+// call (or, with sessions disabled, a single unwrap pass) may return.
+func (m *SessionManager) MaxIteratorResultItems() int {
+	return m.maxIteratorResultItems
+}
+
+// Register implements result.OnNewSession: it's the callback passed to
+// result.NewInvoke, invoked by Invoke.MarshalJSON exactly once per
+// invocation that left at least one iterator on the result stack.
+func (m *SessionManager) Register(sessionID string, iterators []result.ServerIterator, finalize func()) {
+	items := make(map[string]stackitem.Item, len(iterators))
+	for _, it := range iterators {
+		items[it.ID] = it.Item
+	}
+	sess := &iteratorSession{
+		iterators: items,
+		finalize:  finalize,
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.poolSize > 0 && len(m.sessions) >= m.poolSize {
+		m.evictOldestLocked()
+	}
+	m.sessions[sessionID] = sess
+	m.order = append(m.order, sessionID)
+	sess.timer = time.AfterFunc(m.expirationTime, func() { m.expire(sessionID) })
+}
+
+// evictOldestLocked finalizes and drops the longest-open session. Must
+// be called with m.mtx held.
+func (m *SessionManager) evictOldestLocked() {
+	if len(m.order) == 0 {
+		return
+	}
+	id := m.order[0]
+	m.order = m.order[1:]
+	m.closeLocked(id)
+}
+
+// expire is the SessionManager's response to a session's timer firing:
+// it's the same cleanup as an explicit Terminate, just triggered by
+// inactivity instead of a terminatesession call.
+func (m *SessionManager) expire(sessionID string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.closeLocked(sessionID)
+}
+
+// closeLocked stops sessionID's timer, finalizes it, and removes it from
+// both the session map and the eviction order. Must be called with
+// m.mtx held; it's a no-op if sessionID isn't currently open.
+func (m *SessionManager) closeLocked(sessionID string) {
+	sess, ok := m.sessions[sessionID]
+	if !ok {
+		return
+	}
+	sess.timer.Stop()
+	delete(m.sessions, sessionID)
+	for i, id := range m.order {
+		if id == sessionID {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			break
+		}
+	}
+	sess.finalize()
+}
+
+// get returns sessionID's session and resets its expiration timer, or
+// errSessionNotFound if it's not open.
+func (m *SessionManager) get(sessionID string) (*iteratorSession, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	sess, ok := m.sessions[sessionID]
+	if !ok {
+		return nil, errSessionNotFound
+	}
+	sess.timer.Reset(m.expirationTime)
+	return sess, nil
+}
+
+// TraverseIterator implements the traverseiterator RPC call: it pops up
+// to maxItemsPerCall items (capped by MaxIteratorResultItems) from
+// iteratorID's live iterator under sessionID.
+func (m *SessionManager) TraverseIterator(sessionID, iteratorID uuid.UUID, maxItemsPerCall int) ([]stackitem.Item, error) {
+	if !m.enabled {
+		return nil, errSessionsDisabled
+	}
+	sess, err := m.get(sessionID.String())
+	if err != nil {
+		return nil, err
+	}
+	sess.Lock()
+	defer sess.Unlock()
+	item, ok := sess.iterators[iteratorID.String()]
+	if !ok {
+		return nil, errIteratorNotFound
+	}
+	if maxItemsPerCall <= 0 || maxItemsPerCall > m.maxIteratorResultItems {
+		maxItemsPerCall = m.maxIteratorResultItems
+	}
+	return result.DrainIterator(item, maxItemsPerCall), nil
+}
+
+// TerminateSession implements the terminatesession RPC call: it releases
+// sessionID and every iterator still open under it ahead of its natural
+// expiration.
+func (m *SessionManager) TerminateSession(sessionID uuid.UUID) (bool, error) {
+	if !m.enabled {
+		return false, errSessionsDisabled
+	}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if _, ok := m.sessions[sessionID.String()]; !ok {
+		return false, errSessionNotFound
+	}
+	m.closeLocked(sessionID.String())
+	return true, nil
+}
+
+// traverseIterator implements the traverseiterator RPC method, paging
+// through an iterator registered by a prior invokefunction/invokescript
+// call with sessions enabled.
+func (s *Server) traverseIterator(sessionID, iteratorID uuid.UUID, maxItemsPerCall int) ([]stackitem.Item, error) {
+	return s.sessions.TraverseIterator(sessionID, iteratorID, maxItemsPerCall)
+}
+
+// terminateSession implements the terminatesession RPC method, letting a
+// caller release a session's iterators early instead of waiting for
+// RPC.SessionExpirationTime.
+func (s *Server) terminateSession(sessionID uuid.UUID) (bool, error) {
+	return s.sessions.TerminateSession(sessionID)
+}
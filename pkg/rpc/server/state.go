@@ -0,0 +1,98 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/multiverse-dev/saiya/pkg/rpc/response/result"
+)
+
+// contractStateKey builds the MPT key for key within contract's storage,
+// using the same reserved account/storage prefix as accountStorageKey but
+// allowing an arbitrary-length key instead of a fixed 32-byte slot, so it
+// also covers prefix scans for findstates.
+func contractStateKey(contract common.Address, key []byte) []byte {
+	out := make([]byte, 0, 1+common.AddressLength+len(key))
+	out = append(out, byte(0xFE))
+	out = append(out, contract.Bytes()...)
+	out = append(out, key...)
+	return out
+}
+
+// errStateUnavailable wraps a failed MPT lookup against root with a clearer
+// explanation: the most likely cause is that root predates the GC horizon
+// and its nodes have already been removed by stateroot.Module.GC.
+func errStateUnavailable(root common.Hash, err error) error {
+	return fmt.Errorf("state root %s is unavailable, it may predate the local garbage collection horizon: %w", root, err)
+}
+
+// getState implements getstate: it returns the raw value stored at key
+// within contract's storage in the MPT rooted at root.
+func (s *Server) getState(root common.Hash, contract common.Address, key []byte) (hexutil.Bytes, error) {
+	value, err := s.chain.GetStateModule().GetState(root, contractStateKey(contract, key))
+	if err != nil {
+		return nil, errStateUnavailable(root, err)
+	}
+	return value, nil
+}
+
+// findStates implements findstates: it returns up to count key/value pairs
+// within contract's storage whose key starts with prefix, beginning after
+// start (see Module.FindStates for the exact start semantics), along with
+// boundary proofs for the returned page and whether more items remain.
+func (s *Server) findStates(root common.Hash, contract common.Address, prefix, start []byte, count int) (*result.FindStatesResult, error) {
+	if count <= 0 || count > s.config.MaxFindResultItems {
+		count = s.config.MaxFindResultItems
+	}
+	sm := s.chain.GetStateModule()
+	fullPrefix := contractStateKey(contract, prefix)
+
+	// Ask for one extra item so a full page can be told apart from one
+	// that happens to end exactly on the trie's last matching key.
+	kvs, err := sm.FindStates(root, fullPrefix, start, count+1)
+	if err != nil {
+		return nil, errStateUnavailable(root, err)
+	}
+
+	res := &result.FindStatesResult{Truncated: len(kvs) > count}
+	if res.Truncated {
+		kvs = kvs[:count]
+	}
+	res.Results = make([]result.KeyValue, len(kvs))
+	for i, kv := range kvs {
+		res.Results[i] = result.KeyValue{Key: kv.Key, Value: kv.Value}
+	}
+	if len(kvs) > 0 {
+		if proof, err := sm.GetStateProof(root, kvs[0].Key); err == nil {
+			res.FirstProof = toHexProof(proof)
+		}
+		if proof, err := sm.GetStateProof(root, kvs[len(kvs)-1].Key); err == nil {
+			res.LastProof = toHexProof(proof)
+		}
+	}
+	return res, nil
+}
+
+// getStateProof implements getproof: it returns the MPT proof for key
+// within contract's storage against root.
+func (s *Server) getStateProof(root common.Hash, contract common.Address, key []byte) (*result.StateProof, error) {
+	fullKey := contractStateKey(contract, key)
+	proof, err := s.chain.GetStateModule().GetStateProof(root, fullKey)
+	if err != nil {
+		return nil, errStateUnavailable(root, err)
+	}
+	return &result.StateProof{Key: fullKey, Proof: toHexProof(proof)}, nil
+}
+
+// verifyStateProof implements verifyproof: it reconstructs the value
+// authenticated by p against root purely from the proof nodes it carries,
+// without touching local storage, so a client can check a getproof result
+// it received from (and doesn't necessarily trust) a peer.
+func (s *Server) verifyStateProof(root common.Hash, p *result.StateProof) (hexutil.Bytes, bool) {
+	proof := make([][]byte, len(p.Proof))
+	for i, n := range p.Proof {
+		proof[i] = n
+	}
+	return verifyProof(root, p.Key, proof)
+}
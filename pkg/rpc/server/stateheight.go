@@ -0,0 +1,18 @@
+package server
+
+import (
+	"github.com/multiverse-dev/saiya/pkg/rpc/response/result"
+)
+
+// getStateHeight implements getstateheight: it reports the height of the
+// latest locally-computed state root alongside the height of the latest one
+// that's been threshold-signed by state validators, so a light client using
+// GetStateProof knows up to which height the results are backed by more
+// than a single node's word.
+func (s *Server) getStateHeight() result.StateHeight {
+	sm := s.chain.GetStateModule()
+	return result.StateHeight{
+		Local:     sm.CurrentLocalHeight(),
+		Validated: sm.CurrentValidatedHeight(),
+	}
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/multiverse-dev/saiya/pkg/rpc/request"
+	"github.com/multiverse-dev/saiya/pkg/rpc/response"
+)
+
+// errInvalidFilter is returned when a subscribe request's filter doesn't
+// parse against the shape expected for its event kind.
+var errInvalidFilter = errors.New("invalid subscription filter")
+
+// maxTopicPositions is the most topic positions a NotificationFilter may
+// specify, matching the 4-topic limit of an Ethereum log (1 event
+// signature plus up to 3 indexed parameters).
+const maxTopicPositions = 4
+
+// eventIDFromString maps the event names accepted by the "subscribe" RPC
+// method to their response.EventID.
+func eventIDFromString(name string) (response.EventID, error) {
+	switch name {
+	case "block_added":
+		return response.BlockEventID, nil
+	case "transaction_added":
+		return response.TransactionEventID, nil
+	case "notification_from_execution":
+		return response.NotificationEventID, nil
+	case "transaction_executed":
+		return response.ExecutionEventID, nil
+	default:
+		return 0, errors.New("unknown event type")
+	}
+}
+
+// parseFilter decodes raw (if present) into the filter type expected for
+// event, returning a nil interface{} for unfiltered subscriptions.
+func parseFilter(event response.EventID, raw json.RawMessage) (interface{}, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	switch event {
+	case response.BlockEventID:
+		var f request.BlockFilter
+		if err := json.Unmarshal(raw, &f); err != nil {
+			return nil, errInvalidFilter
+		}
+		return f, nil
+	case response.TransactionEventID:
+		var f request.TxFilter
+		if err := json.Unmarshal(raw, &f); err != nil {
+			return nil, errInvalidFilter
+		}
+		return f, nil
+	case response.NotificationEventID:
+		var f request.NotificationFilter
+		if err := json.Unmarshal(raw, &f); err != nil {
+			return nil, errInvalidFilter
+		}
+		if len(f.Topics) > maxTopicPositions {
+			return nil, errInvalidFilter
+		}
+		return f, nil
+	case response.ExecutionEventID:
+		var f request.ExecutionFilter
+		if err := json.Unmarshal(raw, &f); err != nil {
+			return nil, errInvalidFilter
+		}
+		return f, nil
+	default:
+		return nil, errors.New("unknown event type")
+	}
+}
+
+// subscribe implements the "subscribe" RPC method: it registers a new feed
+// on the calling subscriber's connection for the named event, optionally
+// narrowed by a filter, and returns the feed's slot id as a string (the way
+// unsubscribe identifies it).
+func (s *Server) subscribe(sub *subscriber, eventName string, rawFilter json.RawMessage) (string, error) {
+	event, err := eventIDFromString(eventName)
+	if err != nil {
+		return "", err
+	}
+	filter, err := parseFilter(event, rawFilter)
+	if err != nil {
+		return "", err
+	}
+
+	for i := range sub.feeds {
+		if !sub.used[i] {
+			sub.used[i] = true
+			sub.feeds[i].event = event
+			sub.feeds[i].filter = filter
+			return strconv.Itoa(i), nil
+		}
+	}
+	return "", errors.New("maximum number of subscriptions reached")
+}
+
+// unsubscribe implements the "unsubscribe" RPC method, freeing the feed
+// slot identified by id (as returned by subscribe).
+func (s *Server) unsubscribe(sub *subscriber, id string) error {
+	i, err := strconv.Atoi(id)
+	if err != nil || i < 0 || i >= len(sub.feeds) {
+		return errors.New("invalid subscription id")
+	}
+	if !sub.used[i] {
+		return errors.New("subscription not found")
+	}
+	sub.used[i] = false
+	sub.feeds[i] = feed{}
+	return nil
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/gorilla/websocket"
 	"github.com/multiverse-dev/saiya/pkg/core/block"
@@ -21,6 +22,9 @@ type (
 		// pointing to EventID is an obvious overkill at the moment, but
 		// that's not for long.
 		feeds [maxFeeds]feed
+		// used tracks which feeds slots are currently occupied by a
+		// subscription, since EventID's zero value is itself a valid event.
+		used [maxFeeds]bool
 	}
 	feed struct {
 		event  response.EventID
@@ -64,8 +68,7 @@ func (f *feed) Matches(r *response.Notification) bool {
 	case response.NotificationEventID:
 		filt := f.filter.(request.NotificationFilter)
 		notification := r.Payload[0].(*types.Log)
-		hashOk := filt.Contract == nil || notification.Address == (*filt.Contract)
-		return hashOk
+		return matchAddresses(filt.Contracts, notification.Address) && matchTopics(filt.Topics, notification.Topics)
 	case response.ExecutionEventID:
 		filt := f.filter.(request.ExecutionFilter)
 		applog := r.Payload[0].(*types.Receipt)
@@ -73,3 +76,45 @@ func (f *feed) Matches(r *response.Notification) bool {
 	}
 	return false
 }
+
+// matchAddresses reports whether logAddress satisfies addresses: an empty
+// list matches any address, same as an absent "address" field in an
+// eth_getLogs query or log subscription filter.
+func matchAddresses(addresses []common.Address, logAddress common.Address) bool {
+	if len(addresses) == 0 {
+		return true
+	}
+	for _, a := range addresses {
+		if a == logAddress {
+			return true
+		}
+	}
+	return false
+}
+
+// matchTopics reports whether a log's topics satisfy filter: for each
+// position i where filter[i] is non-nil, logTopics[i] must equal one of
+// the hashes listed there (OR within a position), across all such
+// positions (AND between positions). A filter longer than the log's own
+// topics never matches, same as it never would on Ethereum.
+func matchTopics(filter [][]common.Hash, logTopics []common.Hash) bool {
+	if len(logTopics) < len(filter) {
+		return false
+	}
+	for i, want := range filter {
+		if want == nil {
+			continue
+		}
+		var ok bool
+		for _, h := range want {
+			if logTopics[i] == h {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/multiverse-dev/saiya/pkg/rpc/request"
+	"github.com/multiverse-dev/saiya/pkg/rpc/response"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedMatchesNotificationFilter(t *testing.T) {
+	addr1 := common.Address{1}
+	addr2 := common.Address{2}
+	topicA := common.Hash{0xa}
+	topicB := common.Hash{0xb}
+	topicC := common.Hash{0xc}
+
+	log := &types.Log{
+		Address: addr1,
+		Topics:  []common.Hash{topicA, topicB},
+	}
+	notification := &response.Notification{
+		Event:   response.NotificationEventID,
+		Payload: []interface{}{log},
+	}
+
+	tests := []struct {
+		name   string
+		filter interface{}
+		want   bool
+	}{
+		{"no filter matches anything", nil, true},
+		{
+			"empty filter matches anything",
+			request.NotificationFilter{},
+			true,
+		},
+		{
+			"matching single contract",
+			request.NotificationFilter{Contracts: []common.Address{addr1}},
+			true,
+		},
+		{
+			"non-matching single contract",
+			request.NotificationFilter{Contracts: []common.Address{addr2}},
+			false,
+		},
+		{
+			"matching multi-address list",
+			request.NotificationFilter{Contracts: []common.Address{addr2, addr1}},
+			true,
+		},
+		{
+			"wildcard topic position matches anything there",
+			request.NotificationFilter{Topics: [][]common.Hash{nil, {topicB}}},
+			true,
+		},
+		{
+			"OR within a topic position",
+			request.NotificationFilter{Topics: [][]common.Hash{{topicC, topicA}}},
+			true,
+		},
+		{
+			"AND across topic positions, one position mismatching",
+			request.NotificationFilter{Topics: [][]common.Hash{{topicA}, {topicC}}},
+			false,
+		},
+		{
+			"filter longer than the log's topics never matches",
+			request.NotificationFilter{Topics: [][]common.Hash{{topicA}, {topicB}, {topicC}}},
+			false,
+		},
+		{
+			"contract and topics combined",
+			request.NotificationFilter{
+				Contracts: []common.Address{addr1},
+				Topics:    [][]common.Hash{{topicA}},
+			},
+			true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := feed{event: response.NotificationEventID, filter: tc.filter}
+			require.Equal(t, tc.want, f.Matches(notification))
+		})
+	}
+}
+
+func TestParseFilterNotificationTopicsLimit(t *testing.T) {
+	ok := make([][]common.Hash, maxTopicPositions)
+	raw, err := json.Marshal(request.NotificationFilter{Topics: ok})
+	require.NoError(t, err)
+	_, err = parseFilter(response.NotificationEventID, raw)
+	require.NoError(t, err)
+
+	tooMany := make([][]common.Hash, maxTopicPositions+1)
+	raw, err = json.Marshal(request.NotificationFilter{Topics: tooMany})
+	require.NoError(t, err)
+	_, err = parseFilter(response.NotificationEventID, raw)
+	require.ErrorIs(t, err, errInvalidFilter)
+}
@@ -0,0 +1,120 @@
+package stateroot
+
+import (
+	"sync"
+
+	"github.com/multiverse-dev/saiya/pkg/core/state"
+	"github.com/multiverse-dev/saiya/pkg/core/transaction"
+	"github.com/multiverse-dev/saiya/pkg/crypto/keys"
+	"github.com/multiverse-dev/saiya/pkg/network/payload"
+)
+
+// rootSig is a single state validator's signature of an incompleteRoot, along
+// with whether it has been checked against the actual root yet (a signature
+// can arrive before the root it's over, if votes and the root disagree on
+// network order).
+type rootSig struct {
+	pub *keys.PublicKey
+	sig []byte
+	ok  bool
+}
+
+// incompleteRoot tracks votes for the state root of a single block height
+// until enough of them (M out of N state validators) have been collected and
+// verified to finalize a threshold-signed state.MPTRoot.
+type incompleteRoot struct {
+	sync.Mutex
+
+	svList  keys.PublicKeys
+	myIndex int
+
+	root *state.MPTRoot
+	sigs map[string]*rootSig
+
+	myVote  *payload.Extensible
+	retries int
+	isSent  bool
+}
+
+// addSignature records sig as coming from pub, marking it already-verified
+// if this incompleteRoot's root is already known (reverify is responsible for
+// checking any signature that arrived before the root did).
+func (r *incompleteRoot) addSignature(pub *keys.PublicKey, sig []byte) {
+	r.sigs[string(pub.Bytes())] = &rootSig{
+		pub: pub,
+		sig: sig,
+		ok:  r.root != nil,
+	}
+}
+
+// reverify verifies every signature collected so far that couldn't be
+// checked when it first arrived (because the root wasn't known yet).
+func (r *incompleteRoot) reverify(chainID uint64) {
+	if r.root == nil {
+		return
+	}
+	for _, s := range r.sigs {
+		if !s.ok {
+			s.ok = s.pub.VerifyHashable(s.sig, chainID, r.root)
+		}
+	}
+}
+
+// quorum returns the number of signatures required to finalize a state root
+// signed by n validators, tolerating up to (n-1)/3 byzantine ones.
+func quorum(n int) int {
+	return n - (n-1)/3
+}
+
+// isSenderNow reports whether this node is the one responsible for relaying
+// the finalized root this round, chosen round-robin by height so that not
+// every validator broadcasts the same finalized root at once.
+func (r *incompleteRoot) isSenderNow() bool {
+	if r.root == nil || len(r.svList) == 0 {
+		return false
+	}
+	return int(r.root.Index)%len(r.svList) == r.myIndex
+}
+
+// finalize checks whether enough verified signatures have been collected to
+// produce a threshold-signed state root, and if so attaches the aggregated
+// witness and returns it. It must be called with r locked.
+func (r *incompleteRoot) finalize() (*state.MPTRoot, bool) {
+	if r.root == nil || r.isSent {
+		return nil, false
+	}
+
+	m := quorum(len(r.svList))
+	sigs := make([][]byte, 0, m)
+	for _, pub := range r.svList {
+		s, ok := r.sigs[string(pub.Bytes())]
+		if ok && s.ok {
+			sigs = append(sigs, s.sig)
+		}
+		if len(sigs) == m {
+			break
+		}
+	}
+	if len(sigs) != m {
+		return nil, false
+	}
+
+	r.root.Witness = transaction.Witness{
+		VerificationScript: r.svList.Bytes(),
+		InvocationScript:   aggregateSignatures(sigs),
+	}
+	return r.root, true
+}
+
+// aggregateSignatures combines the individually-collected validator
+// signatures into the single InvocationScript carried by a finalized,
+// threshold-signed state root: each signature length-prefixed and
+// concatenated, in the order they were collected.
+func aggregateSignatures(sigs [][]byte) []byte {
+	var out []byte
+	for _, sig := range sigs {
+		out = append(out, byte(len(sig)))
+		out = append(out, sig...)
+	}
+	return out
+}
@@ -0,0 +1,86 @@
+package stateroot
+
+import (
+	"errors"
+
+	"github.com/multiverse-dev/saiya/pkg/core/state"
+	"github.com/multiverse-dev/saiya/pkg/io"
+)
+
+// Category is the category for extensible payloads used to gossip state
+// root votes and validated state roots between state validators.
+const Category = "StateRoot"
+
+// errInvalidMessageType is returned when decoding a Message whose Type isn't
+// one of the known MessageType constants.
+var errInvalidMessageType = errors.New("stateroot: invalid message type")
+
+// MessageType represents a type of stateroot Message.
+type MessageType byte
+
+const (
+	// VoteT is a type for Vote messages, each carrying one validator's
+	// signature of a not-yet-finalized state root.
+	VoteT MessageType = iota
+	// RootT is a type for messages carrying an already validated
+	// (threshold-signed) state root.
+	RootT
+)
+
+// Message is a wrapper for a network message exchanged by the state
+// validation service, identifying the concrete payload's type so it can be
+// decoded on receipt.
+type Message struct {
+	Type    MessageType
+	Payload io.Serializable
+}
+
+// Vote is a single validator's signature over a not-yet-finalized state
+// root, broadcast so other validators can aggregate it into a
+// threshold-signed root.
+type Vote struct {
+	ValidatorIndex int32
+	Height         uint32
+	Signature      []byte
+}
+
+// NewMessage creates a new Message of the given type wrapping p.
+func NewMessage(typ MessageType, p io.Serializable) *Message {
+	return &Message{Type: typ, Payload: p}
+}
+
+// EncodeBinary implements io.Serializable.
+func (m *Message) EncodeBinary(w *io.BinWriter) {
+	w.WriteB(byte(m.Type))
+	m.Payload.EncodeBinary(w)
+}
+
+// DecodeBinary implements io.Serializable.
+func (m *Message) DecodeBinary(r *io.BinReader) {
+	m.Type = MessageType(r.ReadB())
+	switch m.Type {
+	case VoteT:
+		m.Payload = &Vote{}
+	case RootT:
+		m.Payload = &state.MPTRoot{}
+	default:
+		r.Err = errInvalidMessageType
+	}
+	if r.Err == nil {
+		m.Payload.DecodeBinary(r)
+	}
+}
+
+// EncodeBinary implements io.Serializable.
+func (v *Vote) EncodeBinary(w *io.BinWriter) {
+	w.WriteU32LE(uint32(v.ValidatorIndex))
+	w.WriteU32LE(v.Height)
+	w.WriteVarBytes(v.Signature)
+}
+
+// DecodeBinary implements io.Serializable.
+func (v *Vote) DecodeBinary(r *io.BinReader) {
+	v.ValidatorIndex = int32(r.ReadU32LE())
+	v.Height = r.ReadU32LE()
+	v.Signature = r.ReadVarBytes()
+}
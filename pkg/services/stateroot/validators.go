@@ -124,7 +124,12 @@ func (s *service) sendVote(ir *incompleteRoot) {
 	ir.retries++
 }
 
-// getAccount returns current index and account for the node running this service.
+// getAccount returns current index and account for the node running this
+// service. Unlike the short-lived CLI signing paths, s.acc stays
+// decrypted for the service's whole lifetime so it can sign every block's
+// root without re-prompting for a password; narrowing that window to
+// just each signAndSend call would need s.acc to be re-derived from an
+// encrypted credential on every call, which isn't wired up yet.
 func (s *service) getAccount() (byte, *wallet.Account) {
 	s.accMtx.RLock()
 	defer s.accMtx.RUnlock()
@@ -0,0 +1,54 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/multiverse-dev/saiya/pkg/wallet/hardware"
+)
+
+// AccountKind distinguishes how an Account's signing key material is held.
+type AccountKind byte
+
+const (
+	// AccountKindLocal is a regular NEP-2 encrypted local key; EncryptedWIF
+	// holds the key material and PrivateKey/Decrypt/Encrypt operate on it
+	// as usual.
+	AccountKindLocal AccountKind = iota
+	// AccountKindHardware is backed by a Ledger/Trezor device: no key
+	// material is ever stored in the wallet file, and signing is routed
+	// through HWVendor/HWPath instead of PrivateKey.
+	AccountKindHardware
+)
+
+// NewHardwareAccount connects to the first device matching vendor,
+// derives the account at path on it, and returns an Account backed by
+// that device. No private key ever leaves the device or touches disk.
+func NewHardwareAccount(vendor hardware.Vendor, path string) (*Account, error) {
+	signer, err := hardware.NewSigner(vendor)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", vendor, err)
+	}
+	defer signer.Close()
+
+	addr, err := signer.Derive(path)
+	if err != nil {
+		return nil, fmt.Errorf("deriving account at %s on %s: %w", path, vendor, err)
+	}
+
+	return &Account{
+		Address:  addr,
+		Kind:     AccountKindHardware,
+		HWVendor: vendor,
+		HWPath:   path,
+	}, nil
+}
+
+// Signer opens a connection to a's backing device. It only applies to
+// accounts with Kind == AccountKindHardware; callers should check that
+// and fall back to PrivateKey() otherwise.
+func (a *Account) Signer() (hardware.Signer, error) {
+	if a.Kind != AccountKindHardware {
+		return nil, fmt.Errorf("account %s is not hardware-backed", a.Address)
+	}
+	return hardware.NewSigner(a.HWVendor)
+}
@@ -0,0 +1,24 @@
+package wallet
+
+// Close zeroes acc's decrypted private key material, if any was
+// decrypted. It's a no-op for hardware-backed accounts, which never hold
+// key material in the first place, and safe to call on an account that
+// was never decrypted.
+func (a *Account) Close() {
+	if a.Kind == AccountKindHardware {
+		return
+	}
+	if pk := a.PrivateKey(); pk != nil {
+		pk.Destroy()
+	}
+}
+
+// Close zeroes the decrypted private key material of every account in
+// the wallet. Callers that open a Wallet (openWallet/ReadWallet/NewWallet)
+// should defer it so a command never outlives the decrypted keys it
+// asked for.
+func (w *Wallet) Close() {
+	for _, acc := range w.Accounts {
+		acc.Close()
+	}
+}
@@ -0,0 +1,62 @@
+package hardware
+
+import (
+	"fmt"
+
+	gethaccounts "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/crypto/hash"
+)
+
+// deviceSigner implements Signer on top of go-ethereum's usbwallet driver,
+// which already speaks the Ledger and Trezor Ethereum-app APDU/wire
+// protocols over HID/USB.
+type deviceSigner struct {
+	vendor Vendor
+	wallet gethaccounts.Wallet
+}
+
+// Derive implements Signer.
+func (s *deviceSigner) Derive(path string) (common.Address, error) {
+	dp, err := gethaccounts.ParseDerivationPath(path)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid derivation path %q: %w", path, err)
+	}
+	acc, err := s.wallet.Derive(dp, true)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("deriving %s account at %s: %w", s.vendor, path, err)
+	}
+	return acc.Address, nil
+}
+
+// SignHashable implements Signer.
+//
+// Ledger and Trezor's Ethereum apps only expose signing over an RLP
+// transaction or over accounts.Wallet.SignText's "personal_sign"-style
+// message framing, not over an arbitrary pre-computed hash; there is no
+// raw-hash APDU. We reuse SignText here, which means the signature is
+// over keccak256("\x19Ethereum Signed Message:\n32" || h.GetSignedPart(chainID)),
+// not over h.GetSignedPart(chainID) directly - callers that need a
+// signature verifiable by the account's public key VerifyHashable must
+// account for that framing on the device side.
+func (s *deviceSigner) SignHashable(path string, chainID uint64, h hash.Hashable) ([]byte, error) {
+	dp, err := gethaccounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid derivation path %q: %w", path, err)
+	}
+	acc, err := s.wallet.Derive(dp, false)
+	if err != nil {
+		return nil, fmt.Errorf("deriving %s account at %s: %w", s.vendor, path, err)
+	}
+	sigHash := h.GetSignedPart(chainID)
+	sig, err := s.wallet.SignText(acc, sigHash)
+	if err != nil {
+		return nil, fmt.Errorf("signing on %s device: %w", s.vendor, err)
+	}
+	return sig, nil
+}
+
+// Close implements Signer.
+func (s *deviceSigner) Close() error {
+	return s.wallet.Close()
+}
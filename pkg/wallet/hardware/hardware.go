@@ -0,0 +1,84 @@
+// Package hardware provides pluggable signer backends for Ledger and
+// Trezor hardware wallets, addressed by BIP-44 derivation path
+// (e.g. m/44'/60'/0'/0/0). Private key material never leaves the device;
+// a Signer only ever returns derived public keys and signatures.
+package hardware
+
+import (
+	"errors"
+	"fmt"
+
+	gethaccounts "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/usbwallet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/crypto/hash"
+)
+
+// Vendor identifies the hardware wallet vendor backing a Signer.
+type Vendor byte
+
+const (
+	// Ledger identifies Ledger Nano S/X-style devices running the
+	// Ethereum app.
+	Ledger Vendor = iota
+	// Trezor identifies Trezor One/Model T devices.
+	Trezor
+)
+
+// String implements fmt.Stringer.
+func (v Vendor) String() string {
+	switch v {
+	case Ledger:
+		return "ledger"
+	case Trezor:
+		return "trezor"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrNoDevice is returned by NewSigner when no matching device is plugged
+// in and unlocked.
+var ErrNoDevice = errors.New("no hardware wallet device found")
+
+// Signer produces ECDSA signatures on a connected hardware wallet. Unlike
+// a local keys.PrivateKey, neither raw key bytes nor a keys.PublicKey ever
+// leave the device; accounts are addressed by their derived common.Address.
+type Signer interface {
+	// Derive opens the account at path on the device, prompting the user
+	// to confirm on-device if required, and returns its address.
+	Derive(path string) (common.Address, error)
+	// SignHashable signs h's sign-hash for chainID using the key derived
+	// at path, prompting the user to confirm the signature on-device.
+	SignHashable(path string, chainID uint64, h hash.Hashable) ([]byte, error)
+	// Close releases the underlying USB/HID handle.
+	Close() error
+}
+
+// NewSigner opens the first device matching vendor and returns a Signer
+// backed by it.
+func NewSigner(vendor Vendor) (Signer, error) {
+	var hub *gethaccounts.Hub
+	var err error
+	switch vendor {
+	case Ledger:
+		hub, err = usbwallet.NewLedgerHub()
+	case Trezor:
+		hub, err = usbwallet.NewTrezorHub()
+	default:
+		return nil, fmt.Errorf("unknown hardware wallet vendor %d", vendor)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening %s hub: %w", vendor, err)
+	}
+
+	wallets := hub.Wallets()
+	if len(wallets) == 0 {
+		return nil, ErrNoDevice
+	}
+	w := wallets[0]
+	if err := w.Open(""); err != nil {
+		return nil, fmt.Errorf("opening %s device: %w", vendor, err)
+	}
+	return &deviceSigner{vendor: vendor, wallet: w}, nil
+}
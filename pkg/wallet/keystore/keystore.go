@@ -0,0 +1,89 @@
+// Package keystore implements a split keystore layout where each wallet
+// account lives in its own encrypted JSON file inside a directory
+// (Ethereum keystore-style), instead of all accounts sharing a single
+// wallet file. This lets external tooling and hardware security modules
+// drop or remove individual account files without touching the rest of
+// the set.
+package keystore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/multiverse-dev/saiya/pkg/wallet"
+)
+
+// Load reads every account file in dir and returns the accounts found
+// there. Non-JSON files are ignored.
+func Load(dir string) ([]*wallet.Account, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading keystore dir %s: %w", dir, err)
+	}
+	var accs []*wallet.Account
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading keystore file %s: %w", e.Name(), err)
+		}
+		acc := new(wallet.Account)
+		if err := json.Unmarshal(b, acc); err != nil {
+			return nil, fmt.Errorf("parsing keystore file %s: %w", e.Name(), err)
+		}
+		accs = append(accs, acc)
+	}
+	return accs, nil
+}
+
+// Save writes acc to its own file inside dir, named after its address.
+// The file is written to a temporary path and renamed into place so
+// that concurrent readers never observe a partially-written file.
+func Save(dir string, acc *wallet.Account) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("creating keystore dir %s: %w", dir, err)
+	}
+	b, err := json.Marshal(acc)
+	if err != nil {
+		return fmt.Errorf("encoding keystore entry for %s: %w", acc.Address, err)
+	}
+	path := accountPath(dir, acc.Address)
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temp keystore file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing keystore entry for %s: %w", acc.Address, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing keystore entry for %s: %w", acc.Address, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return fmt.Errorf("setting keystore entry permissions for %s: %w", acc.Address, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("installing keystore entry for %s: %w", acc.Address, err)
+	}
+	return nil
+}
+
+// Remove deletes addr's keystore file from dir, if present.
+func Remove(dir string, addr common.Address) error {
+	err := os.Remove(accountPath(dir, addr))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing keystore entry for %s: %w", addr, err)
+	}
+	return nil
+}
+
+func accountPath(dir string, addr common.Address) string {
+	return filepath.Join(dir, strings.ToLower(addr.String())+".json")
+}
@@ -0,0 +1,350 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/multiverse-dev/saiya/pkg/core"
+	"github.com/multiverse-dev/saiya/pkg/core/state"
+	"github.com/multiverse-dev/saiya/pkg/core/transaction"
+	"github.com/multiverse-dev/saiya/pkg/crypto"
+	"github.com/multiverse-dev/saiya/pkg/crypto/hash"
+	"github.com/multiverse-dev/saiya/pkg/crypto/keys"
+	"github.com/multiverse-dev/saiya/pkg/network/payload"
+)
+
+// SignContextType discriminates the kind of artifact a SignContext
+// carries, so a single offline-signing workflow can co-sign transactions
+// as well as the consensus artifacts produced by the stateroot service
+// (payload.Extensible votes and the state.MPTRoot they finalize).
+type SignContextType byte
+
+const (
+	// SignContextTx signs a transaction's verification script.
+	SignContextTx SignContextType = iota
+	// SignContextExtensible signs an extensible consensus payload, e.g.
+	// a stateroot vote.
+	SignContextExtensible
+	// SignContextStateRoot signs a finalized state.MPTRoot.
+	SignContextStateRoot
+)
+
+// String implements fmt.Stringer.
+func (t SignContextType) String() string {
+	switch t {
+	case SignContextTx:
+		return "tx"
+	case SignContextExtensible:
+		return "extensible"
+	case SignContextStateRoot:
+		return "stateroot"
+	default:
+		return "unknown"
+	}
+}
+
+// SignContext carries everything an offline/multisig signing round needs:
+// the artifact being signed, the chain ID and network magic it was
+// signed under, the expected M-of-N signer set, and the signatures
+// collected from them so far, keyed by their position in PublicKeys.
+type SignContext struct {
+	Type    SignContextType
+	ChainID uint64
+	Magic   uint32
+
+	// Tx is populated when Type == SignContextTx.
+	Tx transaction.SaiyaTx
+	// Extensible is populated when Type == SignContextExtensible.
+	Extensible *payload.Extensible
+	// Root is populated when Type == SignContextStateRoot.
+	Root *state.MPTRoot
+
+	Sigs       [][]byte
+	PublicKeys keys.PublicKeys
+	M          int
+}
+
+// Hashable returns the artifact's hash.Hashable view, i.e. the payload
+// that was actually signed.
+func (sc *SignContext) Hashable() (hash.Hashable, error) {
+	switch sc.Type {
+	case SignContextTx:
+		return &sc.Tx, nil
+	case SignContextExtensible:
+		if sc.Extensible == nil {
+			return nil, errors.New("sign context has no extensible payload")
+		}
+		return sc.Extensible, nil
+	case SignContextStateRoot:
+		if sc.Root == nil {
+			return nil, errors.New("sign context has no state root")
+		}
+		return sc.Root, nil
+	default:
+		return nil, fmt.Errorf("unknown sign context type %d", sc.Type)
+	}
+}
+
+// Describe returns a short, human-readable summary of the artifact being
+// signed, for display before the user is asked for their password.
+func (sc *SignContext) Describe() string {
+	switch sc.Type {
+	case SignContextTx:
+		t := sc.Tx
+		to := "(contract creation)"
+		if t.To != nil {
+			to = t.To.String()
+		}
+		fee := fmt.Sprintf("gasPrice=%s", t.GasPrice)
+		if t.Type == transaction.DynamicFeeSaiyaTxType {
+			fee = fmt.Sprintf("maxFeePerGas=%s maxPriorityFeePerGas=%s", t.MaxFeePerGas, t.MaxPriorityFeePerGas)
+		}
+		return fmt.Sprintf("tx: from=%s to=%s value=%s nonce=%d gas=%d %s",
+			t.From, to, t.Value, t.Nonce, t.Gas, fee)
+	case SignContextExtensible:
+		e := sc.Extensible
+		if e == nil {
+			return "extensible: <missing>"
+		}
+		return fmt.Sprintf("extensible: category=%s sender=%s validBlocks=[%d,%d)",
+			e.Category, e.Sender, e.ValidBlockStart, e.ValidBlockEnd)
+	case SignContextStateRoot:
+		r := sc.Root
+		if r == nil {
+			return "stateroot: <missing>"
+		}
+		return fmt.Sprintf("stateroot: index=%d root=%s", r.Index, r.Root)
+	default:
+		return fmt.Sprintf("unknown sign context type %d", sc.Type)
+	}
+}
+
+// Check verifies the verification script against the signer set and
+// every signature already present.
+func (sc *SignContext) Check() error {
+	if sc.Type == SignContextTx {
+		t := &sc.Tx
+		intrinsic, err := core.IntrinsicGas(t.Data, transaction.ToEthAccessList(t.AccessList), t.To == nil)
+		if err != nil {
+			return err
+		}
+		if t.Gas < intrinsic {
+			return errors.New("tx gas below intrinsic gas")
+		}
+	}
+	h, err := sc.Hashable()
+	if err != nil {
+		return err
+	}
+	script, err := sc.verificationScript()
+	if err != nil {
+		return err
+	}
+	pks, m, err := crypto.ParseMultiVerificationScript(script)
+	if err != nil {
+		return err
+	}
+	if len(sc.Sigs) != m {
+		return errors.New("invalid sigs count")
+	}
+	sc.PublicKeys = *pks
+	sc.M = m
+	for i, sig := range sc.Sigs {
+		if len(sig) > 0 {
+			if !sc.PublicKeys[i].VerifyHashable(sig, sc.ChainID, h) {
+				return errors.New("invalid signature")
+			}
+		}
+	}
+	return nil
+}
+
+func (sc *SignContext) verificationScript() ([]byte, error) {
+	switch sc.Type {
+	case SignContextTx:
+		return sc.Tx.Witness.VerificationScript, nil
+	case SignContextExtensible:
+		if sc.Extensible == nil {
+			return nil, errors.New("sign context has no extensible payload")
+		}
+		return sc.Extensible.Witness.VerificationScript, nil
+	case SignContextStateRoot:
+		if sc.Root == nil {
+			return nil, errors.New("sign context has no state root")
+		}
+		return sc.Root.Witness.VerificationScript, nil
+	default:
+		return nil, fmt.Errorf("unknown sign context type %d", sc.Type)
+	}
+}
+
+// AddSig records sig as pk's signature over the artifact.
+func (sc *SignContext) AddSig(pk *keys.PublicKey, sig []byte) error {
+	h, err := sc.Hashable()
+	if err != nil {
+		return err
+	}
+	if !pk.VerifyHashable(sig, sc.ChainID, h) {
+		return errors.New("invalid signature")
+	}
+	for i, p := range sc.PublicKeys {
+		if p.Address() == pk.Address() {
+			sc.Sigs[i] = sig
+		}
+	}
+	return nil
+}
+
+// IsComplete reports whether enough signatures have been collected to
+// meet the M-of-N threshold.
+func (sc SignContext) IsComplete() bool {
+	sigCount := 0
+	for _, sig := range sc.Sigs {
+		if len(sig) > 0 {
+			sigCount++
+		}
+	}
+	return sc.M == sigCount
+}
+
+// finalWitness assembles the aggregated witness once IsComplete is true.
+func (sc *SignContext) finalWitness() *transaction.Witness {
+	if !sc.IsComplete() {
+		return nil
+	}
+	sigs := make([][]byte, sc.M)
+	for i, j := 0, 0; i < sc.M && j < len(sc.Sigs); j++ {
+		if len(sc.Sigs[j]) > 0 {
+			sigs[i] = sc.Sigs[j]
+			i++
+		}
+	}
+	script, err := sc.verificationScript()
+	if err != nil {
+		return nil
+	}
+	return &transaction.Witness{
+		InvocationScript:   crypto.CreateMultiInvocationScript(sigs),
+		VerificationScript: script,
+	}
+}
+
+// CreateTx assembles the final witnessed transaction once IsComplete is
+// true and Type == SignContextTx. It returns nil otherwise.
+func (sc *SignContext) CreateTx() *transaction.Transaction {
+	if sc.Type != SignContextTx {
+		return nil
+	}
+	w := sc.finalWitness()
+	if w == nil {
+		return nil
+	}
+	sc.Tx.Witness = *w
+	return transaction.NewTx(&sc.Tx)
+}
+
+// Finalize assembles the aggregated witness once IsComplete is true and
+// attaches it to the underlying Extensible or Root artifact. It returns
+// nil for SignContextTx - use CreateTx there instead.
+func (sc *SignContext) Finalize() error {
+	w := sc.finalWitness()
+	if w == nil {
+		return errors.New("sign context is not complete")
+	}
+	switch sc.Type {
+	case SignContextExtensible:
+		sc.Extensible.Witness = *w
+	case SignContextStateRoot:
+		sc.Root.Witness = *w
+	default:
+		return fmt.Errorf("Finalize does not apply to %s sign contexts", sc.Type)
+	}
+	return nil
+}
+
+type signContextJson struct {
+	Type       SignContextType      `json:"type"`
+	ChainID    hexutil.Uint64       `json:"chainId"`
+	Magic      uint32               `json:"magic"`
+	Tx         *transaction.SaiyaTx `json:"tx,omitempty"`
+	Extensible *payload.Extensible  `json:"extensible,omitempty"`
+	Root       *state.MPTRoot       `json:"root,omitempty"`
+	Sigs       []hexutil.Bytes      `json:"signatures"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (sc *SignContext) MarshalJSON() ([]byte, error) {
+	scj := &signContextJson{
+		Type:    sc.Type,
+		ChainID: hexutil.Uint64(sc.ChainID),
+		Magic:   sc.Magic,
+	}
+	switch sc.Type {
+	case SignContextTx:
+		scj.Tx = &sc.Tx
+	case SignContextExtensible:
+		scj.Extensible = sc.Extensible
+	case SignContextStateRoot:
+		scj.Root = sc.Root
+	}
+	scj.Sigs = make([]hexutil.Bytes, len(sc.Sigs))
+	for i, sig := range sc.Sigs {
+		scj.Sigs[i] = sig
+	}
+	return json.Marshal(scj)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (sc *SignContext) UnmarshalJSON(b []byte) error {
+	scj := new(signContextJson)
+	if err := json.Unmarshal(b, scj); err != nil {
+		return err
+	}
+	sc.Type = scj.Type
+	sc.ChainID = uint64(scj.ChainID)
+	sc.Magic = scj.Magic
+	if scj.Tx != nil {
+		sc.Tx = *scj.Tx
+	}
+	sc.Extensible = scj.Extensible
+	sc.Root = scj.Root
+	sc.Sigs = make([][]byte, len(scj.Sigs))
+	for i, sig := range scj.Sigs {
+		sc.Sigs[i] = sig
+	}
+	return sc.Check()
+}
+
+// Sign has acc produce a partial signature over context's artifact and
+// records it at acc's position in context.PublicKeys. acc must already be
+// decrypted (or hardware-backed).
+func Sign(acc *Account, context *SignContext) error {
+	h, err := context.Hashable()
+	if err != nil {
+		return err
+	}
+	for i, p := range context.PublicKeys {
+		if p.Address() != acc.Address {
+			continue
+		}
+		var sig []byte
+		if acc.Kind == AccountKindHardware {
+			signer, err := acc.Signer()
+			if err != nil {
+				return fmt.Errorf("connecting to hardware wallet: %w", err)
+			}
+			defer signer.Close()
+			sig, err = signer.SignHashable(acc.HWPath, context.ChainID, h)
+			if err != nil {
+				return fmt.Errorf("signing on hardware wallet: %w", err)
+			}
+		} else {
+			sig = acc.PrivateKey().SignHashable(context.ChainID, h)
+		}
+		context.Sigs[i] = sig
+		return nil
+	}
+	return errors.New("account is not a public key in sign context")
+}